@@ -4,10 +4,16 @@ package main
 import (
 	"flag"
 	"fmt"
+	"io"
 	"os"
 	"strings"
 
 	"github.com/sventorben/decider/internal/cli"
+	"github.com/sventorben/decider/internal/config"
+	"github.com/sventorben/decider/internal/git"
+	"github.com/sventorben/decider/internal/lsp"
+	"github.com/sventorben/decider/internal/mcp"
+	"github.com/sventorben/decider/internal/site"
 )
 
 // Version information, set via ldflags at build time.
@@ -17,7 +23,9 @@ var (
 	date    = "unknown"
 )
 
-const defaultADRDir = "docs/adr"
+// conf holds the project configuration from .decider.yaml merged onto the
+// built-in defaults. Flag defaults are taken from it, so flags override it.
+var conf = config.Default()
 
 func main() {
 	if len(os.Args) < 2 {
@@ -27,6 +35,12 @@ func main() {
 
 	command := os.Args[1]
 
+	switch command {
+	case "version", "help", "-h", "--help":
+	default:
+		loadConfig()
+	}
+
 	switch command {
 	case "init":
 		runInit(os.Args[2:])
@@ -38,10 +52,32 @@ func main() {
 		runList(os.Args[2:])
 	case "show":
 		runShow(os.Args[2:])
+	case "which":
+		runWhich(os.Args[2:])
+	case "context":
+		runContext(os.Args[2:])
+	case "export":
+		runExport(os.Args[2:])
+	case "site":
+		runSite(os.Args[2:])
+	case "graph":
+		runGraph(os.Args[2:])
 	case "check":
 		runCheck(os.Args[2:])
 	case "explain":
 		runExplain(os.Args[2:])
+	case "supersede":
+		runSupersede(os.Args[2:])
+	case "status":
+		runStatus(os.Args[2:])
+	case "config":
+		runConfig(os.Args[2:])
+	case "hooks":
+		runHooks(os.Args[2:])
+	case "mcp":
+		runMCP(os.Args[2:])
+	case "lsp":
+		runLSP(os.Args[2:])
 	case "version":
 		printVersion()
 	case "help", "-h", "--help":
@@ -65,12 +101,26 @@ Commands:
   index         Generate/update the ADR index
   list          List ADRs with optional filters
   show          Display details of an ADR
-  check         Validate ADRs or check diff applicability
+  which         Show the ADRs that govern files or directories
+  context       Pack the ADRs that govern paths for an agent, within a token budget
+  export        Write adopted ADRs into agent instruction files
+  site          Render ADRs as a static HTML site
+  graph         Output the supersession and relation graph of ADRs
+  check         Validate ADRs, check diff applicability, Go imports, scope coverage or overlaps
   explain       Explain why ADRs apply to changed files
+  supersede     Supersede an ADR with a new or existing one
+  status        Move an ADR to a new lifecycle status
+  config        Show the effective project configuration
+  hooks         Install git hooks that run decider's checks
+  mcp           Serve ADR queries to AI agents over MCP (stdio)
+  lsp           Run a language server for editors (stdio)
   version       Show version information
   help          Show this help message
 
-Run 'decider <command> -h' for more information on a command.`)
+Run 'decider <command> -h' for more information on a command.
+
+Defaults for all commands can be set in a .decider.yaml file, found by
+walking up from the working directory. Flags override it.`)
 }
 
 func printVersion() {
@@ -81,18 +131,14 @@ func printVersion() {
 
 func runInit(args []string) {
 	fs := flag.NewFlagSet("init", flag.ExitOnError)
-	dir := fs.String("dir", defaultADRDir, "ADR directory path")
-	format := fs.String("format", "text", "Output format (text|toon|json)")
+	dir := fs.String("dir", conf.ADRDir, "ADR directory path")
+	format := fs.String("format", conf.Format, "Output format (text|toon|json)")
 	if err := fs.Parse(args); err != nil {
 		fmt.Fprintf(os.Stderr, "error: %v\n", err)
 		os.Exit(1)
 	}
 
-	outputFormat, err := cli.ParseOutputFormat(*format)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "error: %v\n", err)
-		os.Exit(1)
-	}
+	outputFormat := parseFormat(*format)
 
 	cfg := &cli.InitConfig{
 		Dir:    *dir,
@@ -107,12 +153,13 @@ func runInit(args []string) {
 
 func runNew(args []string) {
 	fs := flag.NewFlagSet("new", flag.ExitOnError)
-	dir := fs.String("dir", defaultADRDir, "ADR directory path")
+	dir := fs.String("dir", conf.ADRDir, "ADR directory path")
 	tags := fs.String("tags", "", "Comma-separated tags")
 	paths := fs.String("paths", "", "Comma-separated scope paths (globs)")
 	status := fs.String("status", "proposed", "Initial status")
+	template := fs.String("template", conf.Template, "Template name (templates/<name>.md or built-in: full|lightweight|spike)")
 	noIndex := fs.Bool("no-index", false, "Skip updating index")
-	format := fs.String("format", "text", "Output format (text|toon|json)")
+	format := fs.String("format", conf.Format, "Output format (text|toon|json)")
 
 	fs.Usage = func() {
 		fmt.Println("Usage: decider new [options] <title>")
@@ -136,11 +183,7 @@ func runNew(args []string) {
 
 	title := strings.Join(fs.Args(), " ")
 
-	outputFormat, err := cli.ParseOutputFormat(*format)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "error: %v\n", err)
-		os.Exit(1)
-	}
+	outputFormat := parseFormat(*format)
 
 	var tagList []string
 	if *tags != "" {
@@ -157,14 +200,18 @@ func runNew(args []string) {
 	}
 
 	cfg := &cli.NewConfig{
-		Title:   title,
-		Dir:     *dir,
-		Tags:    tagList,
-		Paths:   pathList,
-		Status:  *status,
-		NoIndex: *noIndex,
-		Format:  outputFormat,
-		Output:  cli.NewOutput(outputFormat),
+		Title:    title,
+		Dir:      *dir,
+		Tags:     tagList,
+		Paths:    pathList,
+		Status:   *status,
+		Template: *template,
+		NoIndex:  *noIndex,
+		Format:   outputFormat,
+		Output:   cli.NewOutput(outputFormat),
+
+		IDPrefix:    conf.IDPrefix,
+		AllowedTags: conf.AllowedTags,
 	}
 
 	if _, err := cli.RunNew(cfg); err != nil {
@@ -175,19 +222,15 @@ func runNew(args []string) {
 
 func runIndex(args []string) {
 	fs := flag.NewFlagSet("index", flag.ExitOnError)
-	dir := fs.String("dir", defaultADRDir, "ADR directory path")
+	dir := fs.String("dir", conf.ADRDir, "ADR directory path")
 	check := fs.Bool("check", false, "Check if index is up-to-date (don't modify)")
-	format := fs.String("format", "text", "Output format (text|toon|json|yaml)")
+	format := fs.String("format", conf.Format, "Output format (text|toon|json|yaml)")
 	if err := fs.Parse(args); err != nil {
 		fmt.Fprintf(os.Stderr, "error: %v\n", err)
 		os.Exit(1)
 	}
 
-	outputFormat, err := cli.ParseOutputFormat(*format)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "error: %v\n", err)
-		os.Exit(1)
-	}
+	outputFormat := parseFormat(*format)
 
 	cfg := &cli.IndexConfig{
 		Dir:    *dir,
@@ -196,7 +239,7 @@ func runIndex(args []string) {
 		Output: cli.NewOutput(outputFormat),
 	}
 
-	_, err = cli.RunIndex(cfg)
+	_, err := cli.RunIndex(cfg)
 	if err != nil {
 		if *check {
 			os.Exit(2) // Lint failure exit code
@@ -208,21 +251,17 @@ func runIndex(args []string) {
 
 func runList(args []string) {
 	fs := flag.NewFlagSet("list", flag.ExitOnError)
-	dir := fs.String("dir", defaultADRDir, "ADR directory path")
+	dir := fs.String("dir", conf.ADRDir, "ADR directory path")
 	status := fs.String("status", "", "Filter by status")
 	tag := fs.String("tag", "", "Filter by tag")
 	path := fs.String("path", "", "Filter by scope path match")
-	format := fs.String("format", "text", "Output format (text|toon|json)")
+	format := fs.String("format", conf.Format, "Output format (text|toon|json)")
 	if err := fs.Parse(args); err != nil {
 		fmt.Fprintf(os.Stderr, "error: %v\n", err)
 		os.Exit(1)
 	}
 
-	outputFormat, err := cli.ParseOutputFormat(*format)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "error: %v\n", err)
-		os.Exit(1)
-	}
+	outputFormat := parseFormat(*format)
 
 	var tags []string
 	if *tag != "" {
@@ -246,8 +285,8 @@ func runList(args []string) {
 
 func runShow(args []string) {
 	fs := flag.NewFlagSet("show", flag.ExitOnError)
-	dir := fs.String("dir", defaultADRDir, "ADR directory path")
-	format := fs.String("format", "text", "Output format (text|toon|json)")
+	dir := fs.String("dir", conf.ADRDir, "ADR directory path")
+	format := fs.String("format", conf.Format, "Output format (text|toon|json)")
 
 	fs.Usage = func() {
 		fmt.Println("Usage: decider show [options] <ADR-ID|number|filename>")
@@ -269,20 +308,247 @@ func runShow(args []string) {
 		os.Exit(1)
 	}
 
-	outputFormat, err := cli.ParseOutputFormat(*format)
+	outputFormat := parseFormat(*format)
+
+	cfg := &cli.ShowConfig{
+		ID:     fs.Arg(0),
+		Dir:    *dir,
+		Format: outputFormat,
+		Output: cli.NewOutput(outputFormat),
+	}
+
+	if _, err := cli.RunShow(cfg); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func runWhich(args []string) {
+	fs := flag.NewFlagSet("which", flag.ExitOnError)
+	dir := fs.String("dir", conf.ADRDir, "ADR directory path")
+	format := fs.String("format", conf.Format, "Output format (text|toon|json)")
+
+	fs.Usage = func() {
+		fmt.Println("Usage: decider which [options] <path>...")
+		fmt.Println()
+		fmt.Println("Show the ADRs whose scope covers each path, with their constraints,")
+		fmt.Println("invariants and agent guidance. Directories are expanded recursively.")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if fs.NArg() < 1 {
+		fmt.Fprintln(os.Stderr, "error: at least one path is required")
+		fs.Usage()
+		os.Exit(1)
+	}
+
+	outputFormat := parseFormat(*format)
+
+	cfg := &cli.WhichConfig{
+		Paths:  fs.Args(),
+		Dir:    *dir,
+		Format: outputFormat,
+		Output: cli.NewOutput(outputFormat),
+	}
+
+	if _, err := cli.RunWhich(cfg); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func runContext(args []string) {
+	fs := flag.NewFlagSet("context", flag.ExitOnError)
+	dir := fs.String("dir", conf.ADRDir, "ADR directory path")
+	paths := fs.String("paths", "", "Files or directories to pack the governing ADRs for (comma-separated)")
+	budget := fs.Int("budget", 0, "Maximum estimated tokens; lower-priority sections are dropped to fit (0 for no limit)")
+	format := fs.String("format", "toon", "Output format (toon|json)")
+
+	fs.Usage = func() {
+		fmt.Println("Usage: decider context --paths <paths> [options]")
+		fmt.Println()
+		fmt.Println("Pack the constraints, invariants, Decision and Agent Guidance sections of")
+		fmt.Println("the adopted ADRs that govern the paths. With --budget, Decision sections,")
+		fmt.Println("then Agent Guidance, invariants and constraints are dropped until the pack")
+		fmt.Println("fits, and the output lists what was truncated.")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+
+	pathList := splitCSV(*paths)
+	if len(pathList) == 0 {
+		fmt.Fprintln(os.Stderr, "error: --paths is required")
+		fs.Usage()
+		os.Exit(1)
+	}
+
+	outputFormat := parseFormat(*format)
+
+	cfg := &cli.ContextConfig{
+		Paths:  pathList,
+		Dir:    *dir,
+		Budget: *budget,
+		Format: outputFormat,
+		Output: cli.NewOutput(outputFormat),
+	}
+
+	if _, err := cli.RunContext(cfg); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func runExport(args []string) {
+	if len(args) < 1 {
+		fmt.Fprintln(os.Stderr, "Usage: decider export <agents> [options]")
+		fmt.Fprintln(os.Stderr, "")
+		fmt.Fprintln(os.Stderr, "Subcommands:")
+		fmt.Fprintln(os.Stderr, "  agents  Write a managed block of adopted ADRs into AGENTS.md or CLAUDE.md")
+		os.Exit(1)
+	}
+
+	subCmd := args[0]
+
+	switch subCmd {
+	case "agents":
+		runExportAgents(args[1:])
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown export subcommand: %s\n", subCmd)
+		os.Exit(1)
+	}
+}
+
+func runExportAgents(args []string) {
+	fs := flag.NewFlagSet("export agents", flag.ExitOnError)
+	dir := fs.String("dir", conf.ADRDir, "ADR directory path")
+	out := fs.String("out", "AGENTS.md", "Agent instruction file to write the block into")
+	check := fs.Bool("check", false, "Check if the block is up-to-date (don't modify)")
+	format := fs.String("format", conf.Format, "Output format (text|toon|json)")
+
+	fs.Usage = func() {
+		fmt.Println("Usage: decider export agents [options]")
+		fmt.Println()
+		fmt.Println("Render the constraints, invariants and Agent Guidance of all adopted ADRs,")
+		fmt.Println("grouped by scope, between marker comments in an agent instruction file.")
+		fmt.Println("The rest of the file is left untouched.")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+
+	outputFormat := parseFormat(*format)
+
+	cfg := &cli.ExportAgentsConfig{
+		Dir:    *dir,
+		Out:    *out,
+		Check:  *check,
+		Format: outputFormat,
+		Output: cli.NewOutput(outputFormat),
+	}
+
+	result, err := cli.RunExportAgents(cfg)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "error: %v\n", err)
 		os.Exit(1)
 	}
 
-	cfg := &cli.ShowConfig{
-		ID:     fs.Arg(0),
+	if *check && !result.UpToDate {
+		os.Exit(2) // Lint failure exit code
+	}
+}
+
+func runSite(args []string) {
+	fs := flag.NewFlagSet("site", flag.ExitOnError)
+	dir := fs.String("dir", conf.ADRDir, "ADR directory path")
+	out := fs.String("out", "public", "Output directory")
+	title := fs.String("title", site.DefaultTitle, "Site title")
+	format := fs.String("format", conf.Format, "Output format (text|toon|json)")
+
+	fs.Usage = func() {
+		fmt.Println("Usage: decider site [options]")
+		fmt.Println()
+		fmt.Println("Render every ADR to HTML with an index, status badges, tag pages, a")
+		fmt.Println("supersession timeline and client-side search. The site works offline")
+		fmt.Println("from the file system, and the same ADRs always produce the same files.")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+
+	outputFormat := parseFormat(*format)
+
+	cfg := &cli.SiteConfig{
 		Dir:    *dir,
+		Out:    *out,
+		Title:  *title,
 		Format: outputFormat,
 		Output: cli.NewOutput(outputFormat),
 	}
 
-	if _, err := cli.RunShow(cfg); err != nil {
+	if _, err := cli.RunSite(cfg); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func runGraph(args []string) {
+	fs := flag.NewFlagSet("graph", flag.ExitOnError)
+	dir := fs.String("dir", conf.ADRDir, "ADR directory path")
+	tags := fs.String("tag", "", "Only include ADRs with any of these tags (comma-separated)")
+	root := fs.String("root", "", "Only include ADRs connected to this ADR (ID, number or filename)")
+	depth := fs.Int("depth", 0, "Maximum number of links from --root (0 for no limit)")
+	format := fs.String("format", "mermaid", "Output format (mermaid|dot|toon|json)")
+
+	fs.Usage = func() {
+		fmt.Println("Usage: decider graph [options]")
+		fmt.Println()
+		fmt.Println("Output the graph of supersedes, superseded_by and related_adrs links")
+		fmt.Println("between ADRs, with nodes colored by status and edges labeled by relation.")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+
+	outputFormat := parseFormat(*format, cli.FormatMermaid, cli.FormatDOT)
+
+	cfg := &cli.GraphConfig{
+		Dir:    *dir,
+		Tags:   splitCSV(*tags),
+		Root:   *root,
+		Depth:  *depth,
+		Format: outputFormat,
+		Output: cli.NewOutput(outputFormat),
+	}
+
+	if _, err := cli.RunGraph(cfg); err != nil {
 		fmt.Fprintf(os.Stderr, "error: %v\n", err)
 		os.Exit(1)
 	}
@@ -290,11 +556,14 @@ func runShow(args []string) {
 
 func runCheck(args []string) {
 	if len(args) < 1 {
-		fmt.Fprintln(os.Stderr, "Usage: decider check <adr|diff> [options]")
+		fmt.Fprintln(os.Stderr, "Usage: decider check <adr|diff|imports|scope|overlaps> [options]")
 		fmt.Fprintln(os.Stderr, "")
 		fmt.Fprintln(os.Stderr, "Subcommands:")
 		fmt.Fprintln(os.Stderr, "  adr     Validate ADR files")
 		fmt.Fprintln(os.Stderr, "  diff    Find ADRs applicable to git diff")
+		fmt.Fprintln(os.Stderr, "  imports Check Go imports against ADR import rules")
+		fmt.Fprintln(os.Stderr, "  scope   Find scope patterns matching no file and files no ADR covers")
+		fmt.Fprintln(os.Stderr, "  overlaps Find adopted ADRs that govern the same files")
 		os.Exit(1)
 	}
 
@@ -305,6 +574,12 @@ func runCheck(args []string) {
 		runCheckADR(args[1:])
 	case "diff":
 		runCheckDiff(args[1:])
+	case "imports":
+		runCheckImports(args[1:])
+	case "scope":
+		runCheckScope(args[1:])
+	case "overlaps":
+		runCheckOverlaps(args[1:])
 	default:
 		fmt.Fprintf(os.Stderr, "Unknown check subcommand: %s\n", subCmd)
 		os.Exit(1)
@@ -313,25 +588,25 @@ func runCheck(args []string) {
 
 func runCheckADR(args []string) {
 	fs := flag.NewFlagSet("check adr", flag.ExitOnError)
-	dir := fs.String("dir", defaultADRDir, "ADR directory path")
-	strict := fs.Bool("strict", false, "Treat warnings as errors (fail on missing rationale pattern)")
-	format := fs.String("format", "text", "Output format (text|toon|json)")
+	dir := fs.String("dir", conf.ADRDir, "ADR directory path")
+	strict := fs.Bool("strict", conf.Strict, "Treat warnings as errors (fail on missing rationale pattern)")
+	format := fs.String("format", conf.Format, "Output format (text|toon|json|sarif)")
 	if err := fs.Parse(args); err != nil {
 		fmt.Fprintf(os.Stderr, "error: %v\n", err)
 		os.Exit(1)
 	}
 
-	outputFormat, err := cli.ParseOutputFormat(*format)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "error: %v\n", err)
-		os.Exit(1)
-	}
+	outputFormat := parseFormat(*format, cli.FormatSARIF)
 
 	cfg := &cli.CheckADRConfig{
 		Dir:    *dir,
 		Strict: *strict,
 		Format: outputFormat,
 		Output: cli.NewOutput(outputFormat),
+
+		RequiredSections: conf.RequiredSections,
+		AllowedTags:      conf.AllowedTags,
+		IDPrefix:         conf.IDPrefix,
 	}
 
 	result, err := cli.RunCheckADR(cfg)
@@ -347,14 +622,19 @@ func runCheckADR(args []string) {
 
 func runCheckDiff(args []string) {
 	fs := flag.NewFlagSet("check diff", flag.ExitOnError)
-	dir := fs.String("dir", defaultADRDir, "ADR directory path")
-	base := fs.String("base", "", "Base ref for git diff (required)")
-	format := fs.String("format", "text", "Output format (text|toon|json)")
+	dir := fs.String("dir", conf.ADRDir, "ADR directory path")
+	changes := addChangeFlags(fs)
+	requireAck := fs.Bool("require-ack", false, "Fail unless commits in BASE..HEAD acknowledge every applicable ADR with an ADR-Ack trailer")
+	statuses := fs.String("status", strings.Join(cli.DefaultStatuses, ","), "Comma-separated statuses of the ADRs that apply; others are listed as historical context")
+	linkBase := fs.String("link-base", "", "URL prefix for ADR links in markdown output")
+	format := fs.String("format", conf.Format, "Output format (text|toon|json|sarif|github|markdown)")
 
 	fs.Usage = func() {
-		fmt.Println("Usage: decider check diff --base <ref> [options]")
+		fmt.Println("Usage: decider check diff (--base <ref> [--head <ref>] | --staged | --working-tree | --files <path>) [options]")
 		fmt.Println()
-		fmt.Println("Find ADRs applicable to files changed since <base>.")
+		fmt.Println("Find ADRs applicable to changed files and enforce their structured")
+		fmt.Println("constraints. Exits 2 on violations, or with --require-ack on")
+		fmt.Println("unacknowledged ADRs.")
 		fmt.Println()
 		fmt.Println("Options:")
 		fs.PrintDefaults()
@@ -365,41 +645,221 @@ func runCheckDiff(args []string) {
 		os.Exit(1)
 	}
 
-	if *base == "" {
-		fmt.Fprintln(os.Stderr, "error: --base is required")
-		fs.Usage()
+	files := changes.fileList(fs)
+	outputFormat := parseFormat(*format, cli.FormatSARIF, cli.FormatGitHub, cli.FormatMarkdown)
+
+	cfg := &cli.CheckDiffConfig{
+		Dir:         *dir,
+		Format:      outputFormat,
+		Output:      cli.NewOutput(outputFormat),
+		Base:        *changes.base,
+		Head:        *changes.head,
+		Staged:      *changes.staged,
+		WorkingTree: *changes.workingTree,
+		Files:       files,
+		LinkBase:    *linkBase,
+		RequireAck:  *requireAck,
+		Statuses:    splitCSV(*statuses),
+	}
+
+	result, err := cli.RunCheckDiff(cfg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
 		os.Exit(1)
 	}
 
-	outputFormat, err := cli.ParseOutputFormat(*format)
+	if len(result.Violations) > 0 || len(result.Unacknowledged) > 0 {
+		os.Exit(2) // Constraint violation exit code
+	}
+}
+
+func runCheckImports(args []string) {
+	fs := flag.NewFlagSet("check imports", flag.ExitOnError)
+	dir := fs.String("dir", conf.ADRDir, "ADR directory path")
+	format := fs.String("format", conf.Format, "Output format (text|toon|json)")
+
+	fs.Usage = func() {
+		fmt.Println("Usage: decider check imports [options]")
+		fmt.Println()
+		fmt.Println("Check Go imports in the repository against the imports rules of")
+		fmt.Println("adopted ADRs. Exits 2 on violations.")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+
+	outputFormat := parseFormat(*format)
+
+	cfg := &cli.CheckImportsConfig{
+		Dir:    *dir,
+		Format: outputFormat,
+		Output: cli.NewOutput(outputFormat),
+	}
+
+	result, err := cli.RunCheckImports(cfg)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "error: %v\n", err)
 		os.Exit(1)
 	}
 
-	cfg := &cli.CheckDiffConfig{
+	if !result.Valid {
+		os.Exit(2) // Lint failure exit code
+	}
+}
+
+func runCheckScope(args []string) {
+	fs := flag.NewFlagSet("check scope", flag.ExitOnError)
+	dir := fs.String("dir", conf.ADRDir, "ADR directory path")
+	sources := fs.String("sources", "", "Comma-separated globs of files an ADR should cover, '!' to exclude (default: all files outside the ADR directory)")
+	maxOrphaned := fs.Int("max-orphaned", -1, "Fail when more scope patterns match no file (-1: no limit)")
+	maxUncovered := fs.Int("max-uncovered", -1, "Fail when more source files are covered by no ADR (-1: no limit)")
+	format := fs.String("format", conf.Format, "Output format (text|toon|json)")
+
+	fs.Usage = func() {
+		fmt.Println("Usage: decider check scope [options]")
+		fmt.Println()
+		fmt.Println("Report scope patterns of adopted ADRs that match no file in the")
+		fmt.Println("working tree, and files no adopted ADR covers. Files ignored by")
+		fmt.Println("git are skipped. Exits 2 when a threshold is exceeded.")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+
+	outputFormat := parseFormat(*format)
+
+	cfg := &cli.CheckScopeConfig{
+		Dir:          *dir,
+		Sources:      splitCSV(*sources),
+		MaxOrphaned:  *maxOrphaned,
+		MaxUncovered: *maxUncovered,
+		Format:       outputFormat,
+		Output:       cli.NewOutput(outputFormat),
+	}
+
+	result, err := cli.RunCheckScope(cfg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if !result.Passed {
+		os.Exit(2) // Threshold exceeded
+	}
+}
+
+func runCheckOverlaps(args []string) {
+	fs := flag.NewFlagSet("check overlaps", flag.ExitOnError)
+	dir := fs.String("dir", conf.ADRDir, "ADR directory path")
+	strict := fs.Bool("strict", false, "Fail on overlaps between ADRs not linked via related_adrs or supersession")
+	format := fs.String("format", conf.Format, "Output format (text|toon|json)")
+
+	fs.Usage = func() {
+		fmt.Println("Usage: decider check overlaps [options]")
+		fmt.Println()
+		fmt.Println("Report adopted ADRs whose scopes cover the same files in the working")
+		fmt.Println("tree, flagging those not linked via related_adrs or supersession.")
+		fmt.Println("Exits 2 with --strict when unlinked overlaps are found.")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+
+	outputFormat := parseFormat(*format)
+
+	cfg := &cli.CheckOverlapsConfig{
 		Dir:    *dir,
-		Base:   *base,
+		Strict: *strict,
 		Format: outputFormat,
 		Output: cli.NewOutput(outputFormat),
 	}
 
-	if _, err := cli.RunCheckDiff(cfg); err != nil {
+	result, err := cli.RunCheckOverlaps(cfg)
+	if err != nil {
 		fmt.Fprintf(os.Stderr, "error: %v\n", err)
 		os.Exit(1)
 	}
+
+	if !result.Valid {
+		os.Exit(2) // Lint failure exit code
+	}
 }
 
 func runExplain(args []string) {
 	fs := flag.NewFlagSet("explain", flag.ExitOnError)
-	dir := fs.String("dir", defaultADRDir, "ADR directory path")
-	base := fs.String("base", "", "Base ref for git diff (required)")
-	format := fs.String("format", "text", "Output format (text|toon|json)")
+	dir := fs.String("dir", conf.ADRDir, "ADR directory path")
+	changes := addChangeFlags(fs)
+	statuses := fs.String("status", strings.Join(cli.DefaultStatuses, ","), "Comma-separated statuses of the ADRs that apply; others are listed as historical context")
+	linkBase := fs.String("link-base", "", "URL prefix for ADR links in markdown output")
+	format := fs.String("format", conf.Format, "Output format (text|toon|json|github|markdown)")
+
+	fs.Usage = func() {
+		fmt.Println("Usage: decider explain (--base <ref> [--head <ref>] | --staged | --working-tree | --files <path>) [options]")
+		fmt.Println()
+		fmt.Println("Explain why ADRs apply to changed files.")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+
+	files := changes.fileList(fs)
+	outputFormat := parseFormat(*format, cli.FormatGitHub, cli.FormatMarkdown)
+
+	cfg := &cli.ExplainConfig{
+		Dir:         *dir,
+		Format:      outputFormat,
+		Output:      cli.NewOutput(outputFormat),
+		Base:        *changes.base,
+		Head:        *changes.head,
+		Staged:      *changes.staged,
+		WorkingTree: *changes.workingTree,
+		Files:       files,
+		LinkBase:    *linkBase,
+		Statuses:    splitCSV(*statuses),
+	}
+
+	if _, err := cli.RunExplain(cfg); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func runSupersede(args []string) {
+	fs := flag.NewFlagSet("supersede", flag.ExitOnError)
+	dir := fs.String("dir", conf.ADRDir, "ADR directory path")
+	by := fs.String("by", "", "Existing ADR that supersedes the old one")
+	tags := fs.String("tags", "", "Comma-separated tags for the new ADR (default: inherited)")
+	paths := fs.String("paths", "", "Comma-separated scope paths for the new ADR (default: inherited)")
+	status := fs.String("status", "adopted", "Initial status of the new ADR")
+	template := fs.String("template", conf.Template, "Template name for the new ADR")
+	noIndex := fs.Bool("no-index", false, "Skip updating index")
+	format := fs.String("format", conf.Format, "Output format (text|toon|json)")
 
 	fs.Usage = func() {
-		fmt.Println("Usage: decider explain --base <ref> [options]")
+		fmt.Println("Usage: decider supersede [options] <ADR-ID> <new title>")
+		fmt.Println("       decider supersede [options] --by <ADR-ID> <ADR-ID>")
 		fmt.Println()
-		fmt.Println("Explain why ADRs apply to files changed since <base>.")
+		fmt.Println("Mark an ADR as superseded and link it with its replacement.")
 		fmt.Println()
 		fmt.Println("Options:")
 		fs.PrintDefaults()
@@ -410,27 +870,395 @@ func runExplain(args []string) {
 		os.Exit(1)
 	}
 
-	if *base == "" {
-		fmt.Fprintln(os.Stderr, "error: --base is required")
+	if fs.NArg() < 1 {
+		fmt.Fprintln(os.Stderr, "error: ADR identifier is required")
 		fs.Usage()
 		os.Exit(1)
 	}
 
-	outputFormat, err := cli.ParseOutputFormat(*format)
-	if err != nil {
+	title := strings.Join(fs.Args()[1:], " ")
+	if title == "" && *by == "" {
+		fmt.Fprintln(os.Stderr, "error: a new title or --by is required")
+		fs.Usage()
+		os.Exit(1)
+	}
+	if title != "" && *by != "" {
+		fmt.Fprintln(os.Stderr, "error: a new title and --by are mutually exclusive")
+		fs.Usage()
+		os.Exit(1)
+	}
+
+	outputFormat := parseFormat(*format)
+
+	cfg := &cli.SupersedeConfig{
+		ID:       fs.Arg(0),
+		Title:    title,
+		By:       *by,
+		Dir:      *dir,
+		Tags:     splitCSV(*tags),
+		Paths:    splitCSV(*paths),
+		Status:   *status,
+		Template: *template,
+		NoIndex:  *noIndex,
+		Format:   outputFormat,
+		Output:   cli.NewOutput(outputFormat),
+
+		IDPrefix:    conf.IDPrefix,
+		AllowedTags: conf.AllowedTags,
+	}
+
+	if _, err := cli.RunSupersede(cfg); err != nil {
 		fmt.Fprintf(os.Stderr, "error: %v\n", err)
 		os.Exit(1)
 	}
+}
 
-	cfg := &cli.ExplainConfig{
-		Dir:    *dir,
-		Base:   *base,
+func runStatus(args []string) {
+	fs := flag.NewFlagSet("status", flag.ExitOnError)
+	dir := fs.String("dir", conf.ADRDir, "ADR directory path")
+	date := fs.String("date", "", "Transition date YYYY-MM-DD (default: today)")
+	noIndex := fs.Bool("no-index", false, "Skip updating index")
+	format := fs.String("format", conf.Format, "Output format (text|toon|json)")
+
+	fs.Usage = func() {
+		fmt.Println("Usage: decider status [options] <ADR-ID> <status>")
+		fmt.Println()
+		fmt.Println("Move an ADR to a new status following the ADR lifecycle:")
+		fmt.Println("  proposed   -> adopted | rejected")
+		fmt.Println("  adopted    -> deprecated | superseded")
+		fmt.Println("  deprecated -> superseded")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if fs.NArg() < 2 {
+		fmt.Fprintln(os.Stderr, "error: ADR identifier and status are required")
+		fs.Usage()
+		os.Exit(1)
+	}
+
+	outputFormat := parseFormat(*format)
+
+	cfg := &cli.StatusConfig{
+		ID:      fs.Arg(0),
+		Status:  fs.Arg(1),
+		Date:    *date,
+		Dir:     *dir,
+		NoIndex: *noIndex,
+		Format:  outputFormat,
+		Output:  cli.NewOutput(outputFormat),
+	}
+
+	if _, err := cli.RunStatus(cfg); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func runConfig(args []string) {
+	if len(args) < 1 || args[0] != "show" {
+		fmt.Fprintln(os.Stderr, "Usage: decider config show [options]")
+		fmt.Fprintln(os.Stderr, "")
+		fmt.Fprintln(os.Stderr, "Subcommands:")
+		fmt.Fprintln(os.Stderr, "  show    Print the effective configuration")
+		os.Exit(1)
+	}
+
+	fs := flag.NewFlagSet("config show", flag.ExitOnError)
+	format := fs.String("format", conf.Format, "Output format (text|toon|json|yaml)")
+	if err := fs.Parse(args[1:]); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+
+	outputFormat := parseFormat(*format)
+
+	cfg := &cli.ConfigShowConfig{
+		Config: conf,
 		Format: outputFormat,
 		Output: cli.NewOutput(outputFormat),
 	}
 
-	if _, err := cli.RunExplain(cfg); err != nil {
+	if _, err := cli.RunConfigShow(cfg); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func runHooks(args []string) {
+	if len(args) < 1 {
+		fmt.Fprintln(os.Stderr, "Usage: decider hooks <install|uninstall|status> [options]")
+		fmt.Fprintln(os.Stderr, "")
+		fmt.Fprintln(os.Stderr, "Subcommands:")
+		fmt.Fprintln(os.Stderr, "  install    Install the pre-commit and commit-msg hooks")
+		fmt.Fprintln(os.Stderr, "  uninstall  Remove them and restore the hooks they replaced")
+		fmt.Fprintln(os.Stderr, "  status     Show which hooks are installed")
+		os.Exit(1)
+	}
+
+	subCmd := args[0]
+	if subCmd == "run" {
+		runHook(args[1:])
+		return
+	}
+
+	var run func(*cli.HooksConfig) (*cli.HooksResult, error)
+	switch subCmd {
+	case "install":
+		run = cli.RunHooksInstall
+	case "uninstall":
+		run = cli.RunHooksUninstall
+	case "status":
+		run = cli.RunHooksStatus
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown hooks subcommand: %s\n", subCmd)
+		os.Exit(1)
+	}
+
+	fs := flag.NewFlagSet("hooks "+subCmd, flag.ExitOnError)
+	only := fs.String("hooks", "", "Comma-separated hooks (default: pre-commit,commit-msg)")
+	format := fs.String("format", conf.Format, "Output format (text|toon|json)")
+
+	fs.Usage = func() {
+		fmt.Printf("Usage: decider hooks %s [options]\n", subCmd)
+		fmt.Println()
+		fmt.Println("The pre-commit hook validates staged ADR files and enforces structured")
+		fmt.Println("constraints on the staged changes. The commit-msg hook requires an")
+		fmt.Println("'ADR-Ack: ADR-NNNN' trailer for each adopted ADR with constraints that")
+		fmt.Println("covers a staged file. Existing hooks are kept and run first.")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args[1:]); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+
+	outputFormat := parseFormat(*format)
+
+	cfg := &cli.HooksConfig{
+		Hooks:  splitCSV(*only),
+		Format: outputFormat,
+		Output: cli.NewOutput(outputFormat),
+	}
+
+	if _, err := run(cfg); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// runHook is called by the installed hook scripts.
+func runHook(args []string) {
+	if len(args) < 1 {
+		fmt.Fprintln(os.Stderr, "Usage: decider hooks run <pre-commit|commit-msg> [hook arguments]")
+		os.Exit(1)
+	}
+
+	cfg := &cli.HookRunConfig{
+		Hook:             args[0],
+		Args:             args[1:],
+		Dir:              conf.ADRDir,
+		Output:           cli.NewOutput(cli.FormatText),
+		Strict:           conf.Strict,
+		RequiredSections: conf.RequiredSections,
+		AllowedTags:      conf.AllowedTags,
+		IDPrefix:         conf.IDPrefix,
+	}
+
+	result, err := cli.RunHook(cfg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if !result.Passed {
+		os.Exit(2)
+	}
+}
+
+func runMCP(args []string) {
+	fs := flag.NewFlagSet("mcp", flag.ExitOnError)
+	dir := fs.String("dir", conf.ADRDir, "ADR directory path")
+	defaultFormat := conf.Format
+	if defaultFormat != "json" {
+		defaultFormat = "toon"
+	}
+	format := fs.String("format", defaultFormat, "Default tool payload format (toon|json)")
+
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: decider mcp [options]")
+		fmt.Fprintln(os.Stderr)
+		fmt.Fprintln(os.Stderr, "Run a Model Context Protocol server on stdin/stdout exposing the")
+		fmt.Fprintln(os.Stderr, "tools list_adrs, show_adr, adrs_for_paths, check_diff and validate.")
+		fmt.Fprintln(os.Stderr)
+		fmt.Fprintln(os.Stderr, "Options:")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+
+	outputFormat, err := cli.ParseOutputFormat(*format)
+	if err != nil || (outputFormat != cli.FormatTOON && outputFormat != cli.FormatJSON) {
+		fmt.Fprintf(os.Stderr, "error: invalid format %q (valid: toon, json)\n", *format)
+		os.Exit(1)
+	}
+
+	server := mcp.NewServer(mcp.Config{
+		Dir:     *dir,
+		Format:  outputFormat,
+		Version: version,
+
+		RequiredSections: conf.RequiredSections,
+		AllowedTags:      conf.AllowedTags,
+		IDPrefix:         conf.IDPrefix,
+	})
+	if err := server.Serve(os.Stdin, os.Stdout); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func runLSP(args []string) {
+	fs := flag.NewFlagSet("lsp", flag.ExitOnError)
+	dir := fs.String("dir", conf.ADRDir, "ADR directory path")
+
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: decider lsp [options]")
+		fmt.Fprintln(os.Stderr)
+		fmt.Fprintln(os.Stderr, "Run a Language Server Protocol server on stdin/stdout. It reports")
+		fmt.Fprintln(os.Stderr, "validation diagnostics for ADR files, completes and resolves ADR IDs,")
+		fmt.Fprintln(os.Stderr, "and shows the adopted ADRs covering a source file on hover.")
+		fmt.Fprintln(os.Stderr)
+		fmt.Fprintln(os.Stderr, "Options:")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+
+	server := lsp.NewServer(lsp.Config{
+		Dir:     *dir,
+		Version: version,
+
+		RequiredSections: conf.RequiredSections,
+		AllowedTags:      conf.AllowedTags,
+		IDPrefix:         conf.IDPrefix,
+	})
+	if err := server.Serve(os.Stdin, os.Stdout); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// loadConfig discovers and loads .decider.yaml from the working directory.
+func loadConfig() {
+	loaded, err := config.LoadFromDir(".")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+	if format, err := cli.ParseOutputFormat(loaded.Format); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %s: %v\n", loaded.Source, err)
+		os.Exit(1)
+	} else if format.IsReport() {
+		fmt.Fprintf(os.Stderr, "error: %s: format %q can only be selected with --format\n", loaded.Source, loaded.Format)
+		os.Exit(1)
+	}
+	conf = loaded
+}
+
+// splitCSV splits a comma-separated flag value, returning nil when empty.
+func splitCSV(s string) []string {
+	if s == "" {
+		return nil
+	}
+	var result []string
+	for _, item := range strings.Split(s, ",") {
+		result = append(result, strings.TrimSpace(item))
+	}
+	return result
+}
+
+// parseFormat parses a --format value, exiting on invalid values. Report
+// formats are accepted only when the command lists them in reports.
+func parseFormat(s string, reports ...cli.OutputFormat) cli.OutputFormat {
+	format, err := cli.ParseOutputFormat(s)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+	if format.IsReport() {
+		for _, r := range reports {
+			if r == format {
+				return format
+			}
+		}
+		fmt.Fprintf(os.Stderr, "error: format %q is not supported by this command\n", s)
+		os.Exit(1)
+	}
+	return format
+}
+
+// changeFlags select the changed files for check diff and explain.
+type changeFlags struct {
+	base, head, files   *string
+	staged, workingTree *bool
+}
+
+func addChangeFlags(fs *flag.FlagSet) *changeFlags {
+	return &changeFlags{
+		base:        fs.String("base", "", "Base git ref to diff against"),
+		head:        fs.String("head", "", "Head git ref; diffs BASE..HEAD instead of the working tree"),
+		staged:      fs.Bool("staged", false, "Use staged changes (against HEAD, or --base when set)"),
+		workingTree: fs.Bool("working-tree", false, "Use unstaged changes to tracked files"),
+		files:       fs.String("files", "", "Read changed paths, one per line, from a file (- for stdin)"),
+	}
+}
+
+// fileList returns the paths read with --files, or nil when git lists the
+// changes. It exits when no source of changes is given.
+func (c *changeFlags) fileList(fs *flag.FlagSet) []string {
+	if *c.files == "" {
+		if *c.base == "" && !*c.staged && !*c.workingTree {
+			fmt.Fprintln(os.Stderr, "error: one of --base, --staged, --working-tree or --files is required")
+			fs.Usage()
+			os.Exit(1)
+		}
+		return nil
+	}
+	if *c.base != "" || *c.head != "" || *c.staged || *c.workingTree {
+		fmt.Fprintln(os.Stderr, "error: --files cannot be combined with --base, --head, --staged or --working-tree")
+		os.Exit(1)
+	}
+
+	var r io.Reader = os.Stdin
+	if *c.files != "-" {
+		f, err := os.Open(*c.files)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "error: %v\n", err)
+			os.Exit(1)
+		}
+		defer func() { _ = f.Close() }()
+		r = f
+	}
+	files, err := git.ReadFileList(r)
+	if err != nil {
 		fmt.Fprintf(os.Stderr, "error: %v\n", err)
 		os.Exit(1)
 	}
+	return files
 }
@@ -0,0 +1,223 @@
+package adr
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Constraint is an entry of the constraints list. It is either free text,
+// written as a plain string, or a machine-checkable rule written as a mapping
+// with exactly one rule key:
+//
+//	constraints:
+//	  - "Use the repository pattern"
+//	  - forbid_import: database/sql
+//	    paths: ["src/handlers/**"]
+//	    message: "Handlers must go through the repository layer"
+//	  - imports:
+//	      to: ["database/sql"]
+//	      allow_from: ["internal/db/**"]
+type Constraint struct {
+	Text string `yaml:"-"` // Free-text constraint
+
+	ForbidImport string      `yaml:"forbid_import,omitempty"` // Import path (or prefix) that must not be imported
+	ForbidRegex  string      `yaml:"forbid_regex,omitempty"`  // Regular expression that must not match any line
+	RequireFile  string      `yaml:"require_file,omitempty"`  // Glob; a matching file must change alongside
+	ForbidPath   string      `yaml:"forbid_path,omitempty"`   // Glob; matching files must not be added or modified
+	Imports      *ImportRule `yaml:"imports,omitempty"`       // Allowed and forbidden Go import edges
+	Paths        []string    `yaml:"paths,omitempty"`         // Files the rule applies to; the ADR scope when empty
+	Message      string      `yaml:"message,omitempty"`       // Explanation shown for violations
+}
+
+// ImportRule restricts which Go packages may import the packages matching To.
+// Package globs match either the full import path or, for packages of the
+// module being checked, the path relative to the module root (e.g.
+// "internal/db/**"). An import of a To package is a violation when the
+// importing package matches ForbidFrom, or when AllowFrom is set and the
+// importing package matches none of it.
+type ImportRule struct {
+	To         []string `yaml:"to" json:"to"`
+	AllowFrom  []string `yaml:"allow_from,omitempty" json:"allow_from,omitempty"`
+	ForbidFrom []string `yaml:"forbid_from,omitempty" json:"forbid_from,omitempty"`
+}
+
+var importRuleKeys = map[string]bool{"to": true, "allow_from": true, "forbid_from": true}
+
+// importRuleFields is used to decode ImportRule without recursing into
+// ImportRule.UnmarshalYAML.
+type importRuleFields ImportRule
+
+// UnmarshalYAML rejects unknown keys so a typo does not disable the rule.
+func (r *ImportRule) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind != yaml.MappingNode {
+		return fmt.Errorf("line %d: imports must be a mapping", value.Line)
+	}
+	for i := 0; i+1 < len(value.Content); i += 2 {
+		if key := value.Content[i].Value; !importRuleKeys[key] {
+			return fmt.Errorf("line %d: unknown imports key %q", value.Content[i].Line, key)
+		}
+	}
+	var fields importRuleFields
+	if err := value.Decode(&fields); err != nil {
+		return err
+	}
+	*r = ImportRule(fields)
+	return nil
+}
+
+// String describes the rule, e.g. "only internal/db/** may import database/sql".
+func (r ImportRule) String() string {
+	to := strings.Join(r.To, ", ")
+	var parts []string
+	if len(r.AllowFrom) > 0 {
+		parts = append(parts, fmt.Sprintf("only %s may import %s", strings.Join(r.AllowFrom, ", "), to))
+	}
+	if len(r.ForbidFrom) > 0 {
+		parts = append(parts, fmt.Sprintf("%s must not import %s", strings.Join(r.ForbidFrom, ", "), to))
+	}
+	return strings.Join(parts, "; ")
+}
+
+// Constraint rule kinds.
+const (
+	RuleForbidImport = "forbid_import"
+	RuleForbidRegex  = "forbid_regex"
+	RuleRequireFile  = "require_file"
+	RuleForbidPath   = "forbid_path"
+	RuleImports      = "imports"
+)
+
+var constraintKeys = map[string]bool{
+	RuleForbidImport: true,
+	RuleForbidRegex:  true,
+	RuleRequireFile:  true,
+	RuleForbidPath:   true,
+	RuleImports:      true,
+	"paths":          true,
+	"message":        true,
+}
+
+// constraintRule is used to decode the mapping form without recursing into
+// Constraint.UnmarshalYAML.
+type constraintRule Constraint
+
+// UnmarshalYAML accepts either a string or a rule mapping.
+func (c *Constraint) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		*c = Constraint{Text: value.Value}
+		return nil
+	}
+	if value.Kind != yaml.MappingNode {
+		return fmt.Errorf("line %d: constraint must be a string or a mapping", value.Line)
+	}
+	for i := 0; i+1 < len(value.Content); i += 2 {
+		if key := value.Content[i].Value; !constraintKeys[key] {
+			return fmt.Errorf("line %d: unknown constraint key %q", value.Content[i].Line, key)
+		}
+	}
+	var rule constraintRule
+	if err := value.Decode(&rule); err != nil {
+		return err
+	}
+	*c = Constraint(rule)
+	return nil
+}
+
+// MarshalYAML writes free-text constraints as plain strings.
+func (c Constraint) MarshalYAML() (interface{}, error) {
+	if !c.IsRule() {
+		return c.Text, nil
+	}
+	return constraintRule(c), nil
+}
+
+// IsRule reports whether the constraint is machine-checkable.
+func (c Constraint) IsRule() bool {
+	return c.ForbidImport != "" || c.ForbidRegex != "" || c.RequireFile != "" || c.ForbidPath != "" || c.Imports != nil
+}
+
+// Rule returns the rule kind and its argument, or empty strings for free text.
+func (c Constraint) Rule() (kind, arg string) {
+	switch {
+	case c.ForbidImport != "":
+		return RuleForbidImport, c.ForbidImport
+	case c.ForbidRegex != "":
+		return RuleForbidRegex, c.ForbidRegex
+	case c.RequireFile != "":
+		return RuleRequireFile, c.RequireFile
+	case c.ForbidPath != "":
+		return RuleForbidPath, c.ForbidPath
+	case c.Imports != nil:
+		return RuleImports, c.Imports.String()
+	}
+	return "", ""
+}
+
+// String returns the constraint as shown to humans and agents: the text of a
+// free-text constraint, or the message (or rule) of a structured one.
+func (c Constraint) String() string {
+	if !c.IsRule() {
+		return c.Text
+	}
+	if c.Message != "" {
+		return c.Message
+	}
+	kind, arg := c.Rule()
+	return fmt.Sprintf("%s: %s", kind, arg)
+}
+
+// ConstraintStrings returns the display form of each constraint.
+func ConstraintStrings(constraints []Constraint) []string {
+	if constraints == nil {
+		return nil
+	}
+	out := make([]string, len(constraints))
+	for i, c := range constraints {
+		out[i] = c.String()
+	}
+	return out
+}
+
+// validateConstraints reports malformed structured constraints.
+func validateConstraints(constraints []Constraint, result *ValidationResult) {
+	for i, c := range constraints {
+		field := fmt.Sprintf("constraints[%d]", i)
+		if !c.IsRule() {
+			if c.Text == "" && (len(c.Paths) > 0 || c.Message != "") {
+				result.addErrorCode(field, fmt.Sprintf("rule must set one of %s, %s, %s, %s, %s",
+					RuleForbidImport, RuleForbidRegex, RuleRequireFile, RuleForbidPath, RuleImports), "invalid_constraint")
+			}
+			continue
+		}
+
+		kinds := 0
+		for _, v := range []string{c.ForbidImport, c.ForbidRegex, c.RequireFile, c.ForbidPath} {
+			if v != "" {
+				kinds++
+			}
+		}
+		if c.Imports != nil {
+			kinds++
+		}
+		if kinds > 1 {
+			result.addErrorCode(field, "rule must set exactly one rule key", "invalid_constraint")
+			continue
+		}
+		if c.ForbidRegex != "" {
+			if _, err := regexp.Compile(c.ForbidRegex); err != nil {
+				result.addErrorCode(field, fmt.Sprintf("invalid forbid_regex: %v", err), "invalid_constraint")
+			}
+		}
+		if c.Imports != nil {
+			if len(c.Imports.To) == 0 {
+				result.addErrorCode(field, "imports rule must list the packages in to", "invalid_constraint")
+			}
+			if len(c.Imports.AllowFrom) == 0 && len(c.Imports.ForbidFrom) == 0 {
+				result.addErrorCode(field, "imports rule must set allow_from or forbid_from", "invalid_constraint")
+			}
+		}
+	}
+}
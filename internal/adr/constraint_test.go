@@ -0,0 +1,134 @@
+package adr
+
+import (
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestConstraintUnmarshalMixedList(t *testing.T) {
+	input := `constraints:
+  - "Use the repository pattern"
+  - forbid_import: database/sql
+    paths: ["src/handlers/**"]
+    message: Handlers must use repositories
+  - forbid_regex: 'fmt\.Println'
+`
+	var fm Frontmatter
+	if err := yaml.Unmarshal([]byte(input), &fm); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if len(fm.Constraints) != 3 {
+		t.Fatalf("len(Constraints) = %d, want 3", len(fm.Constraints))
+	}
+
+	want := []string{"Use the repository pattern", "Handlers must use repositories", `forbid_regex: fmt\.Println`}
+	got := ConstraintStrings(fm.Constraints)
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("ConstraintStrings()[%d] = %q, want %q", i, got[i], want[i])
+		}
+	}
+
+	if fm.Constraints[0].IsRule() {
+		t.Error("free-text constraint reported as rule")
+	}
+	if kind, arg := fm.Constraints[1].Rule(); kind != RuleForbidImport || arg != "database/sql" {
+		t.Errorf("Rule() = %q, %q", kind, arg)
+	}
+	if len(fm.Constraints[1].Paths) != 1 {
+		t.Errorf("Paths = %v", fm.Constraints[1].Paths)
+	}
+}
+
+func TestConstraintUnmarshalErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"unknown key", "constraints:\n  - forbid_imports: database/sql\n"},
+		{"sequence entry", "constraints:\n  - [a, b]\n"},
+		{"unknown imports key", "constraints:\n  - imports:\n      to: [database/sql]\n      allowed_from: [db]\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var fm Frontmatter
+			if err := yaml.Unmarshal([]byte(tt.input), &fm); err == nil {
+				t.Error("Unmarshal() expected error")
+			}
+		})
+	}
+}
+
+func TestConstraintMarshalRoundTrip(t *testing.T) {
+	constraints := []Constraint{
+		{Text: "Use the repository pattern"},
+		{ForbidPath: "src/legacy/**", Message: "Legacy code is frozen"},
+	}
+	data, err := yaml.Marshal(constraints)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	if !strings.Contains(string(data), "- Use the repository pattern\n") {
+		t.Errorf("free text not marshaled as string:\n%s", data)
+	}
+
+	var back []Constraint
+	if err := yaml.Unmarshal(data, &back); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if len(back) != 2 || back[0].Text != constraints[0].Text || back[1].ForbidPath != "src/legacy/**" || back[1].Message != "Legacy code is frozen" {
+		t.Errorf("round trip = %+v", back)
+	}
+}
+
+func TestValidateConstraints(t *testing.T) {
+	tests := []struct {
+		name       string
+		constraint Constraint
+		wantErr    bool
+	}{
+		{"free text", Constraint{Text: "Be nice"}, false},
+		{"valid rule", Constraint{ForbidRegex: `TODO\(`}, false},
+		{"two rule keys", Constraint{ForbidImport: "a", ForbidPath: "b"}, true},
+		{"invalid regex", Constraint{ForbidRegex: "("}, true},
+		{"mapping without rule", Constraint{Message: "Something"}, true},
+		{"valid imports rule", Constraint{Imports: &ImportRule{To: []string{"database/sql"}, AllowFrom: []string{"internal/db/**"}}}, false},
+		{"imports rule without to", Constraint{Imports: &ImportRule{AllowFrom: []string{"internal/db/**"}}}, true},
+		{"imports rule without from", Constraint{Imports: &ImportRule{To: []string{"database/sql"}}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := &ValidationResult{}
+			validateConstraints([]Constraint{tt.constraint}, result)
+			if got := len(result.Errors) > 0; got != tt.wantErr {
+				t.Errorf("validateConstraints() errors = %v, wantErr %v", result.Errors, tt.wantErr)
+			}
+			for _, e := range result.Errors {
+				if e.Code != "invalid_constraint" {
+					t.Errorf("error code = %q, want invalid_constraint", e.Code)
+				}
+			}
+		})
+	}
+}
+
+func TestImportRuleString(t *testing.T) {
+	input := `constraints:
+  - imports:
+      to: [database/sql]
+      allow_from: ["internal/db/**"]
+      forbid_from: [cmd/**]
+`
+	var fm Frontmatter
+	if err := yaml.Unmarshal([]byte(input), &fm); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	want := "imports: only internal/db/** may import database/sql; cmd/** must not import database/sql"
+	if got := fm.Constraints[0].String(); got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
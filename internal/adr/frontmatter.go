@@ -63,11 +63,24 @@ func ExtractFrontmatter(content string) (frontmatter string, body string, err er
 
 // ParseFrontmatter parses YAML frontmatter into a Frontmatter struct.
 func ParseFrontmatter(yamlContent string) (*Frontmatter, error) {
+	fm, _, err := parseFrontmatterNode(yamlContent)
+	return fm, err
+}
+
+// parseFrontmatterNode parses YAML frontmatter and also returns its node
+// tree, which carries the positions of keys and values.
+func parseFrontmatterNode(yamlContent string) (*Frontmatter, *yaml.Node, error) {
+	var node yaml.Node
+	if err := yaml.Unmarshal([]byte(yamlContent), &node); err != nil {
+		return nil, nil, fmt.Errorf("parsing frontmatter YAML: %w", err)
+	}
 	var fm Frontmatter
-	if err := yaml.Unmarshal([]byte(yamlContent), &fm); err != nil {
-		return nil, fmt.Errorf("parsing frontmatter YAML: %w", err)
+	if node.Kind != 0 {
+		if err := node.Decode(&fm); err != nil {
+			return nil, nil, fmt.Errorf("parsing frontmatter YAML: %w", err)
+		}
 	}
-	return &fm, nil
+	return &fm, &node, nil
 }
 
 // ParseADR parses a complete ADR from markdown content.
@@ -81,16 +94,21 @@ func ParseADR(content string, filename string, filePath string) (*ADR, error) {
 		return nil, fmt.Errorf("no frontmatter found in %s", filename)
 	}
 
-	fm, err := ParseFrontmatter(fmStr)
+	fm, node, err := parseFrontmatterNode(fmStr)
 	if err != nil {
 		return nil, fmt.Errorf("parsing frontmatter in %s: %w", filename, err)
 	}
 
+	// The body starts after the opening delimiter, the frontmatter lines
+	// and the closing delimiter.
+	bodyLine := strings.Count(fmStr, "\n") + 3
+
 	return &ADR{
 		Frontmatter: *fm,
 		Body:        body,
 		Filename:    filename,
 		FilePath:    filePath,
+		Positions:   newSourceMap(node, body, bodyLine),
 	}, nil
 }
 
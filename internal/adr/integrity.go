@@ -0,0 +1,218 @@
+package adr
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ValidateRepository checks references between ADRs. Unlike Validate, which
+// looks at each ADR in isolation, it reports problems that only show up when
+// the whole set is considered: dangling and self references, one-sided
+// supersession links, supersession cycles, duplicate IDs and superseded ADRs
+// whose successor does not exist. Errors are returned in ADR order.
+func ValidateRepository(adrs []*ADR) []ValidationError {
+	byID := make(map[string][]*ADR)
+	for _, a := range adrs {
+		if a.Frontmatter.ADRID != "" {
+			byID[a.Frontmatter.ADRID] = append(byID[a.Frontmatter.ADRID], a)
+		}
+	}
+
+	var errs []ValidationError
+	// addAt reports an error on field, located at the frontmatter path at
+	addAt := func(a *ADR, field, at, code, message string) {
+		pos := a.Positions.locate(at)
+		errs = append(errs, ValidationError{
+			File:     a.Filename,
+			Field:    field,
+			Message:  message,
+			Severity: SeverityError,
+			Code:     code,
+			Line:     pos.Line,
+			Column:   pos.Column,
+		})
+	}
+	add := func(a *ADR, field, code, message string) {
+		addAt(a, field, field, code, message)
+	}
+
+	cycles := findSupersessionCycles(adrs, byID)
+
+	for _, a := range adrs {
+		fm := a.Frontmatter
+		id := fm.ADRID
+
+		if others := byID[id]; len(others) > 1 {
+			var files []string
+			for _, o := range others {
+				if o != a {
+					files = append(files, o.Filename)
+				}
+			}
+			add(a, "adr_id", "duplicate_adr_id", fmt.Sprintf("%s is also used by %s", id, strings.Join(files, ", ")))
+		}
+
+		refs := []struct {
+			field string
+			ids   []string
+		}{
+			{"supersedes", fm.Supersedes},
+			{"superseded_by", fm.SupersededBy},
+			{"related_adrs", fm.RelatedADRs},
+		}
+		for _, ref := range refs {
+			for i, target := range ref.ids {
+				at := fmt.Sprintf("%s[%d]", ref.field, i)
+				switch {
+				case target == id && id != "":
+					addAt(a, ref.field, at, "self_reference", fmt.Sprintf("%s references itself", id))
+				case len(byID[target]) == 0:
+					addAt(a, ref.field, at, "dangling_reference", fmt.Sprintf("references %s, which does not exist", target))
+				}
+			}
+		}
+
+		// One-sided supersession links are reported on the ADR missing the back-link
+		for _, target := range fm.SupersededBy {
+			for _, successor := range byID[target] {
+				if target != id && !containsID(successor.Frontmatter.Supersedes, id) {
+					add(successor, "supersedes", "asymmetric_supersession",
+						fmt.Sprintf("%s lists %s in superseded_by, but %s does not list it in supersedes", id, target, target))
+				}
+			}
+		}
+		for _, target := range fm.Supersedes {
+			for _, predecessor := range byID[target] {
+				if target != id && !containsID(predecessor.Frontmatter.SupersededBy, id) {
+					add(predecessor, "superseded_by", "asymmetric_supersession",
+						fmt.Sprintf("%s lists %s in supersedes, but %s does not list it in superseded_by", id, target, target))
+				}
+			}
+		}
+
+		// An empty superseded_by is already reported by ValidateLifecycle
+		if fm.Status == StatusSuperseded && len(fm.SupersededBy) > 0 && !hasSuccessor(a, adrs, byID) {
+			add(a, "superseded_by", "superseded_without_successor",
+				fmt.Sprintf("status is superseded but none of %v exists", fm.SupersededBy))
+		}
+
+		if cycle, ok := cycles[id]; ok {
+			add(a, "supersedes", "supersession_cycle", fmt.Sprintf("supersession cycle: %s", strings.Join(cycle, " -> ")))
+		}
+	}
+
+	return errs
+}
+
+// hasSuccessor reports whether any existing ADR replaces a.
+func hasSuccessor(a *ADR, adrs []*ADR, byID map[string][]*ADR) bool {
+	id := a.Frontmatter.ADRID
+	for _, target := range a.Frontmatter.SupersededBy {
+		if target != id && len(byID[target]) > 0 {
+			return true
+		}
+	}
+	for _, other := range adrs {
+		if other.Frontmatter.ADRID != id && containsID(other.Frontmatter.Supersedes, id) {
+			return true
+		}
+	}
+	return false
+}
+
+// findSupersessionCycles returns, for every ADR that is part of a supersession
+// cycle, the cycle it belongs to as a closed path starting at the lowest ID.
+// Edges run from an ADR to its successor and are taken from both supersedes
+// and superseded_by; self references are ignored here.
+func findSupersessionCycles(adrs []*ADR, byID map[string][]*ADR) map[string][]string {
+	successors := make(map[string][]string)
+	addEdge := func(from, to string) {
+		if from == "" || to == "" || from == to || len(byID[from]) == 0 || len(byID[to]) == 0 {
+			return
+		}
+		if !containsID(successors[from], to) {
+			successors[from] = append(successors[from], to)
+		}
+	}
+	for _, a := range adrs {
+		for _, s := range a.Frontmatter.SupersededBy {
+			addEdge(a.Frontmatter.ADRID, s)
+		}
+		for _, p := range a.Frontmatter.Supersedes {
+			addEdge(p, a.Frontmatter.ADRID)
+		}
+	}
+
+	ids := make([]string, 0, len(successors))
+	for id := range successors {
+		sort.Strings(successors[id])
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	const (
+		unvisited = iota
+		inProgress
+		done
+	)
+	state := make(map[string]int)
+	cycles := make(map[string][]string)
+	var stack []string
+
+	var visit func(id string)
+	visit = func(id string) {
+		state[id] = inProgress
+		stack = append(stack, id)
+		for _, next := range successors[id] {
+			switch state[next] {
+			case unvisited:
+				visit(next)
+			case inProgress:
+				// Extract the cycle from the stack
+				start := len(stack) - 1
+				for stack[start] != next {
+					start--
+				}
+				cycle := normalizeCycle(stack[start:])
+				for _, member := range cycle[:len(cycle)-1] {
+					if _, seen := cycles[member]; !seen {
+						cycles[member] = cycle
+					}
+				}
+			}
+		}
+		stack = stack[:len(stack)-1]
+		state[id] = done
+	}
+
+	for _, id := range ids {
+		if state[id] == unvisited {
+			visit(id)
+		}
+	}
+
+	return cycles
+}
+
+// normalizeCycle rotates a cycle so it starts at its lowest ID and closes it.
+func normalizeCycle(cycle []string) []string {
+	minIdx := 0
+	for i, id := range cycle {
+		if id < cycle[minIdx] {
+			minIdx = i
+		}
+	}
+	result := append([]string{}, cycle[minIdx:]...)
+	result = append(result, cycle[:minIdx]...)
+	return append(result, result[0])
+}
+
+func containsID(ids []string, id string) bool {
+	for _, s := range ids {
+		if s == id {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,151 @@
+package adr
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func testRepoADR(num int, status Status, mutate func(fm *Frontmatter)) *ADR {
+	fm := Frontmatter{
+		ADRID:  fmt.Sprintf("ADR-%04d", num),
+		Title:  fmt.Sprintf("Decision %d", num),
+		Status: status,
+		Date:   "2026-01-16",
+	}
+	if mutate != nil {
+		mutate(&fm)
+	}
+	return &ADR{
+		Frontmatter: fm,
+		Filename:    fmt.Sprintf("%04d-decision.md", num),
+	}
+}
+
+func errorCodes(errs []ValidationError) []string {
+	var codes []string
+	for _, e := range errs {
+		codes = append(codes, e.File+":"+e.Code)
+	}
+	return codes
+}
+
+func TestValidateRepository(t *testing.T) {
+	tests := []struct {
+		name string
+		adrs []*ADR
+		want []string
+	}{
+		{
+			name: "consistent supersession",
+			adrs: []*ADR{
+				testRepoADR(1, StatusSuperseded, func(fm *Frontmatter) { fm.SupersededBy = []string{"ADR-0002"} }),
+				testRepoADR(2, StatusAdopted, func(fm *Frontmatter) {
+					fm.Supersedes = []string{"ADR-0001"}
+					fm.RelatedADRs = []string{"ADR-0001"}
+				}),
+			},
+		},
+		{
+			name: "dangling references",
+			adrs: []*ADR{
+				testRepoADR(1, StatusAdopted, func(fm *Frontmatter) {
+					fm.RelatedADRs = []string{"ADR-0042"}
+					fm.Supersedes = []string{"ADR-0043"}
+				}),
+			},
+			want: []string{"0001-decision.md:dangling_reference", "0001-decision.md:dangling_reference"},
+		},
+		{
+			name: "self reference",
+			adrs: []*ADR{
+				testRepoADR(1, StatusAdopted, func(fm *Frontmatter) { fm.RelatedADRs = []string{"ADR-0001"} }),
+			},
+			want: []string{"0001-decision.md:self_reference"},
+		},
+		{
+			name: "missing supersedes back-link",
+			adrs: []*ADR{
+				testRepoADR(1, StatusSuperseded, func(fm *Frontmatter) { fm.SupersededBy = []string{"ADR-0002"} }),
+				testRepoADR(2, StatusAdopted, nil),
+			},
+			want: []string{"0002-decision.md:asymmetric_supersession"},
+		},
+		{
+			name: "missing superseded_by back-link",
+			adrs: []*ADR{
+				testRepoADR(1, StatusAdopted, nil),
+				testRepoADR(2, StatusAdopted, func(fm *Frontmatter) { fm.Supersedes = []string{"ADR-0001"} }),
+			},
+			want: []string{"0001-decision.md:asymmetric_supersession"},
+		},
+		{
+			name: "duplicate IDs",
+			adrs: []*ADR{
+				testRepoADR(1, StatusAdopted, nil),
+				{Frontmatter: Frontmatter{ADRID: "ADR-0001", Status: StatusAdopted}, Filename: "0001-copy.md"},
+			},
+			want: []string{"0001-decision.md:duplicate_adr_id", "0001-copy.md:duplicate_adr_id"},
+		},
+		{
+			name: "successor does not exist",
+			adrs: []*ADR{
+				testRepoADR(1, StatusSuperseded, func(fm *Frontmatter) { fm.SupersededBy = []string{"ADR-0009"} }),
+			},
+			want: []string{"0001-decision.md:dangling_reference", "0001-decision.md:superseded_without_successor"},
+		},
+		{
+			name: "supersession cycle",
+			adrs: []*ADR{
+				testRepoADR(1, StatusSuperseded, func(fm *Frontmatter) {
+					fm.SupersededBy = []string{"ADR-0002"}
+					fm.Supersedes = []string{"ADR-0002"}
+				}),
+				testRepoADR(2, StatusSuperseded, func(fm *Frontmatter) {
+					fm.SupersededBy = []string{"ADR-0001"}
+					fm.Supersedes = []string{"ADR-0001"}
+				}),
+			},
+			want: []string{"0001-decision.md:supersession_cycle", "0002-decision.md:supersession_cycle"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := errorCodes(ValidateRepository(tt.adrs))
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("ValidateRepository() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateRepositoryCycleMessage(t *testing.T) {
+	adrs := []*ADR{
+		testRepoADR(1, StatusSuperseded, func(fm *Frontmatter) { fm.SupersededBy = []string{"ADR-0002"} }),
+		testRepoADR(2, StatusSuperseded, func(fm *Frontmatter) {
+			fm.Supersedes = []string{"ADR-0001"}
+			fm.SupersededBy = []string{"ADR-0003"}
+		}),
+		testRepoADR(3, StatusSuperseded, func(fm *Frontmatter) {
+			fm.Supersedes = []string{"ADR-0002"}
+			fm.SupersededBy = []string{"ADR-0001"}
+		}),
+	}
+
+	var messages []string
+	for _, e := range ValidateRepository(adrs) {
+		if e.Code == "supersession_cycle" {
+			messages = append(messages, e.Message)
+		}
+	}
+	if len(messages) != 3 {
+		t.Fatalf("got %d cycle errors, want 3: %v", len(messages), messages)
+	}
+	want := "supersession cycle: ADR-0001 -> ADR-0002 -> ADR-0003 -> ADR-0001"
+	for _, m := range messages {
+		if m != want {
+			t.Errorf("cycle message = %q, want %q", m, want)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package adr
+
+import (
+	"fmt"
+)
+
+// transitions defines the ADR lifecycle state machine. Each status maps to
+// the statuses it may move to; rejected and superseded are terminal.
+var transitions = map[Status][]Status{
+	StatusProposed:   {StatusAdopted, StatusRejected},
+	StatusAdopted:    {StatusDeprecated, StatusSuperseded},
+	StatusDeprecated: {StatusSuperseded},
+	StatusRejected:   {},
+	StatusSuperseded: {},
+}
+
+// AllowedTransitions returns the statuses an ADR in the given status may move to.
+func AllowedTransitions(from Status) []Status {
+	return append([]Status{}, transitions[from]...)
+}
+
+// CheckTransition returns an error if moving from one status to another is
+// not permitted by the lifecycle state machine.
+func CheckTransition(from, to Status) error {
+	if _, err := ParseStatus(string(to)); err != nil {
+		return err
+	}
+	if from == to {
+		return fmt.Errorf("status is already %s", to)
+	}
+	allowed, ok := transitions[from]
+	if !ok {
+		return fmt.Errorf("invalid current status %q", from)
+	}
+	for _, s := range allowed {
+		if s == to {
+			return nil
+		}
+	}
+	if len(allowed) == 0 {
+		return fmt.Errorf("cannot move from %s to %s: %s is a terminal status", from, to, from)
+	}
+	return fmt.Errorf("cannot move from %s to %s: allowed transitions are %v", from, to, allowed)
+}
+
+// ValidateLifecycle checks that an ADR's status is consistent with its
+// supersession fields and status date, i.e. that the state it is in could
+// have been reached through the lifecycle state machine.
+func ValidateLifecycle(adr *ADR, result *ValidationResult) {
+	fm := adr.Frontmatter
+
+	if fm.Status == StatusSuperseded && len(fm.SupersededBy) == 0 {
+		result.addErrorCode("superseded_by", "status is superseded but no successor is listed", "superseded_without_successor")
+	}
+
+	if len(fm.SupersededBy) > 0 && fm.Status != StatusSuperseded && fm.Status != "" {
+		result.addErrorCode("status", fmt.Sprintf("lists superseded_by %v but status is %s, not superseded", fm.SupersededBy, fm.Status), "successor_without_superseded_status")
+	}
+
+	if fm.StatusDate != "" {
+		if !dateRegex.MatchString(fm.StatusDate) {
+			result.addError("status_date", "must be in YYYY-MM-DD format")
+		} else if dateRegex.MatchString(fm.Date) && fm.StatusDate < fm.Date {
+			result.addErrorCode("status_date", fmt.Sprintf("status changed on %s, before the decision date %s", fm.StatusDate, fm.Date), "status_date_before_date")
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package adr
+
+import (
+	"testing"
+)
+
+func TestCheckTransition(t *testing.T) {
+	tests := []struct {
+		from    Status
+		to      Status
+		wantErr bool
+	}{
+		{StatusProposed, StatusAdopted, false},
+		{StatusProposed, StatusRejected, false},
+		{StatusProposed, StatusDeprecated, true},
+		{StatusProposed, StatusSuperseded, true},
+		{StatusAdopted, StatusDeprecated, false},
+		{StatusAdopted, StatusSuperseded, false},
+		{StatusAdopted, StatusProposed, true},
+		{StatusAdopted, StatusAdopted, true},
+		{StatusDeprecated, StatusSuperseded, false},
+		{StatusDeprecated, StatusAdopted, true},
+		{StatusRejected, StatusAdopted, true},
+		{StatusSuperseded, StatusAdopted, true},
+		{StatusAdopted, Status("unknown"), true},
+		{Status("unknown"), StatusAdopted, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.from)+"->"+string(tt.to), func(t *testing.T) {
+			err := CheckTransition(tt.from, tt.to)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CheckTransition(%s, %s) error = %v, wantErr %v", tt.from, tt.to, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAllowedTransitionsTerminal(t *testing.T) {
+	for _, s := range []Status{StatusRejected, StatusSuperseded} {
+		if got := AllowedTransitions(s); len(got) != 0 {
+			t.Errorf("AllowedTransitions(%s) = %v, want none", s, got)
+		}
+	}
+}
+
+func TestValidateLifecycle(t *testing.T) {
+	tests := []struct {
+		name     string
+		fm       Frontmatter
+		wantCode string
+	}{
+		{
+			name: "adopted without successor",
+			fm:   Frontmatter{Status: StatusAdopted, Date: "2026-01-16"},
+		},
+		{
+			name: "superseded with successor",
+			fm:   Frontmatter{Status: StatusSuperseded, Date: "2026-01-16", SupersededBy: []string{"ADR-0002"}},
+		},
+		{
+			name:     "superseded without successor",
+			fm:       Frontmatter{Status: StatusSuperseded, Date: "2026-01-16"},
+			wantCode: "superseded_without_successor",
+		},
+		{
+			name:     "successor on adopted ADR",
+			fm:       Frontmatter{Status: StatusAdopted, Date: "2026-01-16", SupersededBy: []string{"ADR-0002"}},
+			wantCode: "successor_without_superseded_status",
+		},
+		{
+			name: "status date after decision date",
+			fm:   Frontmatter{Status: StatusAdopted, Date: "2026-01-16", StatusDate: "2026-02-01"},
+		},
+		{
+			name:     "status date before decision date",
+			fm:       Frontmatter{Status: StatusAdopted, Date: "2026-01-16", StatusDate: "2025-12-31"},
+			wantCode: "status_date_before_date",
+		},
+		{
+			name:     "malformed status date",
+			fm:       Frontmatter{Status: StatusAdopted, Date: "2026-01-16", StatusDate: "Feb 1"},
+			wantCode: "validation_error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := &ValidationResult{File: "0001-test.md"}
+			ValidateLifecycle(&ADR{Frontmatter: tt.fm}, result)
+
+			if tt.wantCode == "" {
+				if len(result.Errors) != 0 {
+					t.Errorf("ValidateLifecycle() errors = %v, want none", result.Errors)
+				}
+				return
+			}
+			if len(result.Errors) != 1 || result.Errors[0].Code != tt.wantCode {
+				t.Errorf("ValidateLifecycle() errors = %v, want one with code %q", result.Errors, tt.wantCode)
+			}
+		})
+	}
+}
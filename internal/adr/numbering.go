@@ -14,6 +14,11 @@ import (
 
 var adrFilenameRegex = regexp.MustCompile(`^(\d{4})-.*\.md$`)
 
+// IsADRFilename reports whether a file name follows the NNNN-title.md pattern.
+func IsADRFilename(name string) bool {
+	return adrFilenameRegex.MatchString(name)
+}
+
 // FindNextNumber scans the ADR directory and returns the next available ADR number.
 func FindNextNumber(adrDir string) (int, error) {
 	entries, err := os.ReadDir(adrDir)
@@ -0,0 +1,151 @@
+package adr
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Position is a 1-based line and column in an ADR file. The zero value
+// means the position is unknown.
+type Position struct {
+	Line   int
+	Column int
+}
+
+// SourceMap records where the frontmatter keys, list items and body headings
+// of a parsed ADR are located in its file.
+type SourceMap struct {
+	// Fields maps frontmatter paths to the position of their key or list
+	// item, e.g. "tags", "tags[1]", "scope.paths[0]" or "constraints[2]".
+	Fields map[string]Position
+
+	// Body is the position of the first non-blank line after the frontmatter.
+	Body Position
+
+	// Headings are the markdown headings of the body in document order.
+	Headings []Heading
+}
+
+// Heading is a markdown heading in the body of an ADR.
+type Heading struct {
+	Title    string
+	Position Position
+}
+
+var headingRegex = regexp.MustCompile(`^#{1,6}\s*(.*?)\s*#*\s*$`)
+
+// newSourceMap builds the source map of an ADR from its parsed frontmatter
+// node and body. bodyLine is the file line the body starts on.
+func newSourceMap(node *yaml.Node, body string, bodyLine int) *SourceMap {
+	m := &SourceMap{Fields: make(map[string]Position)}
+	if node != nil && node.Kind == yaml.DocumentNode && len(node.Content) > 0 {
+		m.addNode("", node.Content[0])
+	}
+
+	inFence := false
+	for i, line := range strings.Split(body, "\n") {
+		line = strings.TrimRight(line, "\r")
+		trimmed := strings.TrimSpace(line)
+		if m.Body.Line == 0 && trimmed != "" {
+			m.Body = Position{Line: bodyLine + i, Column: 1}
+		}
+		if strings.HasPrefix(trimmed, "```") {
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			continue
+		}
+		if match := headingRegex.FindStringSubmatch(line); match != nil {
+			m.Headings = append(m.Headings, Heading{
+				Title:    match[1],
+				Position: Position{Line: bodyLine + i, Column: 1},
+			})
+		}
+	}
+	if m.Body.Line == 0 {
+		m.Body = Position{Line: bodyLine, Column: 1}
+	}
+	return m
+}
+
+// addNode records the positions of the keys and items below a YAML node.
+// Frontmatter lines are shifted by one for the opening "---".
+func (m *SourceMap) addNode(path string, node *yaml.Node) {
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key := node.Content[i]
+			keyPath := key.Value
+			if path != "" {
+				keyPath = path + "." + key.Value
+			}
+			m.Fields[keyPath] = Position{Line: key.Line + 1, Column: key.Column}
+			m.addNode(keyPath, node.Content[i+1])
+		}
+	case yaml.SequenceNode:
+		for i, item := range node.Content {
+			itemPath := fmt.Sprintf("%s[%d]", path, i)
+			m.Fields[itemPath] = Position{Line: item.Line + 1, Column: item.Column}
+			if item.Kind == yaml.MappingNode {
+				m.addNode(itemPath, item)
+			}
+		}
+	}
+}
+
+// Field returns the position of a frontmatter path. For list items and
+// nested keys that are not recorded it falls back to the enclosing key.
+func (m *SourceMap) Field(path string) (Position, bool) {
+	if m == nil {
+		return Position{}, false
+	}
+	for path != "" {
+		if pos, ok := m.Fields[path]; ok {
+			return pos, true
+		}
+		i := strings.LastIndexAny(path, ".[")
+		if i < 0 {
+			break
+		}
+		path = path[:i]
+	}
+	return Position{}, false
+}
+
+// Heading returns the position of the first body heading whose title
+// matches pattern.
+func (m *SourceMap) Heading(pattern *regexp.Regexp) (Position, bool) {
+	if m == nil {
+		return Position{}, false
+	}
+	for _, h := range m.Headings {
+		if pattern.MatchString(h.Title) {
+			return h.Position, true
+		}
+	}
+	return Position{}, false
+}
+
+// locate returns the position a validation error on field refers to:
+// the frontmatter key or list item for frontmatter fields, the ADR ID for
+// the filename, and the start of the body for missing sections. Fields
+// missing from the frontmatter are reported on its opening line.
+func (m *SourceMap) locate(field string) Position {
+	if m == nil {
+		return Position{}
+	}
+	switch field {
+	case "filename":
+		field = "adr_id"
+	case "body":
+		return m.Body
+	}
+	if pos, ok := m.Field(field); ok {
+		return pos
+	}
+	return Position{Line: 1, Column: 1}
+}
@@ -0,0 +1,119 @@
+package adr
+
+import (
+	"regexp"
+	"testing"
+)
+
+const positionedADR = `---
+adr_id: ADR-0001
+title: Use PostgreSQL
+status: adopted
+date: 2026-01-16
+scope:
+  paths:
+    - "src/db/**"
+tags: [database, Storage]
+constraints:
+  - "Use prepared statements"
+  - forbid_regex: "("
+related_adrs:
+  - ADR-0042
+---
+
+# ADR-0001: Use PostgreSQL
+
+## Context
+
+Context.
+
+` + "```markdown\n## Not a heading\n```" + `
+
+## Decision
+
+We use PostgreSQL.
+
+### Option A: Adopted
+`
+
+func TestSourceMap(t *testing.T) {
+	a, err := ParseADR(positionedADR, "0001-use-postgresql.md", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := a.Positions
+
+	fields := []struct {
+		path string
+		want Position
+	}{
+		{"adr_id", Position{2, 1}},
+		{"scope.paths", Position{7, 3}},
+		{"scope.paths[0]", Position{8, 7}},
+		{"tags[1]", Position{9, 18}},
+		{"constraints[1]", Position{12, 5}},
+		{"constraints[1].forbid_regex", Position{12, 5}},
+		{"constraints[1].message", Position{12, 5}}, // falls back to the item
+		{"related_adrs[0]", Position{14, 5}},
+	}
+	for _, tt := range fields {
+		if got, ok := m.Field(tt.path); !ok || got != tt.want {
+			t.Errorf("Field(%q) = %v, %v, want %v", tt.path, got, ok, tt.want)
+		}
+	}
+	if _, ok := m.Field("supersedes"); ok {
+		t.Error("Field(supersedes) found a missing key")
+	}
+
+	if m.Body != (Position{17, 1}) {
+		t.Errorf("Body = %v, want 17:1", m.Body)
+	}
+	var titles []string
+	for _, h := range m.Headings {
+		titles = append(titles, h.Title)
+	}
+	if got := len(titles); got != 4 {
+		t.Fatalf("headings = %q, want 4 outside the code fence", titles)
+	}
+	if pos, ok := m.Heading(regexp.MustCompile(`(?i)^decision$`)); !ok || pos.Line != 27 {
+		t.Errorf("Heading(Decision) = %v, %v, want line 27", pos, ok)
+	}
+}
+
+func TestValidationErrorPositions(t *testing.T) {
+	a, err := ParseADR(positionedADR, "0002-use-postgresql.md", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	vr := ValidateWithOptions(a, ValidateOptions{AllowedTags: []string{"database"}})
+	errs := append(vr.Errors, ValidateRepository([]*ADR{a})...)
+
+	want := map[string]Position{
+		"validation_error":   {2, 1},  // filename does not match adr_id
+		"tag_not_allowed":    {9, 18}, // the Storage item
+		"invalid_constraint": {12, 5},
+		"dangling_reference": {14, 5},
+	}
+	for _, ve := range errs {
+		if ve.Field == "body" {
+			if ve.Line != 17 {
+				t.Errorf("%s at line %d, want the start of the body", ve.Message, ve.Line)
+			}
+			continue
+		}
+		if pos, ok := want[ve.Code]; ok && (Position{ve.Line, ve.Column}) != pos {
+			t.Errorf("%s (%s) at %d:%d, want %v", ve.Code, ve.Field, ve.Line, ve.Column, pos)
+		}
+	}
+	for _, w := range vr.Warnings {
+		if w.Line != 31 {
+			t.Errorf("%s at line %d, want the adopted option heading", w.Code, w.Line)
+		}
+	}
+
+	// ADRs not read from a file have no positions
+	vr = Validate(&ADR{Filename: "0001-x.md"})
+	if len(vr.Errors) == 0 || vr.Errors[0].Line != 0 {
+		t.Errorf("errors without positions = %+v", vr.Errors)
+	}
+}
@@ -0,0 +1,137 @@
+package adr
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SetFrontmatterField replaces the value of a top-level frontmatter key in raw
+// ADR content. Only the lines belonging to that key are rewritten; every other
+// byte of the file, including comments, quoting and the body, is left intact.
+// If the key is not present it is appended at the end of the frontmatter.
+func SetFrontmatterField(content, key string, value interface{}) (string, error) {
+	lines := strings.SplitAfter(content, "\n")
+	if len(lines) == 0 || strings.TrimSpace(lines[0]) != frontmatterDelimiter {
+		return "", fmt.Errorf("no frontmatter found")
+	}
+
+	closing := -1
+	for i := 1; i < len(lines); i++ {
+		if strings.TrimSpace(lines[i]) == frontmatterDelimiter {
+			closing = i
+			break
+		}
+	}
+	if closing == -1 {
+		return "", fmt.Errorf("unclosed frontmatter: missing closing '---'")
+	}
+
+	newline := "\n"
+	if strings.HasSuffix(lines[0], "\r\n") {
+		newline = "\r\n"
+	}
+
+	rendered, err := renderField(key, value, newline)
+	if err != nil {
+		return "", err
+	}
+
+	start, end, err := fieldLines(strings.Join(lines[1:closing], ""), key)
+	if err != nil {
+		return "", err
+	}
+
+	var out strings.Builder
+	if start == -1 {
+		// Key is absent: insert just before the closing delimiter
+		out.WriteString(strings.Join(lines[:closing], ""))
+		out.WriteString(rendered)
+		out.WriteString(strings.Join(lines[closing:], ""))
+		return out.String(), nil
+	}
+
+	// Offsets are relative to the frontmatter, which starts after line 0
+	start++
+	end++
+	out.WriteString(strings.Join(lines[:start], ""))
+	out.WriteString(rendered)
+	out.WriteString(strings.Join(lines[end:], ""))
+	return out.String(), nil
+}
+
+// fieldLines returns the zero-based half-open line range [start, end) that a
+// top-level key occupies within the frontmatter text. Trailing blank and
+// comment-only lines are not considered part of the value. Returns start -1
+// when the key does not exist.
+func fieldLines(frontmatter, key string) (int, int, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal([]byte(frontmatter), &doc); err != nil {
+		return 0, 0, fmt.Errorf("parsing frontmatter YAML: %w", err)
+	}
+	if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return -1, -1, nil
+	}
+
+	fmLines := strings.SplitAfter(frontmatter, "\n")
+	mapping := doc.Content[0]
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value != key {
+			continue
+		}
+
+		start := mapping.Content[i].Line - 1
+		end := len(fmLines)
+		if i+2 < len(mapping.Content) {
+			end = mapping.Content[i+2].Line - 1
+		}
+		for end > start+1 {
+			trimmed := strings.TrimSpace(fmLines[end-1])
+			if trimmed != "" && !strings.HasPrefix(trimmed, "#") {
+				break
+			}
+			end--
+		}
+		return start, end, nil
+	}
+
+	return -1, -1, nil
+}
+
+// renderField renders a single "key: value" YAML entry using the two-space
+// list indentation of the ADR template.
+func renderField(key string, value interface{}, newline string) (string, error) {
+	var valueNode yaml.Node
+	if err := valueNode.Encode(value); err != nil {
+		return "", fmt.Errorf("encoding %s: %w", key, err)
+	}
+	if valueNode.Kind == yaml.SequenceNode && len(valueNode.Content) == 0 {
+		valueNode.Style = yaml.FlowStyle
+	}
+
+	node := &yaml.Node{
+		Kind: yaml.MappingNode,
+		Content: []*yaml.Node{
+			{Kind: yaml.ScalarNode, Tag: "!!str", Value: key},
+			&valueNode,
+		},
+	}
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(node); err != nil {
+		return "", fmt.Errorf("encoding %s: %w", key, err)
+	}
+	if err := enc.Close(); err != nil {
+		return "", fmt.Errorf("encoding %s: %w", key, err)
+	}
+
+	rendered := buf.String()
+	if newline != "\n" {
+		rendered = strings.ReplaceAll(rendered, "\n", newline)
+	}
+	return rendered, nil
+}
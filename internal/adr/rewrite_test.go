@@ -0,0 +1,174 @@
+package adr
+
+import (
+	"testing"
+)
+
+const rewriteFixture = `---
+adr_id: ADR-0003
+title: "Use Redux"  # quoted on purpose
+status: adopted
+date: 2026-01-16
+tags:
+  - frontend
+supersedes: []
+superseded_by: []
+
+# trailing comment
+related_adrs:
+  - ADR-0001
+---
+
+# ADR-0003: Use Redux
+
+Body text stays as-is.
+`
+
+func TestSetFrontmatterField(t *testing.T) {
+	tests := []struct {
+		name  string
+		key   string
+		value interface{}
+		want  string
+	}{
+		{
+			name:  "replace scalar",
+			key:   "status",
+			value: StatusSuperseded,
+			want: `---
+adr_id: ADR-0003
+title: "Use Redux"  # quoted on purpose
+status: superseded
+date: 2026-01-16
+tags:
+  - frontend
+supersedes: []
+superseded_by: []
+
+# trailing comment
+related_adrs:
+  - ADR-0001
+---
+
+# ADR-0003: Use Redux
+
+Body text stays as-is.
+`,
+		},
+		{
+			name:  "replace empty list with items",
+			key:   "superseded_by",
+			value: []string{"ADR-0007"},
+			want: `---
+adr_id: ADR-0003
+title: "Use Redux"  # quoted on purpose
+status: adopted
+date: 2026-01-16
+tags:
+  - frontend
+supersedes: []
+superseded_by:
+  - ADR-0007
+
+# trailing comment
+related_adrs:
+  - ADR-0001
+---
+
+# ADR-0003: Use Redux
+
+Body text stays as-is.
+`,
+		},
+		{
+			name:  "replace last multi-line list",
+			key:   "related_adrs",
+			value: []string{},
+			want: `---
+adr_id: ADR-0003
+title: "Use Redux"  # quoted on purpose
+status: adopted
+date: 2026-01-16
+tags:
+  - frontend
+supersedes: []
+superseded_by: []
+
+# trailing comment
+related_adrs: []
+---
+
+# ADR-0003: Use Redux
+
+Body text stays as-is.
+`,
+		},
+		{
+			name:  "append missing key",
+			key:   "status_date",
+			value: "2026-02-01",
+			want: `---
+adr_id: ADR-0003
+title: "Use Redux"  # quoted on purpose
+status: adopted
+date: 2026-01-16
+tags:
+  - frontend
+supersedes: []
+superseded_by: []
+
+# trailing comment
+related_adrs:
+  - ADR-0001
+status_date: "2026-02-01"
+---
+
+# ADR-0003: Use Redux
+
+Body text stays as-is.
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := SetFrontmatterField(rewriteFixture, tt.key, tt.value)
+			if err != nil {
+				t.Fatalf("SetFrontmatterField() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("SetFrontmatterField() =\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetFrontmatterFieldCRLF(t *testing.T) {
+	content := "---\r\nadr_id: ADR-0001\r\nstatus: proposed\r\n---\r\n\r\nBody\r\n"
+	got, err := SetFrontmatterField(content, "status", StatusAdopted)
+	if err != nil {
+		t.Fatalf("SetFrontmatterField() error = %v", err)
+	}
+	want := "---\r\nadr_id: ADR-0001\r\nstatus: adopted\r\n---\r\n\r\nBody\r\n"
+	if got != want {
+		t.Errorf("SetFrontmatterField() = %q, want %q", got, want)
+	}
+}
+
+func TestSetFrontmatterFieldErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{"no frontmatter", "# Just markdown\n"},
+		{"unclosed frontmatter", "---\nadr_id: ADR-0001\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := SetFrontmatterField(tt.content, "status", StatusAdopted); err == nil {
+				t.Error("SetFrontmatterField() expected error")
+			}
+		})
+	}
+}
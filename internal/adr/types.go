@@ -46,17 +46,18 @@ type Scope struct {
 
 // Frontmatter represents the YAML frontmatter of an ADR.
 type Frontmatter struct {
-	ADRID        string   `yaml:"adr_id"`
-	Title        string   `yaml:"title"`
-	Status       Status   `yaml:"status"`
-	Date         string   `yaml:"date"`
-	Scope        Scope    `yaml:"scope"`
-	Tags         []string `yaml:"tags"`
-	Constraints  []string `yaml:"constraints"`
-	Invariants   []string `yaml:"invariants"`
-	Supersedes   []string `yaml:"supersedes"`
-	SupersededBy []string `yaml:"superseded_by"`
-	RelatedADRs  []string `yaml:"related_adrs"`
+	ADRID        string       `yaml:"adr_id"`
+	Title        string       `yaml:"title"`
+	Status       Status       `yaml:"status"`
+	StatusDate   string       `yaml:"status_date,omitempty"`
+	Date         string       `yaml:"date"`
+	Scope        Scope        `yaml:"scope"`
+	Tags         []string     `yaml:"tags"`
+	Constraints  []Constraint `yaml:"constraints"`
+	Invariants   []string     `yaml:"invariants"`
+	Supersedes   []string     `yaml:"supersedes"`
+	SupersededBy []string     `yaml:"superseded_by"`
+	RelatedADRs  []string     `yaml:"related_adrs"`
 }
 
 // ADR represents a complete Architecture Decision Record.
@@ -65,6 +66,18 @@ type ADR struct {
 	Body        string
 	Filename    string
 	FilePath    string
+	Positions   *SourceMap // Nil for ADRs not read by ParseADR
+}
+
+// DefaultIDPrefix is the prefix of ADR IDs unless configured otherwise.
+const DefaultIDPrefix = "ADR"
+
+// FormatID builds an ADR ID such as "ADR-0042" from a prefix and number.
+func FormatID(prefix string, number int) string {
+	if prefix == "" {
+		prefix = DefaultIDPrefix
+	}
+	return fmt.Sprintf("%s-%04d", prefix, number)
 }
 
 // Number extracts the numeric portion from the ADR ID (e.g., "ADR-0042" -> 42).
@@ -73,10 +86,11 @@ func (a *ADR) Number() (int, error) {
 }
 
 // ExtractNumber extracts the numeric portion from an ADR ID string.
+// Any prefix up to the last hyphen is ignored, so custom ID prefixes work.
 func ExtractNumber(adrID string) (int, error) {
 	adrID = strings.TrimSpace(adrID)
-	if strings.HasPrefix(strings.ToUpper(adrID), "ADR-") {
-		adrID = adrID[4:]
+	if i := strings.LastIndex(adrID, "-"); i >= 0 {
+		adrID = adrID[i+1:]
 	}
 	var num int
 	_, err := fmt.Sscanf(adrID, "%d", &num)
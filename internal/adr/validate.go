@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"regexp"
 	"strings"
+
+	"github.com/sventorben/decider/internal/glob"
 )
 
 // ValidationSeverity indicates whether an issue is an error or warning.
@@ -21,9 +23,14 @@ type ValidationError struct {
 	Message  string
 	Severity ValidationSeverity
 	Code     string // Machine-readable error code
+	Line     int    // 1-based line in File; 0 if unknown
+	Column   int    // 1-based column in File; 0 if unknown
 }
 
 func (e ValidationError) Error() string {
+	if e.Line > 0 {
+		return fmt.Sprintf("%s:%d:%d: %s: %s", e.File, e.Line, e.Column, e.Field, e.Message)
+	}
 	return fmt.Sprintf("%s: %s: %s", e.File, e.Field, e.Message)
 }
 
@@ -32,6 +39,8 @@ type ValidationResult struct {
 	File     string
 	Errors   []ValidationError
 	Warnings []ValidationError
+
+	positions *SourceMap // Positions of the validated ADR, for locating errors
 }
 
 // IsValid returns true if there are no validation errors.
@@ -57,18 +66,53 @@ var RequiredSections = []string{
 	"Consequences",
 }
 
-var adrIDRegex = regexp.MustCompile(`^ADR-\d{4}$`)
 var dateRegex = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}$`)
 
+// isADRID reports whether id is prefix, a dash and four digits, e.g.
+// ADR-0001.
+func isADRID(id, prefix string) bool {
+	number, ok := strings.CutPrefix(id, prefix+"-")
+	if !ok || len(number) != 4 {
+		return false
+	}
+	for _, r := range number {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// ValidateOptions holds project-specific validation rules.
+type ValidateOptions struct {
+	RequiredSections []string // Sections every ADR must contain; RequiredSections when nil
+	AllowedTags      []string // Permitted tags; any tag is allowed when empty
+	IDPrefix         string   // ADR ID prefix; DefaultIDPrefix when empty
+}
+
 // Validate checks an ADR for required fields and returns validation errors.
 func Validate(adr *ADR) *ValidationResult {
-	result := &ValidationResult{File: adr.Filename}
+	return ValidateWithOptions(adr, ValidateOptions{})
+}
+
+// ValidateWithOptions checks an ADR like Validate, using project-specific rules.
+func ValidateWithOptions(adr *ADR, opts ValidateOptions) *ValidationResult {
+	result := &ValidationResult{File: adr.Filename, positions: adr.Positions}
+
+	prefix := opts.IDPrefix
+	if prefix == "" {
+		prefix = DefaultIDPrefix
+	}
+	requiredSections := opts.RequiredSections
+	if requiredSections == nil {
+		requiredSections = RequiredSections
+	}
 
 	// Validate frontmatter fields
 	if adr.Frontmatter.ADRID == "" {
 		result.addError("adr_id", "required field is missing")
-	} else if !adrIDRegex.MatchString(adr.Frontmatter.ADRID) {
-		result.addError("adr_id", "must match pattern ADR-NNNN")
+	} else if !isADRID(adr.Frontmatter.ADRID, prefix) {
+		result.addError("adr_id", fmt.Sprintf("must match pattern %s-NNNN", prefix))
 	}
 
 	if adr.Frontmatter.Title == "" {
@@ -94,8 +138,32 @@ func Validate(adr *ADR) *ValidationResult {
 		}
 	}
 
+	// Validate status consistency with the lifecycle state machine
+	ValidateLifecycle(adr, result)
+
+	// Validate structured constraints
+	validateConstraints(adr.Frontmatter.Constraints, result)
+
+	// Validate scope patterns
+	for i, pattern := range adr.Frontmatter.Scope.Paths {
+		if err := glob.ValidatePattern(pattern); err != nil {
+			result.add(SeverityError, "scope.paths", err.Error(), "invalid_scope_path",
+				adr.Positions.locate(fmt.Sprintf("scope.paths[%d]", i)))
+		}
+	}
+
+	// Validate tags against the allowed list
+	if len(opts.AllowedTags) > 0 {
+		for i, tag := range adr.Frontmatter.Tags {
+			if !containsID(opts.AllowedTags, tag) {
+				result.add(SeverityError, "tags", fmt.Sprintf("tag %q is not in the allowed tags %v", tag, opts.AllowedTags),
+					"tag_not_allowed", adr.Positions.locate(fmt.Sprintf("tags[%d]", i)))
+			}
+		}
+	}
+
 	// Validate required sections
-	for _, section := range RequiredSections {
+	for _, section := range requiredSections {
 		if !hasSection(adr.Body, section) {
 			result.addError("body", fmt.Sprintf("missing required section: %s", section))
 		}
@@ -108,23 +176,32 @@ func Validate(adr *ADR) *ValidationResult {
 }
 
 func (r *ValidationResult) addError(field, message string) {
-	r.Errors = append(r.Errors, ValidationError{
-		File:     r.File,
-		Field:    field,
-		Message:  message,
-		Severity: SeverityError,
-		Code:     "validation_error",
-	})
+	r.addErrorCode(field, message, "validation_error")
+}
+
+func (r *ValidationResult) addErrorCode(field, message, code string) {
+	r.add(SeverityError, field, message, code, r.positions.locate(field))
 }
 
 func (r *ValidationResult) addWarning(field, message, code string) {
-	r.Warnings = append(r.Warnings, ValidationError{
+	r.add(SeverityWarning, field, message, code, r.positions.locate(field))
+}
+
+func (r *ValidationResult) add(severity ValidationSeverity, field, message, code string, pos Position) {
+	ve := ValidationError{
 		File:     r.File,
 		Field:    field,
 		Message:  message,
-		Severity: SeverityWarning,
+		Severity: severity,
 		Code:     code,
-	})
+		Line:     pos.Line,
+		Column:   pos.Column,
+	}
+	if severity == SeverityWarning {
+		r.Warnings = append(r.Warnings, ve)
+	} else {
+		r.Errors = append(r.Errors, ve)
+	}
 }
 
 // hasSection checks if the body contains a markdown heading with the given title.
@@ -169,8 +246,22 @@ var (
 	rejectedDespitePattern = regexp.MustCompile(`(?i)\*\*rejected despite:\*\*`)
 	adoptedHeadingPattern  = regexp.MustCompile(`(?i)###\s+.+:\s*adopted`)
 	rejectedHeadingPattern = regexp.MustCompile(`(?i)###\s+.+:\s*rejected`)
+
+	// Heading titles, without the leading hashes, for locating warnings
+	adoptedTitlePattern  = regexp.MustCompile(`(?i).+:\s*adopted`)
+	rejectedTitlePattern = regexp.MustCompile(`(?i).+:\s*rejected`)
+	decisionTitlePattern = regexp.MustCompile(`(?i)^decision$`)
 )
 
+// rationalePosition returns the position of the first heading matching
+// pattern, or the start of the body.
+func rationalePosition(adr *ADR, pattern *regexp.Regexp) Position {
+	if pos, ok := adr.Positions.Heading(pattern); ok {
+		return pos
+	}
+	return adr.Positions.locate("body")
+}
+
 // ValidateRationalePattern checks if an ADR follows the mandatory rationale pattern.
 // Returns warnings for missing rationale sections.
 func ValidateRationalePattern(adr *ADR, result *ValidationResult) {
@@ -183,19 +274,21 @@ func ValidateRationalePattern(adr *ADR, result *ValidationResult) {
 
 	// If there's an adopted heading, check for rationale sections
 	if hasAdoptedHeading {
+		pos := rationalePosition(adr, adoptedTitlePattern)
 		if !hasAdoptedBecause {
-			result.addWarning("rationale", "missing 'Adopted because:' section for adopted option", "missing_adopted_because")
+			result.add(SeverityWarning, "rationale", "missing 'Adopted because:' section for adopted option", "missing_adopted_because", pos)
 		}
 		if !hasAdoptedDespite {
-			result.addWarning("rationale", "missing 'Adopted despite:' section for adopted option", "missing_adopted_despite")
+			result.add(SeverityWarning, "rationale", "missing 'Adopted despite:' section for adopted option", "missing_adopted_despite", pos)
 		}
 	} else if hasSection(body, "Decision") {
 		// Decision section exists but no explicit adopted option
+		pos := rationalePosition(adr, decisionTitlePattern)
 		if !hasAdoptedBecause {
-			result.addWarning("rationale", "missing 'Adopted because:' section in Decision", "missing_adopted_because")
+			result.add(SeverityWarning, "rationale", "missing 'Adopted because:' section in Decision", "missing_adopted_because", pos)
 		}
 		if !hasAdoptedDespite {
-			result.addWarning("rationale", "missing 'Adopted despite:' section in Decision", "missing_adopted_despite")
+			result.add(SeverityWarning, "rationale", "missing 'Adopted despite:' section in Decision", "missing_adopted_despite", pos)
 		}
 	}
 
@@ -206,11 +299,12 @@ func ValidateRationalePattern(adr *ADR, result *ValidationResult) {
 
 	// If there are rejected alternatives, check for rationale sections
 	if hasRejectedHeading {
+		pos := rationalePosition(adr, rejectedTitlePattern)
 		if !hasRejectedBecause {
-			result.addWarning("rationale", "missing 'Rejected because:' section for rejected alternative", "missing_rejected_because")
+			result.add(SeverityWarning, "rationale", "missing 'Rejected because:' section for rejected alternative", "missing_rejected_because", pos)
 		}
 		if !hasRejectedDespite {
-			result.addWarning("rationale", "missing 'Rejected despite:' section for rejected alternative", "missing_rejected_despite")
+			result.add(SeverityWarning, "rationale", "missing 'Rejected despite:' section for rejected alternative", "missing_rejected_despite", pos)
 		}
 	}
 	// Note: We only warn about missing alternative rationale when there's an explicit
@@ -127,6 +127,22 @@ What happens.`,
 			wantValid: false,
 			wantErrs:  1, // Invalid format
 		},
+		{
+			name: "invalid scope paths",
+			adr: &ADR{
+				Filename: "0001-test.md",
+				Frontmatter: Frontmatter{
+					ADRID:  "ADR-0001",
+					Title:  "Test",
+					Status: StatusAdopted,
+					Date:   "2026-01-16",
+					Scope:  Scope{Paths: []string{"src/{api,web/**", "!", "src/**", "!src/gen/**"}},
+				},
+				Body: "## Context\n## Decision\n## Alternatives Considered\n## Consequences",
+			},
+			wantValid: false,
+			wantErrs:  2, // Unclosed brace, empty exclusion
+		},
 	}
 
 	for _, tt := range tests {
@@ -514,3 +530,97 @@ Just some decision.
 		})
 	}
 }
+
+func TestValidateWithOptions(t *testing.T) {
+	base := func() *ADR {
+		return &ADR{
+			Filename: "0001-test.md",
+			Frontmatter: Frontmatter{
+				ADRID:  "DEC-0001",
+				Title:  "Test",
+				Status: StatusAdopted,
+				Date:   "2026-01-16",
+				Tags:   []string{"api", "storage"},
+			},
+			Body: "# DEC-0001: Test\n## Context\n## Decision\n## Security\n",
+		}
+	}
+
+	tests := []struct {
+		name      string
+		opts      ValidateOptions
+		wantCodes []string
+		wantMsgs  []string
+	}{
+		{
+			name: "custom prefix and sections",
+			opts: ValidateOptions{IDPrefix: "DEC", RequiredSections: []string{"Context", "Decision", "Security"}},
+		},
+		{
+			name:     "default prefix rejects custom IDs",
+			opts:     ValidateOptions{RequiredSections: []string{"Context"}},
+			wantMsgs: []string{"must match pattern ADR-NNNN"},
+		},
+		{
+			name:     "default sections",
+			opts:     ValidateOptions{IDPrefix: "DEC"},
+			wantMsgs: []string{"missing required section: Alternatives Considered", "missing required section: Consequences"},
+		},
+		{
+			name:      "tag not allowed",
+			opts:      ValidateOptions{IDPrefix: "DEC", RequiredSections: []string{}, AllowedTags: []string{"api"}},
+			wantCodes: []string{"tag_not_allowed"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := ValidateWithOptions(base(), tt.opts)
+
+			var codes, msgs []string
+			for _, e := range result.Errors {
+				if e.Code != "" {
+					codes = append(codes, e.Code)
+				}
+				msgs = append(msgs, e.Message)
+			}
+			if len(msgs) != len(tt.wantCodes)+len(tt.wantMsgs) {
+				t.Fatalf("ValidateWithOptions() errors = %v", result.Errors)
+			}
+			for i, code := range tt.wantCodes {
+				if codes[i] != code {
+					t.Errorf("error code = %q, want %q", codes[i], code)
+				}
+			}
+			for _, want := range tt.wantMsgs {
+				found := false
+				for _, m := range msgs {
+					found = found || m == want
+				}
+				if !found {
+					t.Errorf("missing error %q in %v", want, msgs)
+				}
+			}
+		})
+	}
+}
+
+func TestIsADRID(t *testing.T) {
+	tests := []struct {
+		id, prefix string
+		want       bool
+	}{
+		{"ADR-0001", "ADR", true},
+		{"DEC-1234", "DEC", true},
+		{"ADR-001", "ADR", false},
+		{"ADR-00012", "ADR", false},
+		{"ADR-00a1", "ADR", false},
+		{"ADRX-0001", "ADR", false},
+		{"DEC-0001", "ADR", false},
+	}
+	for _, tt := range tests {
+		if got := isADRID(tt.id, tt.prefix); got != tt.want {
+			t.Errorf("isADRID(%q, %q) = %v, want %v", tt.id, tt.prefix, got, tt.want)
+		}
+	}
+}
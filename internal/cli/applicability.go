@@ -0,0 +1,231 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/sventorben/decider/internal/adr"
+	"github.com/sventorben/decider/internal/enforce"
+	"github.com/sventorben/decider/internal/git"
+	"github.com/sventorben/decider/internal/glob"
+)
+
+// DefaultStatuses are the statuses of the ADRs that check diff and explain
+// apply to changes unless told otherwise.
+var DefaultStatuses = []string{string(adr.StatusAdopted)}
+
+// HistoricalADR is an ADR whose scope covers changed files but whose status
+// is not applied. It is listed for context, without its constraints.
+type HistoricalADR struct {
+	ADRID        string   `json:"adr_id"`
+	Title        string   `json:"title"`
+	Status       string   `json:"status"`
+	File         string   `json:"file"`
+	MatchedFiles []string `json:"matched_files"`
+
+	// SupersededBy is the successor chain of a superseded ADR: the ADRs
+	// reached by following superseded_by links, nearest first.
+	SupersededBy []string `json:"superseded_by,omitempty"`
+}
+
+// changeMatch is how the scope of an ADR relates to one change.
+type changeMatch struct {
+	change int // Index into the changes
+	match  glob.ScopeMatch
+	path   string // The path matched: the change's, or its old path
+}
+
+// appliedADR is an ADR that applies to changes, through its own scope or as
+// the successor of superseded ADRs whose scope covers them.
+type appliedADR struct {
+	adr *adr.ADR
+	via []*adr.ADR // Superseded ADRs it applies in place of
+}
+
+// historicalADR is a matched ADR whose status is not applied.
+type historicalADR struct {
+	adr        *adr.ADR
+	successors []*adr.ADR
+}
+
+// applicability is how the scopes of ADRs cover a list of changes.
+type applicability struct {
+	applied    []appliedADR    // In ADR order
+	historical []historicalADR // In ADR order
+
+	// scoped holds, for each ADR, the changes its scope covers and the
+	// changes that an exclusion took out of it.
+	scoped map[*adr.ADR][]changeMatch
+}
+
+// resolveApplicability matches changes against the scope of every ADR.
+// ADRs with one of statuses (DefaultStatuses when empty) whose scope covers
+// a change apply; other covering ADRs are historical. A superseded ADR is
+// resolved to its successor chain, and the successors with an applied
+// status apply in its place.
+func resolveApplicability(adrs []*adr.ADR, changes []git.Change, statuses []string) (*applicability, error) {
+	if len(statuses) == 0 {
+		statuses = DefaultStatuses
+	}
+	applies := make(map[adr.Status]bool)
+	for _, s := range statuses {
+		status, err := adr.ParseStatus(s)
+		if err != nil {
+			return nil, err
+		}
+		applies[status] = true
+	}
+
+	result := &applicability{scoped: make(map[*adr.ADR][]changeMatch)}
+	byID := make(map[string]*adr.ADR)
+	for _, a := range adrs {
+		byID[a.Frontmatter.ADRID] = a
+		for i, c := range changes {
+			m, path := matchChange(a.Frontmatter.Scope.Paths, c)
+			if m.Matched || m.ExcludedBy != "" {
+				result.scoped[a] = append(result.scoped[a], changeMatch{change: i, match: m, path: path})
+			}
+		}
+	}
+
+	direct := make(map[*adr.ADR]bool)
+	via := make(map[*adr.ADR][]*adr.ADR)
+	for _, a := range adrs {
+		if !result.covers(a) {
+			continue
+		}
+		if applies[a.Frontmatter.Status] {
+			direct[a] = true
+			continue
+		}
+		h := historicalADR{adr: a}
+		if a.Frontmatter.Status == adr.StatusSuperseded {
+			h.successors = successorChain(a, byID)
+			for _, s := range h.successors {
+				if applies[s.Frontmatter.Status] {
+					via[s] = append(via[s], a)
+				}
+			}
+		}
+		result.historical = append(result.historical, h)
+	}
+
+	for _, a := range adrs {
+		if direct[a] || len(via[a]) > 0 {
+			result.applied = append(result.applied, appliedADR{adr: a, via: via[a]})
+		}
+	}
+	return result, nil
+}
+
+// covers reports whether the scope of a covers at least one change.
+func (ap *applicability) covers(a *adr.ADR) bool {
+	for _, cm := range ap.scoped[a] {
+		if cm.match.Matched {
+			return true
+		}
+	}
+	return false
+}
+
+// coveredChanges returns the indexes of the changes that the scope of a, or
+// of one of the ADRs it applies in place of, covers, in order.
+func (ap *applicability) coveredChanges(aa appliedADR) []int {
+	covered := make(map[int]bool)
+	for _, a := range append([]*adr.ADR{aa.adr}, aa.via...) {
+		for _, cm := range ap.scoped[a] {
+			if cm.match.Matched {
+				covered[cm.change] = true
+			}
+		}
+	}
+	var indexes []int
+	for i := 0; len(indexes) < len(covered); i++ {
+		if covered[i] {
+			indexes = append(indexes, i)
+		}
+	}
+	return indexes
+}
+
+// enforced returns the applied ADRs as the targets whose structured
+// constraints are enforced on the changes, so that every violation belongs
+// to an ADR in the report. A successor also governs the changed files in the
+// scope of the superseded ADRs it applies in place of.
+func (ap *applicability) enforced() []enforce.Target {
+	targets := make([]enforce.Target, 0, len(ap.applied))
+	for _, aa := range ap.applied {
+		t := enforce.Target{ADR: aa.adr}
+		for _, old := range aa.via {
+			for _, cm := range ap.scoped[old] {
+				if cm.match.Matched && !contains(t.Inherited, cm.path) {
+					t.Inherited = append(t.Inherited, cm.path)
+				}
+			}
+		}
+		targets = append(targets, t)
+	}
+	return targets
+}
+
+// historicalADRs describes the historical ADRs for output.
+func (ap *applicability) historicalADRs(root string, changes []git.Change) []HistoricalADR {
+	var result []HistoricalADR
+	for _, h := range ap.historical {
+		entry := HistoricalADR{
+			ADRID:        h.adr.Frontmatter.ADRID,
+			Title:        h.adr.Frontmatter.Title,
+			Status:       string(h.adr.Frontmatter.Status),
+			File:         relativePath(root, h.adr.FilePath),
+			MatchedFiles: []string{},
+			SupersededBy: adrIDs(h.successors),
+		}
+		for _, cm := range ap.scoped[h.adr] {
+			if cm.match.Matched {
+				entry.MatchedFiles = append(entry.MatchedFiles, changes[cm.change].Path)
+			}
+		}
+		result = append(result, entry)
+	}
+	return result
+}
+
+// successorChain follows the superseded_by links of a breadth-first and
+// returns the ADRs it reaches, nearest first. Links to missing ADRs and
+// cycles are skipped.
+func successorChain(a *adr.ADR, byID map[string]*adr.ADR) []*adr.ADR {
+	var chain []*adr.ADR
+	seen := map[*adr.ADR]bool{a: true}
+	queue := []*adr.ADR{a}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, id := range current.Frontmatter.SupersededBy {
+			next, ok := byID[id]
+			if !ok || seen[next] {
+				continue
+			}
+			seen[next] = true
+			chain = append(chain, next)
+			queue = append(queue, next)
+		}
+	}
+	return chain
+}
+
+func adrIDs(adrs []*adr.ADR) []string {
+	var ids []string
+	for _, a := range adrs {
+		ids = append(ids, a.Frontmatter.ADRID)
+	}
+	return ids
+}
+
+// supersessionNote describes the successor chain of a historical ADR, e.g.
+// " → ADR-0004 → ADR-0007".
+func supersessionNote(successors []string) string {
+	var note string
+	for _, id := range successors {
+		note += fmt.Sprintf(" → %s", id)
+	}
+	return note
+}
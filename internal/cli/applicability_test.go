@@ -0,0 +1,275 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/sventorben/decider/internal/adr"
+)
+
+// writeSupersessionChain writes ADR-0001, superseded by ADR-0002, which is
+// superseded by ADR-0003, a rejected ADR-0004 and a proposed ADR-0005.
+func writeSupersessionChain(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	writeTestADR(t, dir, "0001-redux.md", `
+adr_id: ADR-0001
+title: Redux
+status: superseded
+date: 2026-01-16
+superseded_by: [ADR-0002]
+scope:
+  paths: ["web/store/**"]
+constraints:
+  - "Use Redux for all state"
+`)
+	writeTestADR(t, dir, "0002-mobx.md", `
+adr_id: ADR-0002
+title: MobX
+status: superseded
+date: 2026-01-16
+supersedes: [ADR-0001]
+superseded_by: [ADR-0003]
+scope:
+  paths: ["web/state/**"]
+`)
+	writeTestADR(t, dir, "0003-signals.md", `
+adr_id: ADR-0003
+title: Signals
+status: adopted
+date: 2026-01-16
+supersedes: [ADR-0002]
+scope:
+  paths: ["web/signals/**"]
+constraints:
+  - "Use signals for shared state"
+`)
+	writeTestADR(t, dir, "0004-graphql.md", `
+adr_id: ADR-0004
+title: GraphQL
+status: rejected
+date: 2026-01-16
+scope:
+  paths: ["web/**"]
+constraints:
+  - "Use GraphQL"
+`)
+	writeTestADR(t, dir, "0005-ssr.md", `
+adr_id: ADR-0005
+title: Server-side Rendering
+status: proposed
+date: 2026-01-16
+scope:
+  paths: ["web/pages/**"]
+`)
+	return dir
+}
+
+func TestRunCheckDiffStatuses(t *testing.T) {
+	dir := writeSupersessionChain(t)
+	files := []string{"web/store/todos.ts", "web/pages/index.ts"}
+
+	out := testOutput()
+	result, err := RunCheckDiff(&CheckDiffConfig{Dir: dir, Files: files, Format: FormatText, Output: out})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The superseded ADR resolves to its adopted successor
+	if len(result.ApplicableADRs) != 1 {
+		t.Fatalf("ApplicableADRs = %+v, want ADR-0003 only", result.ApplicableADRs)
+	}
+	aa := result.ApplicableADRs[0]
+	if aa.ADRID != "ADR-0003" || !reflect.DeepEqual(aa.Via, []string{"ADR-0001"}) ||
+		!reflect.DeepEqual(aa.MatchedFiles, []string{"web/store/todos.ts"}) || len(aa.MatchedPaths) != 0 {
+		t.Errorf("ApplicableADRs[0] = %+v", aa)
+	}
+	if !reflect.DeepEqual(result.Summary.AllConstraints, []string{"Use signals for shared state"}) {
+		t.Errorf("AllConstraints = %v, want only the successor's", result.Summary.AllConstraints)
+	}
+
+	file := func(name string) string { return relativePath(".", filepath.Join(dir, name)) }
+	want := []HistoricalADR{
+		{ADRID: "ADR-0001", Title: "Redux", Status: "superseded", File: file("0001-redux.md"),
+			MatchedFiles: []string{"web/store/todos.ts"}, SupersededBy: []string{"ADR-0002", "ADR-0003"}},
+		{ADRID: "ADR-0004", Title: "GraphQL", Status: "rejected", File: file("0004-graphql.md"),
+			MatchedFiles: []string{"web/store/todos.ts", "web/pages/index.ts"}},
+		{ADRID: "ADR-0005", Title: "Server-side Rendering", Status: "proposed", File: file("0005-ssr.md"),
+			MatchedFiles: []string{"web/pages/index.ts"}},
+	}
+	if !reflect.DeepEqual(result.Historical, want) {
+		t.Errorf("Historical = %+v\nwant %+v", result.Historical, want)
+	}
+
+	text := out.Writer.(*bytes.Buffer).String()
+	for _, want := range []string{"Supersedes: ADR-0001", "## Historical Context", "- ADR-0001: Redux (superseded → ADR-0002 → ADR-0003, 1 file(s))"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("output lacks %q:\n%s", want, text)
+		}
+	}
+	if strings.Contains(text, "Use GraphQL") || strings.Contains(text, "Use Redux") {
+		t.Errorf("output lists constraints of ADRs that do not apply:\n%s", text)
+	}
+
+	// Applying proposed ADRs too
+	result, err = RunCheckDiff(&CheckDiffConfig{Dir: dir, Files: files, Statuses: []string{"adopted", "Proposed"}, Format: FormatJSON, Output: testOutput()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.ApplicableADRs) != 2 || result.ApplicableADRs[1].ADRID != "ADR-0005" || len(result.Historical) != 2 {
+		t.Errorf("with proposed: ApplicableADRs = %+v, Historical = %+v", result.ApplicableADRs, result.Historical)
+	}
+
+	if _, err := RunCheckDiff(&CheckDiffConfig{Dir: dir, Files: files, Statuses: []string{"obsolete"}, Format: FormatJSON, Output: testOutput()}); err == nil {
+		t.Error("unknown status: expected an error")
+	}
+}
+
+func TestRunCheckDiffEnforcesAppliedADRs(t *testing.T) {
+	dir := t.TempDir()
+	writeTestADR(t, dir, "0001-redux.md", `
+adr_id: ADR-0001
+title: Redux
+status: superseded
+date: 2026-01-16
+superseded_by: [ADR-0002]
+scope:
+  paths: ["web/store/**"]
+`)
+	writeTestADR(t, dir, "0002-signals.md", `
+adr_id: ADR-0002
+title: Signals
+status: adopted
+date: 2026-01-16
+supersedes: [ADR-0001]
+scope:
+  paths: ["web/signals/**"]
+constraints:
+  - forbid_regex: createStore
+`)
+	writeTestADR(t, dir, "0003-ssr.md", `
+adr_id: ADR-0003
+title: Server-side Rendering
+status: proposed
+date: 2026-01-16
+scope:
+  paths: ["web/pages/**"]
+constraints:
+  - forbid_regex: window\.
+`)
+	writeTestADR(t, dir, "0004-no-globals.md", `
+adr_id: ADR-0004
+title: No Globals
+status: adopted
+date: 2026-01-16
+scope:
+  paths: ["api/**"]
+constraints:
+  - forbid_regex: const
+    paths: ["web/**"]
+`)
+	root := t.TempDir()
+	for name, content := range map[string]string{
+		"web/store/todos.ts": "export const store = createStore();\n",
+		"web/pages/index.ts": "const width = window.innerWidth;\n",
+	} {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	files := []string{"web/store/todos.ts", "web/pages/index.ts"}
+
+	tests := []struct {
+		statuses []string
+		want     []string
+	}{
+		// The successor's rules cover the superseded ADR's scope; ADR-0004
+		// does not apply, so its rule is not enforced
+		{nil, []string{"ADR-0002 web/store/todos.ts"}},
+		{[]string{"adopted", "proposed"}, []string{"ADR-0002 web/store/todos.ts", "ADR-0003 web/pages/index.ts"}},
+	}
+	for _, tt := range tests {
+		result, err := RunCheckDiff(&CheckDiffConfig{Dir: dir, Files: files, Root: root, Statuses: tt.statuses, Format: FormatJSON, Output: testOutput()})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got []string
+		for _, v := range result.Violations {
+			got = append(got, v.ADRID+" "+v.File)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("statuses %v: violations = %v, want %v", tt.statuses, got, tt.want)
+		}
+	}
+}
+
+func TestRunExplainStatuses(t *testing.T) {
+	dir := writeSupersessionChain(t)
+
+	out := testOutput()
+	result, err := RunExplain(&ExplainConfig{
+		Dir:    dir,
+		Files:  []string{"web/store/todos.ts", "web/signals/count.ts"},
+		Format: FormatMarkdown,
+		Output: out,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(result.Explanations) != 1 {
+		t.Fatalf("Explanations = %+v, want ADR-0003 only", result.Explanations)
+	}
+	exp := result.Explanations[0]
+	if len(exp.Matches) != 2 || exp.Matches[1].Reason != "Supersedes ADR-0001, which covers it: File is anywhere under 'web/store/'" {
+		t.Errorf("Matches = %+v", exp.Matches)
+	}
+	if len(result.Historical) != 2 || result.Historical[0].ADRID != "ADR-0001" || result.Historical[1].ADRID != "ADR-0004" {
+		t.Errorf("Historical = %+v", result.Historical)
+	}
+
+	markdown := out.Writer.(*bytes.Buffer).String()
+	for _, want := range []string{"Supersedes ADR-0001\n", "### Historical context (2)", "(rejected, 2 file(s))"} {
+		if !strings.Contains(markdown, want) {
+			t.Errorf("markdown lacks %q:\n%s", want, markdown)
+		}
+	}
+}
+
+func TestSuccessorChain(t *testing.T) {
+	newADR := func(id string, supersededBy ...string) *adr.ADR {
+		return &adr.ADR{Frontmatter: adr.Frontmatter{ADRID: id, SupersededBy: supersededBy}}
+	}
+	byID := make(map[string]*adr.ADR)
+	for _, a := range []*adr.ADR{
+		newADR("ADR-0001", "ADR-0002", "ADR-0009"),
+		newADR("ADR-0002", "ADR-0001", "ADR-0003"),
+		newADR("ADR-0003", "ADR-0004"),
+		newADR("ADR-0004"),
+	} {
+		byID[a.Frontmatter.ADRID] = a
+	}
+
+	// The missing ADR-0009 and the cycle back to ADR-0001 are skipped
+	tests := []struct {
+		id   string
+		want []string
+	}{
+		{"ADR-0001", []string{"ADR-0002", "ADR-0003", "ADR-0004"}},
+		{"ADR-0003", []string{"ADR-0004"}},
+		{"ADR-0004", nil},
+	}
+	for _, tt := range tests {
+		if got := adrIDs(successorChain(byID[tt.id], byID)); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("successorChain(%s) = %v, want %v", tt.id, got, tt.want)
+		}
+	}
+}
@@ -2,13 +2,13 @@ package cli
 
 import (
 	"fmt"
-	"os/exec"
 	"path/filepath"
 	"strings"
 
 	"github.com/sventorben/decider/internal/adr"
+	"github.com/sventorben/decider/internal/enforce"
+	"github.com/sventorben/decider/internal/git"
 	"github.com/sventorben/decider/internal/glob"
-	"github.com/sventorben/decider/internal/validate"
 )
 
 // CheckADRConfig holds configuration for the check adr command.
@@ -17,6 +17,23 @@ type CheckADRConfig struct {
 	Strict bool
 	Format OutputFormat
 	Output *Output
+
+	// Project-specific validation rules; defaults apply when empty.
+	RequiredSections []string
+	AllowedTags      []string
+	IDPrefix         string
+
+	// Files, when non-nil, limits the check to these ADR filenames. All ADRs
+	// are still loaded so that references between them are resolved.
+	Files []string
+
+	// ADRs, when non-nil, are checked instead of the ADRs in Dir, e.g. as
+	// staged in the index.
+	ADRs []*adr.ADR
+
+	// Root is the directory SARIF locations are relative to (default: the
+	// repository root).
+	Root string
 }
 
 // CheckADRResult holds the result of the check adr command.
@@ -35,6 +52,16 @@ type CheckADRError struct {
 	Message  string `json:"message"`
 	Severity string `json:"severity"` // "error" or "warning"
 	Code     string `json:"code"`     // Machine-readable error code
+	Line     int    `json:"line,omitempty"`
+	Column   int    `json:"column,omitempty"`
+}
+
+// location returns "file:line:column", or just the file if the position is unknown.
+func (e CheckADRError) location() string {
+	if e.Line == 0 {
+		return e.File
+	}
+	return fmt.Sprintf("%s:%d:%d", e.File, e.Line, e.Column)
 }
 
 // CheckADRFileResult represents the validation result for a single file.
@@ -45,20 +72,40 @@ type CheckADRFileResult struct {
 	Warnings []CheckADRError `json:"warnings,omitempty"`
 }
 
-// RunCheckADR validates all ADRs in the directory.
+// RunCheckADR validates all ADRs in the directory, both individually and
+// for referential integrity across the whole set.
 func RunCheckADR(cfg *CheckADRConfig) (*CheckADRResult, error) {
-	adrs, err := adr.LoadAllADRs(cfg.Dir)
-	if err != nil {
-		return nil, fmt.Errorf("loading ADRs: %w", err)
+	adrs := cfg.ADRs
+	if adrs == nil {
+		var err error
+		if adrs, err = adr.LoadAllADRs(cfg.Dir); err != nil {
+			return nil, fmt.Errorf("loading ADRs: %w", err)
+		}
 	}
 
 	result := &CheckADRResult{
 		Valid: true,
-		Count: len(adrs),
+	}
+
+	// Repository-level checks (references between ADRs), grouped by file
+	repoErrors := make(map[string][]adr.ValidationError)
+	for _, ve := range adr.ValidateRepository(adrs) {
+		repoErrors[ve.File] = append(repoErrors[ve.File], ve)
+	}
+
+	opts := adr.ValidateOptions{
+		RequiredSections: cfg.RequiredSections,
+		AllowedTags:      cfg.AllowedTags,
+		IDPrefix:         cfg.IDPrefix,
 	}
 
 	for _, a := range adrs {
-		vr := adr.Validate(a)
+		if cfg.Files != nil && !contains(cfg.Files, a.Filename) {
+			continue
+		}
+		result.Count++
+
+		vr := adr.ValidateWithOptions(a, opts)
 		fileResult := CheckADRFileResult{
 			File:  a.Filename,
 			Valid: vr.IsValid(),
@@ -68,27 +115,24 @@ func RunCheckADR(cfg *CheckADRConfig) (*CheckADRResult, error) {
 		if !vr.IsValid() {
 			result.Valid = false
 			for _, ve := range vr.Errors {
-				checkErr := CheckADRError{
-					File:     ve.File,
-					Field:    ve.Field,
-					Message:  ve.Message,
-					Severity: string(ve.Severity),
-					Code:     ve.Code,
-				}
+				checkErr := newCheckADRError(ve)
 				fileResult.Errors = append(fileResult.Errors, checkErr)
 				result.Errors = append(result.Errors, checkErr)
 			}
 		}
 
+		// Process cross-ADR reference errors
+		for _, ve := range repoErrors[a.Filename] {
+			checkErr := newCheckADRError(ve)
+			fileResult.Valid = false
+			fileResult.Errors = append(fileResult.Errors, checkErr)
+			result.Errors = append(result.Errors, checkErr)
+			result.Valid = false
+		}
+
 		// Process warnings
 		for _, vw := range vr.Warnings {
-			checkWarn := CheckADRError{
-				File:     vw.File,
-				Field:    vw.Field,
-				Message:  vw.Message,
-				Severity: string(vw.Severity),
-				Code:     vw.Code,
-			}
+			checkWarn := newCheckADRError(vw)
 			fileResult.Warnings = append(fileResult.Warnings, checkWarn)
 			result.Warnings = append(result.Warnings, checkWarn)
 		}
@@ -103,7 +147,9 @@ func RunCheckADR(cfg *CheckADRConfig) (*CheckADRResult, error) {
 	}
 
 	// Output
-	if cfg.Format == FormatTOON || cfg.Format == FormatJSON {
+	if cfg.Format == FormatSARIF {
+		_ = cfg.Output.PrintJSON(checkADRSARIF(sarifRoot(cfg.Root), cfg.Dir, result))
+	} else if cfg.Format == FormatTOON || cfg.Format == FormatJSON {
 		_ = cfg.Output.PrintStructured(result)
 	} else {
 		hasErrors := len(result.Errors) > 0
@@ -115,7 +161,7 @@ func RunCheckADR(cfg *CheckADRConfig) (*CheckADRResult, error) {
 			if hasErrors {
 				cfg.Output.Error("Found %d validation error(s):", len(result.Errors))
 				for _, e := range result.Errors {
-					cfg.Output.Println("  [error] %s: %s: %s", e.File, e.Field, e.Message)
+					cfg.Output.Println("  [error] %s: %s: %s", e.location(), e.Field, e.Message)
 				}
 			}
 			if hasWarnings {
@@ -125,7 +171,7 @@ func RunCheckADR(cfg *CheckADRConfig) (*CheckADRResult, error) {
 					cfg.Output.Warn("Found %d warning(s):", len(result.Warnings))
 				}
 				for _, w := range result.Warnings {
-					cfg.Output.Println("  [warning] %s: %s: %s", w.File, w.Field, w.Message)
+					cfg.Output.Println("  [warning] %s: %s: %s", w.location(), w.Field, w.Message)
 				}
 			}
 			if !hasErrors && hasWarnings && !cfg.Strict {
@@ -137,52 +183,126 @@ func RunCheckADR(cfg *CheckADRConfig) (*CheckADRResult, error) {
 	return result, nil
 }
 
+func newCheckADRError(ve adr.ValidationError) CheckADRError {
+	return CheckADRError{
+		File:     ve.File,
+		Field:    ve.Field,
+		Message:  ve.Message,
+		Severity: string(ve.Severity),
+		Code:     ve.Code,
+		Line:     ve.Line,
+		Column:   ve.Column,
+	}
+}
+
 // CheckDiffConfig holds configuration for the check diff command.
 type CheckDiffConfig struct {
 	Dir    string
-	Base   string
 	Format OutputFormat
 	Output *Output
+
+	// Base, Head, Staged and WorkingTree select the changes git reports;
+	// see git.Range.
+	Base        string
+	Head        string
+	Staged      bool
+	WorkingTree bool
+
+	// Files, when non-nil, are used as the changed files instead of asking
+	// git. They are relative to Root (default: the working directory).
+	Files []string
+	Root  string
+
+	// LinkBase is prepended to ADR file paths in Markdown links.
+	LinkBase string
+
+	// RequireAck requires every applicable ADR to be named in an ADR-Ack
+	// trailer of a commit in Base..Head (Head defaults to HEAD).
+	RequireAck bool
+
+	// Statuses are the statuses of the ADRs that apply (default:
+	// DefaultStatuses). Other ADRs whose scope covers a change are
+	// listed as historical context.
+	Statuses []string
+
+	// ADRs, when non-nil, are used instead of the ADRs in Dir, e.g. as
+	// staged in the index.
+	ADRs []*adr.ADR
 }
 
 // CheckDiffResult holds the result of the check diff command.
 type CheckDiffResult struct {
-	ChangedFiles   []string           `json:"changed_files"`
-	ApplicableADRs []ApplicableADR    `json:"applicable_adrs"`
-	Summary        ConstraintsSummary `json:"summary"`
+	ChangedFiles   []string            `json:"changed_files"`
+	ApplicableADRs []ApplicableADR     `json:"applicable_adrs"`
+	Violations     []enforce.Violation `json:"violations,omitempty"`
+	Unacknowledged []string            `json:"unacknowledged,omitempty"` // With RequireAck only
+	Historical     []HistoricalADR     `json:"historical,omitempty"`
+	Summary        ConstraintsSummary  `json:"summary"`
 }
 
 // ApplicableADR represents an ADR that applies to changed files.
 type ApplicableADR struct {
-	ADRID        string   `json:"adr_id"`
-	Title        string   `json:"title"`
-	MatchedPaths []string `json:"matched_paths"`
-	MatchedFiles []string `json:"matched_files"`
-	Constraints  []string `json:"constraints,omitempty"`
-	Invariants   []string `json:"invariants,omitempty"`
+	ADRID        string       `json:"adr_id"`
+	Title        string       `json:"title"`
+	File         string       `json:"file"`
+	MatchedPaths []string     `json:"matched_paths"`
+	MatchedFiles []string     `json:"matched_files"`
+	Changes      []FileChange `json:"changes"`
+	Constraints  []string     `json:"constraints,omitempty"`
+	Invariants   []string     `json:"invariants,omitempty"`
+
+	// Via lists the superseded ADRs whose scope covers changed files and
+	// that this ADR succeeds.
+	Via []string `json:"via,omitempty"`
+
+	// With RequireAck, the commits whose ADR-Ack trailers name this ADR
+	Acknowledged   bool     `json:"acknowledged,omitempty"`
+	AcknowledgedBy []string `json:"acknowledged_by,omitempty"`
+}
+
+// FileChange is a changed file matched by an ADR. Renamed files match when
+// either their old or their new path is in scope.
+type FileChange struct {
+	File    string `json:"file"`
+	OldFile string `json:"old_file,omitempty"` // Renamed files only
+	Change  string `json:"change,omitempty"`   // added, modified, deleted or renamed; empty for --files
 }
 
 // ConstraintsSummary provides a summary of all constraints that apply.
 type ConstraintsSummary struct {
-	TotalADRs        int      `json:"total_adrs"`
-	TotalConstraints int      `json:"total_constraints"`
-	TotalInvariants  int      `json:"total_invariants"`
-	AllConstraints   []string `json:"all_constraints,omitempty"`
-	AllInvariants    []string `json:"all_invariants,omitempty"`
+	TotalADRs           int      `json:"total_adrs"`
+	TotalConstraints    int      `json:"total_constraints"`
+	TotalInvariants     int      `json:"total_invariants"`
+	TotalViolations     int      `json:"total_violations"`
+	TotalUnacknowledged int      `json:"total_unacknowledged,omitempty"`
+	AllConstraints      []string `json:"all_constraints,omitempty"`
+	AllInvariants       []string `json:"all_invariants,omitempty"`
 }
 
-// RunCheckDiff finds ADRs applicable to changed files.
+// RunCheckDiff finds ADRs applicable to changed files and evaluates their
+// structured constraints against the files' contents.
 func RunCheckDiff(cfg *CheckDiffConfig) (*CheckDiffResult, error) {
-	// Get changed files from git
-	changedFiles, err := getGitDiff(cfg.Base)
+	if cfg.RequireAck && (cfg.Base == "" || cfg.Staged || cfg.WorkingTree || cfg.Files != nil) {
+		return nil, fmt.Errorf("acknowledgments are read from commits and need a commit range (--base, optionally with --head)")
+	}
+
+	r := git.Range{Base: cfg.Base, Head: cfg.Head, Staged: cfg.Staged, WorkingTree: cfg.WorkingTree}
+	changes, err := listChanges(cfg.Files, r)
 	if err != nil {
 		return nil, fmt.Errorf("getting git diff: %w", err)
 	}
+	root, err := changesRoot(cfg.Root, cfg.Files)
+	if err != nil {
+		return nil, err
+	}
+	changedFiles := changedPaths(changes)
 
 	// Load all ADRs
-	adrs, err := adr.LoadAllADRs(cfg.Dir)
-	if err != nil {
-		return nil, fmt.Errorf("loading ADRs: %w", err)
+	adrs := cfg.ADRs
+	if adrs == nil {
+		if adrs, err = adr.LoadAllADRs(cfg.Dir); err != nil {
+			return nil, fmt.Errorf("loading ADRs: %w", err)
+		}
 	}
 
 	result := &CheckDiffResult{
@@ -190,40 +310,56 @@ func RunCheckDiff(cfg *CheckDiffConfig) (*CheckDiffResult, error) {
 	}
 
 	// Find applicable ADRs
-	for _, a := range adrs {
-		if len(a.Frontmatter.Scope.Paths) == 0 {
-			continue
+	ap, err := resolveApplicability(adrs, changes, cfg.Statuses)
+	if err != nil {
+		return nil, err
+	}
+	for _, aa := range ap.applied {
+		a := aa.adr
+		applicable := ApplicableADR{
+			ADRID:        a.Frontmatter.ADRID,
+			Title:        a.Frontmatter.Title,
+			File:         relativePath(root, a.FilePath),
+			MatchedPaths: []string{},
+			Via:          adrIDs(aa.via),
+			Constraints:  adr.ConstraintStrings(a.Frontmatter.Constraints),
+			Invariants:   a.Frontmatter.Invariants,
 		}
-
-		var matchedFiles []string
-		var matchedPaths []string
-
-		for _, changedFile := range changedFiles {
-			for _, scopePath := range a.Frontmatter.Scope.Paths {
-				if glob.Match(scopePath, changedFile) {
-					matchedFiles = append(matchedFiles, changedFile)
-					if !contains(matchedPaths, scopePath) {
-						matchedPaths = append(matchedPaths, scopePath)
-					}
-					break
-				}
+		for _, cm := range ap.scoped[a] {
+			if cm.match.Matched && !contains(applicable.MatchedPaths, cm.match.Pattern) {
+				applicable.MatchedPaths = append(applicable.MatchedPaths, cm.match.Pattern)
 			}
 		}
+		for _, i := range ap.coveredChanges(aa) {
+			applicable.MatchedFiles = append(applicable.MatchedFiles, changes[i].Path)
+			applicable.Changes = append(applicable.Changes, newFileChange(changes[i]))
+		}
+		result.ApplicableADRs = append(result.ApplicableADRs, applicable)
+	}
+	result.Historical = ap.historicalADRs(root, changes)
+
+	// Enforce the structured constraints of the ADRs that apply against the
+	// files the changes lead to; a file moved out of scope counts as a
+	// change at its old path too
+	var src enforce.Source = enforce.Dir(root)
+	if snapshot := r.Snapshot(); snapshot != nil && cfg.Files == nil {
+		src = snapshot
+	}
+	result.Violations, err = enforce.Evaluate(ap.enforced(), enforcedPaths(changes), src)
+	if err != nil {
+		return nil, fmt.Errorf("evaluating constraints: %w", err)
+	}
 
-		if len(matchedFiles) > 0 {
-			result.ApplicableADRs = append(result.ApplicableADRs, ApplicableADR{
-				ADRID:        a.Frontmatter.ADRID,
-				Title:        a.Frontmatter.Title,
-				MatchedPaths: matchedPaths,
-				MatchedFiles: matchedFiles,
-				Constraints:  a.Frontmatter.Constraints,
-				Invariants:   a.Frontmatter.Invariants,
-			})
+	if cfg.RequireAck {
+		if err := checkAcknowledgments(result, cfg.Base, cfg.Head); err != nil {
+			return nil, err
 		}
 	}
 
 	// Build summary
 	result.Summary.TotalADRs = len(result.ApplicableADRs)
+	result.Summary.TotalViolations = len(result.Violations)
+	result.Summary.TotalUnacknowledged = len(result.Unacknowledged)
 	for _, aa := range result.ApplicableADRs {
 		result.Summary.TotalConstraints += len(aa.Constraints)
 		result.Summary.TotalInvariants += len(aa.Invariants)
@@ -232,9 +368,16 @@ func RunCheckDiff(cfg *CheckDiffConfig) (*CheckDiffResult, error) {
 	}
 
 	// Output
-	if cfg.Format == FormatTOON || cfg.Format == FormatJSON {
+	switch cfg.Format {
+	case FormatSARIF:
+		_ = cfg.Output.PrintJSON(checkDiffSARIF(sarifRoot(cfg.Root), root, result))
+	case FormatGitHub:
+		_, _ = fmt.Fprint(cfg.Output.Writer, checkDiffGitHub(result))
+	case FormatMarkdown:
+		_, _ = fmt.Fprint(cfg.Output.Writer, checkDiffMarkdown(result, cfg.LinkBase))
+	case FormatTOON, FormatJSON:
 		_ = cfg.Output.PrintStructured(result)
-	} else {
+	default:
 		cfg.Output.Println("Changed files: %d", len(changedFiles))
 		cfg.Output.Println("Applicable ADRs: %d", len(result.ApplicableADRs))
 		cfg.Output.Println("")
@@ -247,7 +390,17 @@ func RunCheckDiff(cfg *CheckDiffConfig) (*CheckDiffResult, error) {
 
 			for _, aa := range result.ApplicableADRs {
 				cfg.Output.Println("### %s: %s", aa.ADRID, aa.Title)
-				cfg.Output.Println("Matches: %s", strings.Join(aa.MatchedPaths, ", "))
+				if len(aa.MatchedPaths) > 0 {
+					cfg.Output.Println("Matches: %s", strings.Join(aa.MatchedPaths, ", "))
+				}
+				if len(aa.Via) > 0 {
+					cfg.Output.Println("Supersedes: %s", strings.Join(aa.Via, ", "))
+				}
+				for _, c := range aa.Changes {
+					if note := changeNote(c.Change, c.OldFile); note != "" {
+						cfg.Output.Println("  %s%s", c.File, note)
+					}
+				}
 
 				if len(aa.Constraints) > 0 {
 					cfg.Output.Println("Constraints:")
@@ -262,35 +415,171 @@ func RunCheckDiff(cfg *CheckDiffConfig) (*CheckDiffResult, error) {
 						cfg.Output.Println("  - %s", i)
 					}
 				}
+				if aa.Acknowledged {
+					cfg.Output.Println("Acknowledged by: %s", strings.Join(shortHashes(aa.AcknowledgedBy), ", "))
+				}
 				cfg.Output.Println("")
 			}
 		}
+
+		if len(result.Historical) > 0 {
+			cfg.Output.Println("## Historical Context")
+			cfg.Output.Println("")
+			cfg.Output.Println("These ADRs also cover the changed files but do not apply; their constraints are not listed.")
+			cfg.Output.Println("")
+			for _, h := range result.Historical {
+				cfg.Output.Println("- %s: %s (%s%s, %d file(s))", h.ADRID, h.Title, h.Status, supersessionNote(h.SupersededBy), len(h.MatchedFiles))
+			}
+			cfg.Output.Println("")
+		}
+
+		if len(result.Violations) > 0 {
+			cfg.Output.Error("Found %d constraint violation(s):", len(result.Violations))
+			for _, v := range result.Violations {
+				cfg.Output.Println("  %s", v)
+			}
+		}
+
+		if len(result.Unacknowledged) > 0 {
+			cfg.Output.Error("Found %d unacknowledged ADR(s); name them in a commit trailer:", len(result.Unacknowledged))
+			cfg.Output.Println("  %s: %s", AckTrailer, strings.Join(result.Unacknowledged, ", "))
+		}
 	}
 
 	return result, nil
 }
 
-func getGitDiff(base string) ([]string, error) {
-	// Validate git ref to prevent injection
-	if err := validate.ValidateGitRef(base); err != nil {
-		return nil, fmt.Errorf("invalid git ref: %w", err)
+// checkAcknowledgments marks the applicable ADRs named in the ADR-Ack
+// trailers of the commits in base..head and lists the others as
+// unacknowledged.
+func checkAcknowledgments(result *CheckDiffResult, base, head string) error {
+	if head == "" {
+		head = "HEAD"
 	}
-
-	cmd := exec.Command("git", "diff", "--name-only", base)
-	output, err := cmd.Output()
+	commits, err := git.RangeTrailers(base, head, AckTrailer)
 	if err != nil {
-		return nil, fmt.Errorf("git diff failed: %w", err)
+		return fmt.Errorf("reading acknowledgments: %w", err)
+	}
+
+	ackedBy := make(map[string][]string)
+	for _, c := range commits {
+		for id := range parseAcks(c.Values) {
+			ackedBy[id] = append(ackedBy[id], c.Commit)
+		}
 	}
+	for i := range result.ApplicableADRs {
+		aa := &result.ApplicableADRs[i]
+		aa.AcknowledgedBy = ackedBy[strings.ToUpper(aa.ADRID)]
+		aa.Acknowledged = len(aa.AcknowledgedBy) > 0
+		if !aa.Acknowledged {
+			result.Unacknowledged = append(result.Unacknowledged, aa.ADRID)
+		}
+	}
+	return nil
+}
 
-	var files []string
-	for _, line := range strings.Split(string(output), "\n") {
-		line = strings.TrimSpace(line)
-		if line != "" {
-			files = append(files, filepath.ToSlash(line))
+// shortHashes abbreviates commit hashes for display.
+func shortHashes(hashes []string) []string {
+	short := make([]string, 0, len(hashes))
+	for _, h := range hashes {
+		if len(h) > 7 {
+			h = h[:7]
 		}
+		short = append(short, h)
 	}
+	return short
+}
 
-	return files, nil
+// changesRoot returns the directory changed paths are relative to: root
+// when set, else the repository root for changes from git, or the working
+// directory for files given on the command line.
+func changesRoot(root string, files []string) (string, error) {
+	if root != "" {
+		return root, nil
+	}
+	if files != nil {
+		return ".", nil
+	}
+	return git.Root()
+}
+
+// listChanges returns the given files as changes of unknown type, or asks
+// git for the changes in r when files is nil.
+func listChanges(files []string, r git.Range) ([]git.Change, error) {
+	if files == nil {
+		return git.Changes(r)
+	}
+	changes := make([]git.Change, 0, len(files))
+	for _, f := range files {
+		changes = append(changes, git.Change{Path: f})
+	}
+	return changes, nil
+}
+
+// matchChange matches the path of c against scope patterns and, for
+// renames out of scope, its old path. It returns the match along with the
+// path it is for; an unmatched result reports the exclusion, if any, that
+// took the file out of scope.
+func matchChange(patterns []string, c git.Change) (glob.ScopeMatch, string) {
+	m := glob.MatchScope(patterns, c.Path)
+	if m.Matched || c.OldPath == "" {
+		return m, c.Path
+	}
+	if old := glob.MatchScope(patterns, c.OldPath); old.Matched || (old.ExcludedBy != "" && m.ExcludedBy == "") {
+		return old, c.OldPath
+	}
+	return m, c.Path
+}
+
+// changeNote describes a deleted or renamed file after its path, and is
+// empty for other changes.
+func changeNote(change, oldFile string) string {
+	switch git.ChangeType(change) {
+	case git.Deleted:
+		return " (deleted)"
+	case git.Renamed:
+		return fmt.Sprintf(" (renamed from %s)", oldFile)
+	}
+	return ""
+}
+
+func newFileChange(c git.Change) FileChange {
+	return FileChange{File: c.Path, OldFile: c.OldPath, Change: string(c.Type)}
+}
+
+// changedPaths returns the current path of each change.
+func changedPaths(changes []git.Change) []string {
+	paths := []string{}
+	for _, c := range changes {
+		paths = append(paths, c.Path)
+	}
+	return paths
+}
+
+// enforcedPaths returns the paths constraints are evaluated against: the
+// current path of each change and the old path of renamed files.
+func enforcedPaths(changes []git.Change) []string {
+	var paths []string
+	for _, c := range changes {
+		paths = append(paths, c.Path)
+		if c.OldPath != "" {
+			paths = append(paths, c.OldPath)
+		}
+	}
+	return paths
+}
+
+// relativePath returns path relative to root in slash form, or path itself
+// if it is outside root.
+func relativePath(root, path string) string {
+	absRoot, err1 := filepath.Abs(root)
+	absPath, err2 := filepath.Abs(path)
+	if err1 == nil && err2 == nil {
+		if rel, err := filepath.Rel(absRoot, absPath); err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return filepath.ToSlash(rel)
+		}
+	}
+	return filepath.ToSlash(path)
 }
 
 func contains(slice []string, item string) bool {
@@ -0,0 +1,333 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/sventorben/decider/internal/git"
+	"github.com/sventorben/decider/internal/glob"
+)
+
+func TestRunCheckADRReportsReferenceErrors(t *testing.T) {
+	dir := t.TempDir()
+	writeTestADR(t, dir, "0001-first.md", `
+adr_id: ADR-0001
+title: First
+status: adopted
+date: 2026-01-16
+related_adrs:
+  - ADR-0099
+`)
+	writeTestADR(t, dir, "0002-second.md", `
+adr_id: ADR-0002
+title: Second
+status: adopted
+date: 2026-01-16
+supersedes:
+  - ADR-0001
+`)
+
+	out := testOutput()
+	result, err := RunCheckADR(&CheckADRConfig{
+		Dir:    dir,
+		Format: FormatText,
+		Output: out,
+	})
+	if err != nil {
+		t.Fatalf("RunCheckADR() error = %v", err)
+	}
+	if result.Valid {
+		t.Error("RunCheckADR() Valid = true, want false")
+	}
+
+	codes := make(map[string]string)
+	for _, e := range result.Errors {
+		codes[e.Code] = e.File
+		if e.Code == "dangling_reference" && (e.Line != 7 || e.Column != 5) {
+			t.Errorf("dangling_reference at %d:%d, want the list item at 7:5", e.Line, e.Column)
+		}
+	}
+	if text := out.Writer.(*bytes.Buffer).String(); !strings.Contains(text, "0001-first.md:7:5: related_adrs:") {
+		t.Errorf("text output lacks the error position:\n%s", text)
+	}
+	if codes["dangling_reference"] != "0001-first.md" {
+		t.Errorf("dangling_reference not reported on 0001-first.md: %v", result.Errors)
+	}
+	if codes["asymmetric_supersession"] != "0001-first.md" {
+		t.Errorf("asymmetric_supersession not reported on 0001-first.md: %v", result.Errors)
+	}
+
+	for _, fr := range result.Results {
+		if fr.File == "0001-first.md" && fr.Valid {
+			t.Error("file result for 0001-first.md is valid, want invalid")
+		}
+	}
+}
+
+func TestRunCheckADRFiles(t *testing.T) {
+	dir := t.TempDir()
+	writeTestADR(t, dir, "0001-first.md", `
+adr_id: ADR-0001
+title: First
+status: adopted
+date: 2026-01-16
+related_adrs:
+  - ADR-0099
+`)
+	writeTestADR(t, dir, "0002-second.md", `
+adr_id: ADR-0002
+title: Second
+status: adopted
+date: 2026-01-16
+related_adrs:
+  - ADR-0001
+`)
+
+	// References resolve against all ADRs, but only the listed file is reported
+	result, err := RunCheckADR(&CheckADRConfig{Dir: dir, Files: []string{"0002-second.md"}, Format: FormatText, Output: testOutput()})
+	if err != nil {
+		t.Fatalf("RunCheckADR() error = %v", err)
+	}
+	if !result.Valid || result.Count != 1 || len(result.Results) != 1 || result.Results[0].File != "0002-second.md" {
+		t.Errorf("result = %+v, want only 0002-second.md, valid", result)
+	}
+}
+
+func TestRunCheckDiffEnforcesConstraints(t *testing.T) {
+	root := t.TempDir()
+	adrDir := filepath.Join(root, "docs", "adr")
+	if err := os.MkdirAll(filepath.Join(root, "src"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(adrDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	writeTestADR(t, adrDir, "0001-repositories.md", `
+adr_id: ADR-0001
+title: Repositories
+status: adopted
+date: 2026-01-16
+scope:
+  paths: ["src/**"]
+constraints:
+  - "Use the repository pattern"
+  - forbid_import: database/sql
+    message: Use repositories
+`)
+	// Neither a proposed ADR nor one that covers no changed file is enforced
+	writeTestADR(t, adrDir, "0002-no-sql.md", `
+adr_id: ADR-0002
+title: No SQL
+status: proposed
+date: 2026-01-16
+scope:
+  paths: ["src/**"]
+constraints:
+  - forbid_regex: sql
+`)
+	writeTestADR(t, adrDir, "0003-no-packages.md", `
+adr_id: ADR-0003
+title: No Packages
+status: adopted
+date: 2026-01-16
+constraints:
+  - forbid_regex: package
+`)
+	if err := os.WriteFile(filepath.Join(root, "src", "user.go"), []byte("package src\n\nimport \"database/sql\"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := RunCheckDiff(&CheckDiffConfig{
+		Dir:    adrDir,
+		Files:  []string{"src/user.go"},
+		Root:   root,
+		Format: FormatText,
+		Output: testOutput(),
+	})
+	if err != nil {
+		t.Fatalf("RunCheckDiff() error = %v", err)
+	}
+
+	if len(result.ApplicableADRs) != 1 {
+		t.Fatalf("ApplicableADRs = %+v", result.ApplicableADRs)
+	}
+	wantConstraints := []string{"Use the repository pattern", "Use repositories"}
+	if !reflect.DeepEqual(result.ApplicableADRs[0].Constraints, wantConstraints) {
+		t.Errorf("Constraints = %v, want %v", result.ApplicableADRs[0].Constraints, wantConstraints)
+	}
+	if len(result.Violations) != 1 || result.Violations[0].File != "src/user.go" || result.Violations[0].Line != 3 {
+		t.Errorf("Violations = %+v", result.Violations)
+	}
+	if result.Summary.TotalViolations != 1 {
+		t.Errorf("Summary.TotalViolations = %d, want 1", result.Summary.TotalViolations)
+	}
+}
+
+func TestMatchChange(t *testing.T) {
+	patterns := []string{"src/db/**", "legacy/**", "!legacy/gen/**"}
+
+	tests := []struct {
+		name   string
+		change git.Change
+		match  glob.ScopeMatch
+		path   string
+	}{
+		{"modified", git.Change{Path: "src/db/x.go", Type: git.Modified}, glob.ScopeMatch{Matched: true, Pattern: "src/db/**"}, "src/db/x.go"},
+		{"deleted", git.Change{Path: "src/db/x.go", Type: git.Deleted}, glob.ScopeMatch{Matched: true, Pattern: "src/db/**"}, "src/db/x.go"},
+		{"moved out of scope", git.Change{Path: "pkg/x.go", OldPath: "src/db/x.go", Type: git.Renamed}, glob.ScopeMatch{Matched: true, Pattern: "src/db/**"}, "src/db/x.go"},
+		{"moved between scopes", git.Change{Path: "legacy/x.go", OldPath: "src/db/x.go", Type: git.Renamed}, glob.ScopeMatch{Matched: true, Pattern: "legacy/**"}, "legacy/x.go"},
+		{"moved into scope", git.Change{Path: "legacy/x.go", OldPath: "pkg/x.go", Type: git.Renamed}, glob.ScopeMatch{Matched: true, Pattern: "legacy/**"}, "legacy/x.go"},
+		{"moved into exclusion", git.Change{Path: "legacy/gen/x.go", OldPath: "src/db/x.go", Type: git.Renamed}, glob.ScopeMatch{Matched: true, Pattern: "src/db/**"}, "src/db/x.go"},
+		{"excluded", git.Change{Path: "legacy/gen/x.go", Type: git.Added}, glob.ScopeMatch{Pattern: "legacy/**", ExcludedBy: "!legacy/gen/**"}, "legacy/gen/x.go"},
+		{"excluded rename", git.Change{Path: "legacy/gen/x.go", OldPath: "pkg/x.go", Type: git.Renamed}, glob.ScopeMatch{Pattern: "legacy/**", ExcludedBy: "!legacy/gen/**"}, "legacy/gen/x.go"},
+		{"out of scope", git.Change{Path: "pkg/x.go", OldPath: "cmd/x.go", Type: git.Renamed}, glob.ScopeMatch{}, "pkg/x.go"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, path := matchChange(patterns, tt.change)
+			if m != tt.match || path != tt.path {
+				t.Errorf("matchChange() = %+v, %q, want %+v, %q", m, path, tt.match, tt.path)
+			}
+		})
+	}
+
+	// Old paths of renamed files are enforced too
+	got := enforcedPaths([]git.Change{{Path: "a.go"}, {Path: "pkg/x.go", OldPath: "src/db/x.go", Type: git.Renamed}})
+	if want := []string{"a.go", "pkg/x.go", "src/db/x.go"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("enforcedPaths() = %v, want %v", got, want)
+	}
+}
+
+// initTestRepo creates a git repository in a temporary directory, changes
+// to it and returns a function running git there. The test is skipped when
+// git is not installed.
+func initTestRepo(t *testing.T) func(args ...string) {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	t.Chdir(t.TempDir())
+	for _, kv := range [][2]string{
+		{"GIT_AUTHOR_NAME", "test"}, {"GIT_AUTHOR_EMAIL", "test@example.com"},
+		{"GIT_COMMITTER_NAME", "test"}, {"GIT_COMMITTER_EMAIL", "test@example.com"},
+		{"GIT_CONFIG_GLOBAL", os.DevNull}, {"GIT_CONFIG_NOSYSTEM", "1"},
+	} {
+		t.Setenv(kv[0], kv[1])
+	}
+	run := func(args ...string) {
+		t.Helper()
+		if out, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+			t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, out)
+		}
+	}
+	run("init", "-q")
+	return run
+}
+
+func TestRunCheckDiffRequireAck(t *testing.T) {
+	run := initTestRepo(t)
+
+	adrDir := filepath.Join("docs", "adr")
+	for _, dir := range []string{adrDir, "src", "web"} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeTestADR(t, adrDir, "0001-backend.md", "adr_id: ADR-0001\ntitle: Backend\nstatus: adopted\ndate: 2026-01-16\nscope:\n  paths: [\"src/**\"]\n")
+	writeTestADR(t, adrDir, "0002-frontend.md", "adr_id: ADR-0002\ntitle: Frontend\nstatus: adopted\ndate: 2026-01-16\nscope:\n  paths: [\"web/**\"]\n")
+	run("add", ".")
+	run("commit", "-q", "-m", "ADRs")
+
+	for _, f := range []string{"src/a.go", "web/a.ts"} {
+		if err := os.WriteFile(f, []byte("x\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	run("add", ".")
+	run("commit", "-q", "-m", "Add code\n\nADR-Ack: adr-0001")
+
+	result, err := RunCheckDiff(&CheckDiffConfig{Dir: adrDir, Base: "HEAD~1", Head: "HEAD", RequireAck: true, Format: FormatText, Output: testOutput()})
+	if err != nil {
+		t.Fatalf("RunCheckDiff() error = %v", err)
+	}
+	if len(result.ApplicableADRs) != 2 {
+		t.Fatalf("ApplicableADRs = %+v", result.ApplicableADRs)
+	}
+	if aa := result.ApplicableADRs[0]; !aa.Acknowledged || len(aa.AcknowledgedBy) != 1 {
+		t.Errorf("ADR-0001 = %+v, want acknowledged by one commit", aa)
+	}
+	if aa := result.ApplicableADRs[1]; aa.Acknowledged {
+		t.Errorf("ADR-0002 = %+v, want unacknowledged", aa)
+	}
+	if want := []string{"ADR-0002"}; !reflect.DeepEqual(result.Unacknowledged, want) || result.Summary.TotalUnacknowledged != 1 {
+		t.Errorf("Unacknowledged = %v (%d), want %v", result.Unacknowledged, result.Summary.TotalUnacknowledged, want)
+	}
+
+	// Acknowledgments need commits
+	if _, err := RunCheckDiff(&CheckDiffConfig{Dir: adrDir, Staged: true, RequireAck: true, Output: testOutput()}); err == nil {
+		t.Error("RequireAck with staged changes: error = nil")
+	}
+}
+
+func TestRunCheckDiffReadsSelectedRevision(t *testing.T) {
+	run := initTestRepo(t)
+
+	adrDir := filepath.Join("docs", "adr")
+	for _, dir := range []string{adrDir, "src"} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeTestADR(t, adrDir, "0001-no-todos.md", "adr_id: ADR-0001\ntitle: No TODOs\nstatus: adopted\ndate: 2026-01-16\nscope:\n  paths: [\"src/**\"]\nconstraints:\n  - forbid_regex: TODO\n")
+	run("add", ".")
+	run("commit", "-q", "-m", "ADRs")
+
+	write := func(content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join("src", "a.go"), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	violations := func(cfg CheckDiffConfig) []string {
+		t.Helper()
+		cfg.Dir, cfg.Format, cfg.Output = adrDir, FormatText, testOutput()
+		result, err := RunCheckDiff(&cfg)
+		if err != nil {
+			t.Fatalf("RunCheckDiff() error = %v", err)
+		}
+		var got []string
+		for _, v := range result.Violations {
+			got = append(got, v.String())
+		}
+		return got
+	}
+	want := []string{"src/a.go:2: [ADR-0001] line matches forbidden pattern TODO"}
+
+	// Staged content is checked, not the cleaned-up working copy
+	write("package src\n// TODO\n")
+	run("add", "src/a.go")
+	write("package src\n")
+	if got := violations(CheckDiffConfig{Staged: true}); !reflect.DeepEqual(got, want) {
+		t.Errorf("staged: violations = %v, want %v", got, want)
+	}
+	if got := violations(CheckDiffConfig{WorkingTree: true}); got != nil {
+		t.Errorf("working tree: violations = %v, want none", got)
+	}
+
+	// Committed content is checked, even after the file is deleted
+	run("commit", "-q", "-m", "Add a.go")
+	if err := os.Remove(filepath.Join("src", "a.go")); err != nil {
+		t.Fatal(err)
+	}
+	if got := violations(CheckDiffConfig{Base: "HEAD~1", Head: "HEAD"}); !reflect.DeepEqual(got, want) {
+		t.Errorf("commit range: violations = %v, want %v", got, want)
+	}
+	if got := violations(CheckDiffConfig{Base: "HEAD~1"}); got != nil {
+		t.Errorf("working tree against base: violations = %v, want none", got)
+	}
+}
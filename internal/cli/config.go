@@ -0,0 +1,60 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/sventorben/decider/internal/config"
+	"gopkg.in/yaml.v3"
+)
+
+// ConfigShowConfig holds configuration for the config show command.
+type ConfigShowConfig struct {
+	Config *config.Config // Effective configuration (file merged onto defaults)
+	Format OutputFormat
+	Output *Output
+}
+
+// RunConfigShow prints the effective project configuration.
+func RunConfigShow(cfg *ConfigShowConfig) (*config.Config, error) {
+	c := cfg.Config
+
+	switch cfg.Format {
+	case FormatTOON, FormatJSON:
+		_ = cfg.Output.PrintStructured(c)
+	case FormatYAML:
+		// Source is not a configuration key, so it is added here rather
+		// than being read from .decider.yaml
+		shown := struct {
+			config.Config `yaml:",inline"`
+			Source        string `yaml:"source"`
+		}{*c, c.Source}
+		enc := yaml.NewEncoder(cfg.Output.Writer)
+		enc.SetIndent(2)
+		if err := enc.Encode(shown); err != nil {
+			return nil, fmt.Errorf("marshaling config: %w", err)
+		}
+		_ = enc.Close()
+	case FormatText:
+		source := c.Source
+		if source == "" {
+			source = "(defaults, no " + config.Filename + " found)"
+		}
+		cfg.Output.Println("Source:            %s", source)
+		cfg.Output.Println("ADR directory:     %s", c.ADRDir)
+		cfg.Output.Println("Format:            %s", c.Format)
+		cfg.Output.Println("Strict:            %t", c.Strict)
+		cfg.Output.Println("Required sections: %s", strings.Join(c.RequiredSections, ", "))
+		if len(c.AllowedTags) == 0 {
+			cfg.Output.Println("Allowed tags:      (any)")
+		} else {
+			cfg.Output.Println("Allowed tags:      %s", strings.Join(c.AllowedTags, ", "))
+		}
+		cfg.Output.Println("ID prefix:         %s", c.IDPrefix)
+		cfg.Output.Println("Template:          %s", c.Template)
+	default:
+		return nil, fmt.Errorf("format %q is not supported by config show: use text, toon, json or yaml", cfg.Format)
+	}
+
+	return c, nil
+}
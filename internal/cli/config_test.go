@@ -0,0 +1,53 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/sventorben/decider/internal/config"
+)
+
+func TestRunConfigShow(t *testing.T) {
+	conf := config.Default()
+	conf.IDPrefix = "DEC"
+	conf.Source = "/repo/.decider.yaml"
+
+	var buf bytes.Buffer
+	_, err := RunConfigShow(&ConfigShowConfig{
+		Config: conf,
+		Format: FormatJSON,
+		Output: &Output{Format: FormatJSON, Writer: &buf},
+	})
+	if err != nil {
+		t.Fatalf("RunConfigShow() error = %v", err)
+	}
+
+	var got map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("invalid JSON output: %v\n%s", err, buf.String())
+	}
+	if got["id_prefix"] != "DEC" || got["source"] != "/repo/.decider.yaml" || got["adr_dir"] != "docs/adr" {
+		t.Errorf("RunConfigShow() JSON = %v", got)
+	}
+
+	buf.Reset()
+	_, err = RunConfigShow(&ConfigShowConfig{
+		Config: conf,
+		Format: FormatYAML,
+		Output: &Output{Format: FormatYAML, Writer: &buf},
+	})
+	if err != nil {
+		t.Fatalf("RunConfigShow() error = %v", err)
+	}
+	if !strings.Contains(buf.String(), "id_prefix: DEC\n") || !strings.Contains(buf.String(), "source: /repo/.decider.yaml\n") {
+		t.Errorf("RunConfigShow() YAML = %s", buf.String())
+	}
+
+	for _, format := range []OutputFormat{"sarif", "github", "markdown", "mermaid", "dot"} {
+		if _, err := RunConfigShow(&ConfigShowConfig{Config: conf, Format: format, Output: testOutput()}); err == nil {
+			t.Errorf("format %s: expected an error", format)
+		}
+	}
+}
@@ -0,0 +1,243 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/sventorben/decider/internal/adr"
+	"github.com/sventorben/decider/internal/git"
+	"github.com/sventorben/decider/internal/toon"
+)
+
+// ContextConfig holds configuration for the context command.
+type ContextConfig struct {
+	Paths  []string // Files or directories; directories are expanded recursively
+	Dir    string
+	Root   string // Repository root; the git top-level (or working directory) when empty
+	Budget int    // Maximum estimated tokens; 0 means no limit
+	Format OutputFormat
+	Output *Output
+}
+
+// ContextResult is the context pack: the adopted ADRs that apply to the
+// paths, cut down to fit the token budget.
+type ContextResult struct {
+	Budget  int          `json:"budget,omitempty"`
+	Tokens  int          `json:"tokens"` // Estimated tokens of the pack itself
+	ADRs    []ContextADR `json:"adrs"`
+	Omitted []string     `json:"omitted,omitempty"` // ADRs left out entirely to fit the budget
+}
+
+// ContextADR holds the parts of an ADR an agent needs before changing the
+// files it governs.
+type ContextADR struct {
+	ADRID         string   `json:"adr_id"`
+	Title         string   `json:"title"`
+	File          string   `json:"file"`
+	Constraints   []string `json:"constraints,omitempty"`
+	Invariants    []string `json:"invariants,omitempty"`
+	Decision      string   `json:"decision,omitempty"`
+	AgentGuidance string   `json:"agent_guidance,omitempty"`
+
+	// Truncated names the sections left out to fit the budget
+	Truncated []string `json:"truncated,omitempty"`
+}
+
+// Sections of a context pack, in the order they are dropped to fit the
+// budget.
+const (
+	sectionDecision      = "decision"
+	sectionAgentGuidance = "agent_guidance"
+	sectionInvariants    = "invariants"
+	sectionConstraints   = "constraints"
+)
+
+var contextDropOrder = []string{sectionDecision, sectionAgentGuidance, sectionInvariants, sectionConstraints}
+
+// RunContext packs the constraints, invariants, Decision and Agent Guidance
+// sections of the adopted ADRs that apply to the paths. If the pack exceeds
+// the budget, sections are dropped by priority, lowest first, starting with
+// the last ADR; ADRs are dropped whole only once nothing else is left.
+func RunContext(cfg *ContextConfig) (*ContextResult, error) {
+	if cfg.Budget < 0 {
+		return nil, fmt.Errorf("budget must not be negative")
+	}
+
+	adrs, err := adr.LoadAllADRs(cfg.Dir)
+	if err != nil {
+		return nil, fmt.Errorf("loading ADRs: %w", err)
+	}
+
+	root, paths, err := resolvePaths(cfg.Root, cfg.Paths)
+	if err != nil {
+		return nil, err
+	}
+	changes := make([]git.Change, len(paths))
+	for i, p := range paths {
+		changes[i] = git.Change{Path: p}
+	}
+
+	ap, err := resolveApplicability(adrs, changes, DefaultStatuses)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ContextResult{Budget: cfg.Budget, ADRs: []ContextADR{}}
+	for _, aa := range ap.applied {
+		a := aa.adr
+		result.ADRs = append(result.ADRs, ContextADR{
+			ADRID:         a.Frontmatter.ADRID,
+			Title:         a.Frontmatter.Title,
+			File:          relativePath(root, a.FilePath),
+			Constraints:   adr.ConstraintStrings(a.Frontmatter.Constraints),
+			Invariants:    a.Frontmatter.Invariants,
+			Decision:      extractSection(a.Body, "Decision"),
+			AgentGuidance: extractSection(a.Body, "Agent Guidance"),
+		})
+	}
+
+	if err := packContext(result, cfg.Format); err != nil {
+		return nil, err
+	}
+
+	if cfg.Format == FormatJSON {
+		_ = cfg.Output.PrintJSON(result)
+	} else {
+		_ = cfg.Output.PrintTOON(result)
+	}
+	if cfg.Budget > 0 && result.Tokens > cfg.Budget {
+		cfg.Output.Warn("Context needs about %d tokens, over the budget of %d", result.Tokens, cfg.Budget)
+	}
+	return result, nil
+}
+
+// packContext drops content from result until its estimated size fits the
+// budget, recording what it dropped, and sets result.Tokens.
+func packContext(result *ContextResult, format OutputFormat) error {
+	fits := func() (bool, error) {
+		if err := measureContext(result, format); err != nil {
+			return false, err
+		}
+		return result.Budget == 0 || result.Tokens <= result.Budget, nil
+	}
+
+	type drop struct {
+		adr     int
+		section string
+	}
+	full := append([]ContextADR(nil), result.ADRs...)
+	var drops []drop
+
+	for _, section := range contextDropOrder {
+		for i := len(result.ADRs) - 1; i >= 0; i-- {
+			if ok, err := fits(); err != nil || ok {
+				return err
+			}
+			if c := &result.ADRs[i]; c.drop(section) {
+				c.Truncated = append(c.Truncated, section)
+				drops = append(drops, drop{i, section})
+			}
+		}
+	}
+
+	omitted := false
+	for {
+		ok, err := fits()
+		if err != nil {
+			return err
+		}
+		if ok || len(result.ADRs) == 0 {
+			break
+		}
+		last := result.ADRs[len(result.ADRs)-1]
+		result.ADRs = result.ADRs[:len(result.ADRs)-1]
+		result.Omitted = append([]string{last.ADRID}, result.Omitted...)
+		omitted = true
+	}
+	if !omitted {
+		return nil
+	}
+
+	// Omitting whole ADRs may have freed more than needed: put sections back
+	// where they fit, highest priority first
+	for i := len(drops) - 1; i >= 0; i-- {
+		d := drops[i]
+		if d.adr >= len(result.ADRs) {
+			continue
+		}
+		c := &result.ADRs[d.adr]
+		saved := *c
+		c.restore(full[d.adr], d.section)
+		ok, err := fits()
+		if err != nil {
+			return err
+		}
+		if !ok {
+			*c = saved
+		}
+	}
+	_, err := fits()
+	return err
+}
+
+// measureContext sets result.Tokens to the estimated tokens of result as
+// printed in format, which includes the count itself.
+func measureContext(result *ContextResult, format OutputFormat) error {
+	result.Tokens = 0
+	for i := 0; i < 3; i++ {
+		var buf bytes.Buffer
+		out := &Output{Format: format, Writer: &buf}
+		var err error
+		if format == FormatJSON {
+			err = out.PrintJSON(result)
+		} else {
+			err = out.PrintTOON(result)
+		}
+		if err != nil {
+			return err
+		}
+		tokens := toon.EstimateTokens(buf.Bytes())
+		if tokens == result.Tokens {
+			break
+		}
+		result.Tokens = tokens
+	}
+	return nil
+}
+
+// drop removes a section from the ADR and reports whether it had content.
+func (c *ContextADR) drop(section string) bool {
+	var dropped bool
+	switch section {
+	case sectionDecision:
+		dropped, c.Decision = c.Decision != "", ""
+	case sectionAgentGuidance:
+		dropped, c.AgentGuidance = c.AgentGuidance != "", ""
+	case sectionInvariants:
+		dropped, c.Invariants = len(c.Invariants) > 0, nil
+	case sectionConstraints:
+		dropped, c.Constraints = len(c.Constraints) > 0, nil
+	}
+	return dropped
+}
+
+// restore puts a dropped section back from the full ADR.
+func (c *ContextADR) restore(full ContextADR, section string) {
+	switch section {
+	case sectionDecision:
+		c.Decision = full.Decision
+	case sectionAgentGuidance:
+		c.AgentGuidance = full.AgentGuidance
+	case sectionInvariants:
+		c.Invariants = full.Invariants
+	case sectionConstraints:
+		c.Constraints = full.Constraints
+	}
+	var truncated []string
+	for _, t := range c.Truncated {
+		if t != section {
+			truncated = append(truncated, t)
+		}
+	}
+	c.Truncated = truncated
+}
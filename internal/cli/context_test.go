@@ -0,0 +1,143 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRunContext(t *testing.T) {
+	root := t.TempDir()
+	t.Chdir(root)
+	dir := filepath.Join(root, "docs", "adr")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	writeTestADR(t, dir, "0001-database-access.md", `
+adr_id: ADR-0001
+title: Database Access
+status: adopted
+date: 2026-01-16
+scope:
+  paths: ["src/db/**"]
+constraints:
+  - "Use the repository pattern"
+invariants:
+  - "Queries are parameterized"
+`)
+	writeTestADR(t, dir, "0002-layering.md", `
+adr_id: ADR-0002
+title: Layering
+status: adopted
+date: 2026-01-16
+scope:
+  paths: ["src/**"]
+constraints:
+  - "Handlers never import the database package"
+`)
+	writeTestADR(t, dir, "0003-orm.md", `
+adr_id: ADR-0003
+title: ORM
+status: proposed
+date: 2026-01-16
+scope:
+  paths: ["src/db/**"]
+constraints:
+  - "Use an ORM"
+`)
+	decision := "We keep all SQL behind repositories, one per aggregate, so that queries can be reviewed in one place."
+	for _, name := range []string{"0001-database-access.md", "0002-layering.md"} {
+		path := filepath.Join(dir, name)
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		content := strings.Replace(string(data), "\nDecision.\n", "\n"+decision+"\n", 1) + "\n## Agent Guidance\n\nNever build SQL from strings.\n"
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	run := func(budget int) *ContextResult {
+		t.Helper()
+		result, err := RunContext(&ContextConfig{
+			Paths:  []string{"src/db/query.go"},
+			Dir:    dir,
+			Root:   root,
+			Budget: budget,
+			Format: FormatTOON,
+			Output: testOutput(),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return result
+	}
+
+	// Without a budget, the applicable adopted ADRs are packed whole
+	full := run(0)
+	if len(full.ADRs) != 2 || full.Omitted != nil || full.Tokens == 0 {
+		t.Fatalf("RunContext() = %+v", full)
+	}
+	first := full.ADRs[0]
+	want := ContextADR{
+		ADRID:         "ADR-0001",
+		Title:         "Database Access",
+		File:          "docs/adr/0001-database-access.md",
+		Constraints:   []string{"Use the repository pattern"},
+		Invariants:    []string{"Queries are parameterized"},
+		Decision:      decision,
+		AgentGuidance: "Never build SQL from strings.",
+	}
+	if !reflect.DeepEqual(first, want) {
+		t.Errorf("ADRs[0] = %+v\nwant %+v", first, want)
+	}
+
+	// The Decision section of the last ADR goes first
+	result := run(full.Tokens - 1)
+	if result.Tokens > full.Tokens-1 || !reflect.DeepEqual(result.ADRs[0], want) {
+		t.Errorf("ADRs[0] = %+v, Tokens = %d", result.ADRs[0], result.Tokens)
+	}
+	if last := result.ADRs[1]; last.Decision != "" || last.AgentGuidance == "" || !reflect.DeepEqual(last.Truncated, []string{sectionDecision}) {
+		t.Errorf("ADRs[1] = %+v, want only the Decision truncated", last)
+	}
+
+	// With too small a budget, everything is omitted
+	result = run(1)
+	if len(result.ADRs) != 0 || !reflect.DeepEqual(result.Omitted, []string{"ADR-0001", "ADR-0002"}) {
+		t.Errorf("budget 1: ADRs = %+v, Omitted = %v", result.ADRs, result.Omitted)
+	}
+
+	if _, err := RunContext(&ContextConfig{Paths: []string{"src"}, Dir: dir, Root: root, Budget: -1, Format: FormatTOON, Output: testOutput()}); err == nil {
+		t.Error("negative budget: expected an error")
+	}
+}
+
+func TestPackContextRestoresSections(t *testing.T) {
+	adrs := []ContextADR{
+		{ADRID: "ADR-0001", Title: "A", File: "a.md", Constraints: []string{"Keep it short"}},
+		{ADRID: "ADR-0002", Title: "B", File: "b.md", Decision: "A long decision that takes many more tokens than a short constraint does, and then some more words"},
+	}
+	// The budget is exactly what the expected pack needs
+	want := &ContextResult{ADRs: adrs[:1], Omitted: []string{"ADR-0002"}}
+	for {
+		if err := measureContext(want, FormatTOON); err != nil {
+			t.Fatal(err)
+		}
+		if want.Budget == want.Tokens {
+			break
+		}
+		want.Budget = want.Tokens
+	}
+
+	// Omitting ADR-0002 makes room for the constraint dropped before it
+	result := &ContextResult{Budget: want.Tokens, ADRs: append([]ContextADR(nil), adrs...)}
+	if err := packContext(result, FormatTOON); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(result, want) {
+		t.Errorf("packContext() = %+v\nwant %+v", result, want)
+	}
+}
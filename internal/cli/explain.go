@@ -5,29 +5,57 @@ import (
 	"strings"
 
 	"github.com/sventorben/decider/internal/adr"
+	"github.com/sventorben/decider/internal/git"
 	"github.com/sventorben/decider/internal/glob"
 )
 
 // ExplainConfig holds configuration for the explain command.
 type ExplainConfig struct {
 	Dir    string
-	Base   string
 	Format OutputFormat
 	Output *Output
+
+	// Base, Head, Staged and WorkingTree select the changes git reports;
+	// see git.Range.
+	Base        string
+	Head        string
+	Staged      bool
+	WorkingTree bool
+
+	// Files, when non-nil, are used as the changed files instead of asking git.
+	Files []string
+
+	// LinkBase is prepended to ADR file paths in Markdown links.
+	LinkBase string
+
+	// Statuses are the statuses of the ADRs that apply; see
+	// CheckDiffConfig.Statuses.
+	Statuses []string
 }
 
 // ExplainResult holds the result of the explain command.
 type ExplainResult struct {
 	ChangedFiles []string       `json:"changed_files"`
 	Explanations []ExplainEntry `json:"explanations"`
+
+	// Excluded lists the ADRs that apply to none of the changed files only
+	// because '!' entries in their scope exclude them.
+	Excluded []ExplainEntry `json:"excluded,omitempty"`
+
+	// Historical lists the ADRs whose scope covers changed files but whose
+	// status does not apply.
+	Historical []HistoricalADR `json:"historical,omitempty"`
 }
 
 // ExplainEntry explains why an ADR applies to specific files.
 type ExplainEntry struct {
 	ADRID       string         `json:"adr_id"`
 	Title       string         `json:"title"`
+	File        string         `json:"file"`
 	Status      string         `json:"status"`
 	Matches     []MatchExplain `json:"matches"`
+	Excluded    []MatchExplain `json:"excluded,omitempty"` // Files an exclusion took out of scope
+	Via         []string       `json:"via,omitempty"`      // Superseded ADRs this ADR applies in place of
 	Constraints []string       `json:"constraints,omitempty"`
 	Invariants  []string       `json:"invariants,omitempty"`
 }
@@ -35,17 +63,23 @@ type ExplainEntry struct {
 // MatchExplain explains a single path match.
 type MatchExplain struct {
 	File    string `json:"file"`
+	OldFile string `json:"old_file,omitempty"` // Renamed files only
+	Change  string `json:"change,omitempty"`   // added, modified, deleted or renamed; empty for --files
 	Pattern string `json:"pattern"`
 	Reason  string `json:"reason"`
 }
 
 // RunExplain provides narrative explanation of why ADRs apply.
 func RunExplain(cfg *ExplainConfig) (*ExplainResult, error) {
-	// Get changed files from git
-	changedFiles, err := getGitDiff(cfg.Base)
+	changes, err := listChanges(cfg.Files, git.Range{Base: cfg.Base, Head: cfg.Head, Staged: cfg.Staged, WorkingTree: cfg.WorkingTree})
 	if err != nil {
 		return nil, fmt.Errorf("getting git diff: %w", err)
 	}
+	root, err := changesRoot("", cfg.Files)
+	if err != nil {
+		return nil, err
+	}
+	changedFiles := changedPaths(changes)
 
 	// Load all ADRs
 	adrs, err := adr.LoadAllADRs(cfg.Dir)
@@ -58,42 +92,88 @@ func RunExplain(cfg *ExplainConfig) (*ExplainResult, error) {
 	}
 
 	// Find applicable ADRs with explanations
-	for _, a := range adrs {
-		if len(a.Frontmatter.Scope.Paths) == 0 {
-			continue
+	ap, err := resolveApplicability(adrs, changes, cfg.Statuses)
+	if err != nil {
+		return nil, err
+	}
+	explain := func(a *adr.ADR) (matches, excluded []MatchExplain) {
+		for _, cm := range ap.scoped[a] {
+			c := changes[cm.change]
+			// Only the pattern that decides is explained
+			reason := explainMatch(cm.match)
+			if cm.path != c.Path {
+				reason = fmt.Sprintf("Renamed from '%s', which %s", cm.path, strings.TrimPrefix(reason, "File "))
+			}
+			explained := MatchExplain{
+				File:    c.Path,
+				OldFile: c.OldPath,
+				Change:  string(c.Type),
+				Pattern: cm.match.Pattern,
+				Reason:  reason,
+			}
+			if cm.match.Matched {
+				matches = append(matches, explained)
+			} else {
+				excluded = append(excluded, explained)
+			}
 		}
+		return matches, excluded
+	}
+	newEntry := func(a *adr.ADR, matches, excluded []MatchExplain) ExplainEntry {
+		return ExplainEntry{
+			ADRID:       a.Frontmatter.ADRID,
+			Title:       a.Frontmatter.Title,
+			File:        relativePath(root, a.FilePath),
+			Status:      string(a.Frontmatter.Status),
+			Matches:     matches,
+			Excluded:    excluded,
+			Constraints: adr.ConstraintStrings(a.Frontmatter.Constraints),
+			Invariants:  a.Frontmatter.Invariants,
+		}
+	}
 
-		var matches []MatchExplain
-
-		for _, changedFile := range changedFiles {
-			for _, scopePath := range a.Frontmatter.Scope.Paths {
-				if glob.Match(scopePath, changedFile) {
-					matches = append(matches, MatchExplain{
-						File:    changedFile,
-						Pattern: scopePath,
-						Reason:  explainMatch(scopePath, changedFile),
-					})
-					break // Only match first pattern per file
+	applied := make(map[*adr.ADR]bool)
+	for _, aa := range ap.applied {
+		applied[aa.adr] = true
+		matches, excluded := explain(aa.adr)
+		for _, old := range aa.via {
+			inherited, _ := explain(old)
+			for _, m := range inherited {
+				if explainsFile(matches, m.File) {
+					continue
 				}
+				m.Reason = fmt.Sprintf("Supersedes %s, which covers it: %s", old.Frontmatter.ADRID, m.Reason)
+				matches = append(matches, m)
 			}
 		}
+		entry := newEntry(aa.adr, matches, excluded)
+		entry.Via = adrIDs(aa.via)
+		result.Explanations = append(result.Explanations, entry)
+	}
+	result.Historical = ap.historicalADRs(root, changes)
 
-		if len(matches) > 0 {
-			result.Explanations = append(result.Explanations, ExplainEntry{
-				ADRID:       a.Frontmatter.ADRID,
-				Title:       a.Frontmatter.Title,
-				Status:      string(a.Frontmatter.Status),
-				Matches:     matches,
-				Constraints: a.Frontmatter.Constraints,
-				Invariants:  a.Frontmatter.Invariants,
-			})
+	// ADRs that would apply but for an exclusion
+	statuses := cfg.Statuses
+	if len(statuses) == 0 {
+		statuses = DefaultStatuses
+	}
+	for _, a := range adrs {
+		if applied[a] || ap.covers(a) || len(ap.scoped[a]) == 0 || !contains(statuses, string(a.Frontmatter.Status)) {
+			continue
 		}
+		_, excluded := explain(a)
+		result.Excluded = append(result.Excluded, newEntry(a, nil, excluded))
 	}
 
 	// Output
-	if cfg.Format == FormatTOON || cfg.Format == FormatJSON {
+	switch cfg.Format {
+	case FormatGitHub:
+		_, _ = fmt.Fprint(cfg.Output.Writer, explainGitHub(result))
+	case FormatMarkdown:
+		_, _ = fmt.Fprint(cfg.Output.Writer, explainMarkdown(result, cfg.LinkBase))
+	case FormatTOON, FormatJSON:
 		_ = cfg.Output.PrintStructured(result)
-	} else {
+	default:
 		cfg.Output.Println("# ADR Applicability Analysis")
 		cfg.Output.Println("")
 		cfg.Output.Println("Analyzing %d changed file(s) against %d ADR(s)...", len(changedFiles), len(adrs))
@@ -112,15 +192,19 @@ func RunExplain(cfg *ExplainConfig) (*ExplainResult, error) {
 			for _, exp := range result.Explanations {
 				cfg.Output.Println("## %s: %s", exp.ADRID, exp.Title)
 				cfg.Output.Println("Status: %s", exp.Status)
+				if len(exp.Via) > 0 {
+					cfg.Output.Println("Supersedes: %s", strings.Join(exp.Via, ", "))
+				}
 				cfg.Output.Println("")
 				cfg.Output.Println("### Why This ADR Applies")
 				cfg.Output.Println("")
 
 				for _, m := range exp.Matches {
-					cfg.Output.Println("- **%s**", m.File)
+					cfg.Output.Println("- **%s**%s", m.File, changeNote(m.Change, m.OldFile))
 					cfg.Output.Println("  Pattern: `%s`", m.Pattern)
 					cfg.Output.Println("  %s", m.Reason)
 				}
+				printExcluded(cfg.Output, exp.Excluded)
 
 				if len(exp.Constraints) > 0 {
 					cfg.Output.Println("")
@@ -141,13 +225,67 @@ func RunExplain(cfg *ExplainConfig) (*ExplainResult, error) {
 				cfg.Output.Println("")
 			}
 		}
+
+		if len(result.Excluded) > 0 {
+			cfg.Output.Println("")
+			cfg.Output.Println("%d ADR(s) do not apply because their scope excludes the changed files:", len(result.Excluded))
+			cfg.Output.Println("")
+			for _, exp := range result.Excluded {
+				cfg.Output.Println("## %s: %s", exp.ADRID, exp.Title)
+				printExcluded(cfg.Output, exp.Excluded)
+				cfg.Output.Println("")
+			}
+		}
+
+		if len(result.Historical) > 0 {
+			cfg.Output.Println("")
+			cfg.Output.Println("# Historical Context")
+			cfg.Output.Println("")
+			cfg.Output.Println("These ADRs also cover the changed files but do not apply, so their")
+			cfg.Output.Println("constraints need not be followed:")
+			cfg.Output.Println("")
+			for _, h := range result.Historical {
+				cfg.Output.Println("- %s: %s (%s%s)", h.ADRID, h.Title, h.Status, supersessionNote(h.SupersededBy))
+				cfg.Output.Println("  Covers: %s", strings.Join(h.MatchedFiles, ", "))
+			}
+		}
 	}
 
 	return result, nil
 }
 
-// explainMatch generates a human-readable explanation of why a pattern matches a file.
-func explainMatch(pattern, file string) string {
+// explainsFile reports whether matches explain file.
+func explainsFile(matches []MatchExplain, file string) bool {
+	for _, m := range matches {
+		if m.File == file {
+			return true
+		}
+	}
+	return false
+}
+
+// printExcluded lists the files that exclusions took out of an ADR's scope.
+func printExcluded(out *Output, excluded []MatchExplain) {
+	if len(excluded) == 0 {
+		return
+	}
+	out.Println("")
+	out.Println("### Excluded From Scope")
+	out.Println("")
+	for _, m := range excluded {
+		out.Println("- **%s**%s", m.File, changeNote(m.Change, m.OldFile))
+		out.Println("  %s", m.Reason)
+	}
+}
+
+// explainMatch generates a human-readable explanation of why a file is in
+// scope, or of which exclusion took it out of scope.
+func explainMatch(m glob.ScopeMatch) string {
+	if m.ExcludedBy != "" {
+		return fmt.Sprintf("File matches '%s' but is excluded by '%s'", m.Pattern, m.ExcludedBy)
+	}
+
+	pattern := m.Pattern
 	if strings.Contains(pattern, "**") {
 		parts := strings.Split(pattern, "**")
 		if len(parts) == 2 {
@@ -165,7 +303,7 @@ func explainMatch(pattern, file string) string {
 		return fmt.Sprintf("File matches recursive pattern '%s'", pattern)
 	}
 
-	if strings.Contains(pattern, "*") {
+	if strings.ContainsAny(pattern, "*?[{") {
 		return fmt.Sprintf("File matches wildcard pattern '%s'", pattern)
 	}
 
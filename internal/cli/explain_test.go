@@ -0,0 +1,120 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sventorben/decider/internal/glob"
+)
+
+func TestRunExplainExclusions(t *testing.T) {
+	dir := t.TempDir()
+	writeTestADR(t, dir, "0001-api-layer.md", `
+adr_id: ADR-0001
+title: API Layer
+status: adopted
+date: 2026-01-16
+scope:
+  paths:
+    - "src/{api,web}/**"
+    - "!**/*_test.go"
+`)
+	writeTestADR(t, dir, "0002-generated-code.md", `
+adr_id: ADR-0002
+title: Generated Code
+status: adopted
+date: 2026-01-16
+scope:
+  paths:
+    - "gen/**"
+    - "!gen/vendor/**"
+`)
+
+	out := testOutput()
+	result, err := RunExplain(&ExplainConfig{
+		Dir:    dir,
+		Format: FormatText,
+		Output: out,
+		Files:  []string{"src/api/handler.go", "src/web/page_test.go", "gen/vendor/x.go"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(result.Explanations) != 1 || result.Explanations[0].ADRID != "ADR-0001" {
+		t.Fatalf("Explanations = %+v, want ADR-0001 only", result.Explanations)
+	}
+	exp := result.Explanations[0]
+	if len(exp.Matches) != 1 || exp.Matches[0].File != "src/api/handler.go" || exp.Matches[0].Pattern != "src/{api,web}/**" {
+		t.Errorf("Matches = %+v", exp.Matches)
+	}
+	if len(exp.Excluded) != 1 || exp.Excluded[0].Reason != "File matches 'src/{api,web}/**' but is excluded by '!**/*_test.go'" {
+		t.Errorf("Excluded = %+v", exp.Excluded)
+	}
+
+	if len(result.Excluded) != 1 || result.Excluded[0].ADRID != "ADR-0002" {
+		t.Fatalf("result.Excluded = %+v, want ADR-0002", result.Excluded)
+	}
+
+	text := out.Writer.(*bytes.Buffer).String()
+	for _, want := range []string{"### Excluded From Scope", "1 ADR(s) do not apply because their scope excludes the changed files", "excluded by '!gen/vendor/**'"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("output lacks %q:\n%s", want, text)
+		}
+	}
+}
+
+func TestExplainMatch(t *testing.T) {
+	tests := []struct {
+		match glob.ScopeMatch
+		want  string
+	}{
+		{glob.ScopeMatch{Matched: true, Pattern: "src/**"}, "File is anywhere under 'src/'"},
+		{glob.ScopeMatch{Matched: true, Pattern: "**/*.go"}, "File matches pattern '*.go' at any depth"},
+		{glob.ScopeMatch{Matched: true, Pattern: "go.{mod,sum}"}, "File matches wildcard pattern 'go.{mod,sum}'"},
+		{glob.ScopeMatch{Matched: true, Pattern: "go.mod"}, "File exactly matches 'go.mod'"},
+		{glob.ScopeMatch{Pattern: "src/**", ExcludedBy: "!src/gen/**"}, "File matches 'src/**' but is excluded by '!src/gen/**'"},
+	}
+	for _, tt := range tests {
+		if got := explainMatch(tt.match); got != tt.want {
+			t.Errorf("explainMatch(%+v) = %q, want %q", tt.match, got, tt.want)
+		}
+	}
+}
+
+func TestRunExplainPathsFromRepositoryRoot(t *testing.T) {
+	run := initTestRepo(t)
+
+	adrDir := filepath.Join("docs", "adr")
+	for _, dir := range []string{adrDir, "src"} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeTestADR(t, adrDir, "0001-backend.md", "adr_id: ADR-0001\ntitle: Backend\nstatus: adopted\ndate: 2026-01-16\nscope:\n  paths: [\"src/**\"]\n")
+	writeTestADR(t, adrDir, "0002-legacy.md", "adr_id: ADR-0002\ntitle: Legacy\nstatus: rejected\ndate: 2026-01-16\nscope:\n  paths: [\"src/**\"]\n")
+	if err := os.WriteFile(filepath.Join("src", "a.go"), []byte("package src\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	run("add", ".")
+	run("commit", "-q", "-m", "Initial")
+	if err := os.WriteFile(filepath.Join("src", "a.go"), []byte("package src // changed\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Run from a subdirectory, as git paths are relative to the root
+	t.Chdir("src")
+	result, err := RunExplain(&ExplainConfig{Dir: filepath.Join("..", adrDir), WorkingTree: true, Format: FormatJSON, Output: testOutput()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Explanations) != 1 || result.Explanations[0].File != "docs/adr/0001-backend.md" {
+		t.Errorf("Explanations = %+v, want docs/adr/0001-backend.md", result.Explanations)
+	}
+	if len(result.Historical) != 1 || result.Historical[0].File != "docs/adr/0002-legacy.md" {
+		t.Errorf("Historical = %+v, want docs/adr/0002-legacy.md", result.Historical)
+	}
+}
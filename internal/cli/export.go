@@ -0,0 +1,199 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/sventorben/decider/internal/adr"
+)
+
+// Markers delimiting the block that export agents manages in an agent
+// instruction file. Everything outside them is left untouched.
+const (
+	AgentsBlockStart = "<!-- decider:agents:start -->"
+	AgentsBlockEnd   = "<!-- decider:agents:end -->"
+)
+
+// ExportAgentsConfig holds configuration for the export agents command.
+type ExportAgentsConfig struct {
+	Dir    string
+	Out    string // Instruction file, e.g. AGENTS.md or CLAUDE.md
+	Check  bool   // Verify the block is up-to-date instead of writing it
+	Format OutputFormat
+	Output *Output
+}
+
+// ExportAgentsResult holds the result of the export agents command.
+type ExportAgentsResult struct {
+	File     string `json:"file"`
+	ADRCount int    `json:"adr_count"`
+	Updated  bool   `json:"updated"`    // The file was written
+	UpToDate bool   `json:"up_to_date"` // The file already held the current block
+}
+
+// RunExportAgents renders the adopted ADRs into the managed block of an agent
+// instruction file, creating the file or appending the block if needed. With
+// Check set, it only reports whether the block is up-to-date.
+func RunExportAgents(cfg *ExportAgentsConfig) (*ExportAgentsResult, error) {
+	adrs, err := adr.LoadAllADRs(cfg.Dir)
+	if err != nil {
+		return nil, fmt.Errorf("loading ADRs: %w", err)
+	}
+	var adopted []*adr.ADR
+	for _, a := range adrs {
+		if a.Frontmatter.Status == adr.StatusAdopted {
+			adopted = append(adopted, a)
+		}
+	}
+
+	current, err := os.ReadFile(cfg.Out)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("reading %s: %w", cfg.Out, err)
+	}
+	block := renderAgentsBlock(adopted, cfg.Dir, filepath.Dir(cfg.Out))
+	content, err := replaceAgentsBlock(string(current), block)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", cfg.Out, err)
+	}
+
+	result := &ExportAgentsResult{
+		File:     cfg.Out,
+		ADRCount: len(adopted),
+		UpToDate: content == string(current),
+	}
+	if !cfg.Check && !result.UpToDate {
+		if err := os.WriteFile(cfg.Out, []byte(content), 0644); err != nil {
+			return nil, fmt.Errorf("writing %s: %w", cfg.Out, err)
+		}
+		result.Updated = true
+	}
+
+	// Output
+	if cfg.Format == FormatTOON || cfg.Format == FormatJSON {
+		_ = cfg.Output.PrintStructured(result)
+		return result, nil
+	}
+
+	switch {
+	case cfg.Check && result.UpToDate:
+		cfg.Output.Success("Agent instructions are up-to-date: %s", cfg.Out)
+	case cfg.Check:
+		cfg.Output.Error("Agent instructions are out of date: %s. Run 'decider export agents --out %s' to update.", cfg.Out, cfg.Out)
+	case result.Updated:
+		cfg.Output.Success("Exported %d adopted ADR(s) to %s", result.ADRCount, cfg.Out)
+	default:
+		cfg.Output.Success("%s is already up-to-date", cfg.Out)
+	}
+
+	return result, nil
+}
+
+// renderAgentsBlock renders the managed block, markers included, listing the
+// ADRs grouped by scope. Groups are in the order of their first ADR. Links
+// are relative to outDir, the directory of the instruction file.
+func renderAgentsBlock(adrs []*adr.ADR, dir, outDir string) string {
+	var b strings.Builder
+	b.WriteString(AgentsBlockStart + "\n")
+	fmt.Fprintf(&b, "<!-- Generated by 'decider export agents' from the adopted ADRs in %s. Do not edit this block by hand. -->\n", agentsLink(outDir, dir))
+	b.WriteString("\n## Architecture Decisions\n\n")
+	if len(adrs) == 0 {
+		b.WriteString("No adopted ADRs.\n\n")
+		b.WriteString(AgentsBlockEnd + "\n")
+		return b.String()
+	}
+	b.WriteString("Follow the constraints and invariants of these adopted ADRs when changing files in their scope.\n")
+
+	var scopes []string
+	groups := make(map[string][]*adr.ADR)
+	for _, a := range adrs {
+		scope := agentsScope(a.Frontmatter.Scope.Paths)
+		if _, ok := groups[scope]; !ok {
+			scopes = append(scopes, scope)
+		}
+		groups[scope] = append(groups[scope], a)
+	}
+
+	for _, scope := range scopes {
+		fmt.Fprintf(&b, "\n### %s\n", scope)
+		for _, a := range groups[scope] {
+			fmt.Fprintf(&b, "\n#### [%s: %s](%s)\n", a.Frontmatter.ADRID, a.Frontmatter.Title, agentsLink(outDir, a.FilePath))
+			if constraints := adr.ConstraintStrings(a.Frontmatter.Constraints); len(constraints) > 0 {
+				b.WriteString("\nConstraints:\n")
+				for _, c := range constraints {
+					b.WriteString("- " + c + "\n")
+				}
+			}
+			if len(a.Frontmatter.Invariants) > 0 {
+				b.WriteString("\nInvariants:\n")
+				for _, i := range a.Frontmatter.Invariants {
+					b.WriteString("- " + i + "\n")
+				}
+			}
+			if guidance := extractSection(a.Body, "Agent Guidance"); guidance != "" {
+				b.WriteString("\nAgent guidance:\n\n" + guidance + "\n")
+			}
+		}
+	}
+
+	b.WriteString("\n" + AgentsBlockEnd + "\n")
+	return b.String()
+}
+
+// agentsScope names the group of an ADR after its scope patterns.
+func agentsScope(patterns []string) string {
+	if len(patterns) == 0 {
+		return "Unscoped"
+	}
+	quoted := make([]string, len(patterns))
+	for i, p := range patterns {
+		quoted[i] = "`" + p + "`"
+	}
+	return "Scope: " + strings.Join(quoted, ", ")
+}
+
+// agentsLink returns path relative to outDir in slash form, falling back to
+// path as given.
+func agentsLink(outDir, path string) string {
+	absOut, err1 := filepath.Abs(outDir)
+	absPath, err2 := filepath.Abs(path)
+	if err1 != nil || err2 != nil {
+		return filepath.ToSlash(path)
+	}
+	rel, err := filepath.Rel(absOut, absPath)
+	if err != nil {
+		return filepath.ToSlash(path)
+	}
+	return filepath.ToSlash(rel)
+}
+
+// replaceAgentsBlock replaces the managed block in content, from the start
+// marker through the end marker, with block. Without markers, block is
+// appended after a blank line.
+func replaceAgentsBlock(content, block string) (string, error) {
+	start := strings.Index(content, AgentsBlockStart)
+	end := strings.Index(content, AgentsBlockEnd)
+	switch {
+	case start < 0 && end < 0:
+		if content == "" {
+			return block, nil
+		}
+		if !strings.HasSuffix(content, "\n") {
+			content += "\n"
+		}
+		return content + "\n" + block, nil
+	case start < 0 || end < start:
+		return "", fmt.Errorf("%s must be followed by %s", AgentsBlockStart, AgentsBlockEnd)
+	case strings.Count(content, AgentsBlockStart) > 1 || strings.Count(content, AgentsBlockEnd) > 1:
+		return "", fmt.Errorf("found more than one managed block")
+	}
+
+	// The block ends with a newline; the one after the end marker is replaced
+	end += len(AgentsBlockEnd)
+	if strings.HasPrefix(content[end:], "\n") {
+		end++
+	}
+	return content[:start] + block + content[end:], nil
+}
@@ -0,0 +1,151 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRunExportAgents(t *testing.T) {
+	root := t.TempDir()
+	dir := filepath.Join(root, "docs", "adr")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	writeTestADR(t, dir, "0001-database-access.md", `
+adr_id: ADR-0001
+title: Database Access
+status: adopted
+date: 2026-01-16
+scope:
+  paths: ["src/db/**"]
+constraints:
+  - "Use the repository pattern"
+invariants:
+  - "Queries are parameterized"
+`)
+	writeTestADR(t, dir, "0002-layering.md", `
+adr_id: ADR-0002
+title: Layering
+status: adopted
+date: 2026-01-16
+scope:
+  paths: ["src/**"]
+constraints:
+  - "Handlers never import the database package"
+`)
+	writeTestADR(t, dir, "0003-migrations.md", `
+adr_id: ADR-0003
+title: Migrations
+status: adopted
+date: 2026-01-16
+scope:
+  paths: ["src/db/**"]
+`)
+	writeTestADR(t, dir, "0004-orm.md", `
+adr_id: ADR-0004
+title: ORM
+status: proposed
+date: 2026-01-16
+scope:
+  paths: ["src/db/**"]
+constraints:
+  - "Use an ORM"
+`)
+	path := filepath.Join(dir, "0003-migrations.md")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, append(data, "\n## Agent Guidance\n\nNever edit an applied migration.\n"...), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	out := filepath.Join(root, "AGENTS.md")
+	manual := "# Agents\n\nRun make test before pushing."
+	if err := os.WriteFile(out, []byte(manual), 0644); err != nil {
+		t.Fatal(err)
+	}
+	export := func(check bool) *ExportAgentsResult {
+		t.Helper()
+		result, err := RunExportAgents(&ExportAgentsConfig{Dir: dir, Out: out, Check: check, Format: FormatText, Output: testOutput()})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return result
+	}
+
+	if result := export(true); result.UpToDate || result.Updated {
+		t.Errorf("check before export = %+v, want out of date", result)
+	}
+	if result := export(false); !result.Updated || result.ADRCount != 3 {
+		t.Errorf("export = %+v, want 3 ADRs written", result)
+	}
+	if result := export(true); !result.UpToDate {
+		t.Errorf("check after export = %+v, want up-to-date", result)
+	}
+
+	written, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	content := string(written)
+	if !strings.HasPrefix(content, manual+"\n\n"+AgentsBlockStart+"\n") || !strings.HasSuffix(content, AgentsBlockEnd+"\n") {
+		t.Errorf("manual content not kept:\n%s", content)
+	}
+	want := "### Scope: `src/db/**`\n\n" +
+		"#### [ADR-0001: Database Access](docs/adr/0001-database-access.md)\n\n" +
+		"Constraints:\n- Use the repository pattern\n\nInvariants:\n- Queries are parameterized\n\n" +
+		"#### [ADR-0003: Migrations](docs/adr/0003-migrations.md)\n\n" +
+		"Agent guidance:\n\nNever edit an applied migration.\n\n" +
+		"### Scope: `src/**`\n"
+	if !strings.Contains(content, want) {
+		t.Errorf("block lacks %q:\n%s", want, content)
+	}
+	if strings.Contains(content, "ADR-0004") || !strings.Contains(content, "adopted ADRs in docs/adr.") {
+		t.Errorf("unexpected block:\n%s", content)
+	}
+
+	// Edits inside the block are overwritten, edits outside are kept
+	edited := strings.Replace(content, "Use the repository pattern", "Anything goes", 1) + "\nMore notes.\n"
+	if err := os.WriteFile(out, []byte(edited), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if result := export(true); result.UpToDate {
+		t.Error("check after editing the block: want out of date")
+	}
+	export(false)
+	if written, _ := os.ReadFile(out); string(written) != content+"\nMore notes.\n" {
+		t.Errorf("after re-export:\n%s", written)
+	}
+}
+
+func TestReplaceAgentsBlock(t *testing.T) {
+	block := AgentsBlockStart + "\nnew\n" + AgentsBlockEnd + "\n"
+	tests := []struct {
+		name    string
+		content string
+		want    string
+		wantErr bool
+	}{
+		{"empty file", "", block, false},
+		{"append", "# Agents\n", "# Agents\n\n" + block, false},
+		{"append without newline", "# Agents", "# Agents\n\n" + block, false},
+		{"replace", "a\n" + AgentsBlockStart + "\nold\n" + AgentsBlockEnd + "\nb\n", "a\n" + block + "b\n", false},
+		{"replace at end without newline", "a\n" + AgentsBlockStart + "\nold\n" + AgentsBlockEnd, "a\n" + block, false},
+		{"missing end", "a\n" + AgentsBlockStart + "\nold\n", "", true},
+		{"end before start", AgentsBlockEnd + "\n" + AgentsBlockStart + "\n", "", true},
+		{"two blocks", block + block, "", true},
+	}
+	for _, tt := range tests {
+		got, err := replaceAgentsBlock(tt.content, block)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: error = %v, wantErr %v", tt.name, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,314 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/sventorben/decider/internal/adr"
+)
+
+// Edge types of the decision graph.
+const (
+	EdgeSupersedes = "supersedes" // From the successor to the ADR it replaces
+	EdgeRelated    = "related"    // Undirected; From is the ADR listed first
+)
+
+// GraphConfig holds configuration for the graph command.
+type GraphConfig struct {
+	Dir    string
+	Tags   []string // Only ADRs with any of these tags
+	Root   string   // Only ADRs connected to this ADR (ID, number or filename)
+	Depth  int      // Maximum number of edges from Root; 0 for no limit
+	Format OutputFormat
+	Output *Output
+}
+
+// GraphResult holds the decision graph.
+type GraphResult struct {
+	Nodes []GraphNode `json:"nodes"`
+	Edges []GraphEdge `json:"edges"`
+}
+
+// GraphNode is an ADR in the decision graph.
+type GraphNode struct {
+	ADRID  string   `json:"adr_id"`
+	Title  string   `json:"title"`
+	Status string   `json:"status"`
+	File   string   `json:"file"`
+	Tags   []string `json:"tags,omitempty"`
+}
+
+// GraphEdge is a supersedes or related link between two ADRs.
+type GraphEdge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+	Type string `json:"type"`
+}
+
+// graphColors are the fill and stroke colors of the nodes of each status.
+var graphColors = map[adr.Status][2]string{
+	adr.StatusProposed:   {"#ddf4ff", "#0969da"},
+	adr.StatusAdopted:    {"#dafbe1", "#1a7f37"},
+	adr.StatusRejected:   {"#ffebe9", "#cf222e"},
+	adr.StatusDeprecated: {"#eaeef2", "#6e7781"},
+	adr.StatusSuperseded: {"#fff8c5", "#9a6700"},
+}
+
+// RunGraph builds the graph of supersedes, superseded_by and related_adrs
+// links between ADRs and writes it as a Mermaid flowchart, a Graphviz DOT
+// digraph, or TOON or JSON.
+func RunGraph(cfg *GraphConfig) (*GraphResult, error) {
+	switch cfg.Format {
+	case FormatMermaid, FormatDOT, FormatTOON, FormatJSON:
+	default:
+		return nil, fmt.Errorf("format %q is not supported by graph: use mermaid, dot, toon or json", cfg.Format)
+	}
+	if cfg.Depth < 0 {
+		return nil, fmt.Errorf("depth must not be negative")
+	}
+	if cfg.Depth > 0 && cfg.Root == "" {
+		return nil, fmt.Errorf("depth requires a root ADR")
+	}
+
+	adrs, err := adr.LoadAllADRs(cfg.Dir)
+	if err != nil {
+		return nil, fmt.Errorf("loading ADRs: %w", err)
+	}
+
+	result := buildGraph(adrs)
+	if len(cfg.Tags) > 0 {
+		result = filterGraph(result, taggedNodes(adrs, cfg.Tags))
+	}
+	if cfg.Root != "" {
+		rootPath, err := resolveADRPath(cfg.Root, cfg.Dir)
+		if err != nil {
+			return nil, err
+		}
+		root := ""
+		for _, a := range adrs {
+			if a.Filename == filepath.Base(rootPath) {
+				root = a.Frontmatter.ADRID
+			}
+		}
+		if root == "" {
+			return nil, fmt.Errorf("ADR not found: %s", cfg.Root)
+		}
+		keep := reachableNodes(result, root, cfg.Depth)
+		if !keep[root] {
+			return nil, fmt.Errorf("%s has none of the tags %s", root, strings.Join(cfg.Tags, ", "))
+		}
+		result = filterGraph(result, keep)
+	}
+
+	// Output
+	switch cfg.Format {
+	case FormatMermaid:
+		writeMermaid(cfg.Output.Writer, result)
+	case FormatDOT:
+		writeDOT(cfg.Output.Writer, result)
+	default:
+		_ = cfg.Output.PrintStructured(result)
+	}
+
+	return result, nil
+}
+
+// buildGraph returns all ADRs as nodes, in order, and the links between
+// them. A link recorded on both ADRs, such as supersedes and superseded_by,
+// is one edge; links to missing ADRs are left out, as check adr reports
+// them.
+func buildGraph(adrs []*adr.ADR) *GraphResult {
+	result := &GraphResult{Nodes: []GraphNode{}, Edges: []GraphEdge{}}
+	order := make(map[string]int)
+	for _, a := range adrs {
+		id := a.Frontmatter.ADRID
+		if _, ok := order[id]; ok {
+			continue // Duplicate IDs are reported by check adr
+		}
+		order[id] = len(result.Nodes)
+		result.Nodes = append(result.Nodes, GraphNode{
+			ADRID:  id,
+			Title:  a.Frontmatter.Title,
+			Status: string(a.Frontmatter.Status),
+			File:   a.Filename,
+			Tags:   a.Frontmatter.Tags,
+		})
+	}
+
+	seen := make(map[GraphEdge]bool)
+	add := func(from, to, typ string) {
+		_, okFrom := order[from]
+		_, okTo := order[to]
+		if !okFrom || !okTo || from == to {
+			return
+		}
+		if typ == EdgeRelated && order[to] < order[from] {
+			from, to = to, from
+		}
+		edge := GraphEdge{From: from, To: to, Type: typ}
+		if !seen[edge] {
+			seen[edge] = true
+			result.Edges = append(result.Edges, edge)
+		}
+	}
+	for _, a := range adrs {
+		id := a.Frontmatter.ADRID
+		for _, old := range a.Frontmatter.Supersedes {
+			add(id, old, EdgeSupersedes)
+		}
+		for _, successor := range a.Frontmatter.SupersededBy {
+			add(successor, id, EdgeSupersedes)
+		}
+		for _, related := range a.Frontmatter.RelatedADRs {
+			add(id, related, EdgeRelated)
+		}
+	}
+	return result
+}
+
+// taggedNodes returns the IDs of the ADRs with any of tags.
+func taggedNodes(adrs []*adr.ADR, tags []string) map[string]bool {
+	keep := make(map[string]bool)
+	for _, a := range adrs {
+		for _, tag := range a.Frontmatter.Tags {
+			for _, filterTag := range tags {
+				if tag == filterTag {
+					keep[a.Frontmatter.ADRID] = true
+				}
+			}
+		}
+	}
+	return keep
+}
+
+// reachableNodes returns the IDs of the nodes at most depth edges away from
+// root, in either direction, or all connected nodes when depth is 0. It is
+// empty when root is not a node.
+func reachableNodes(g *GraphResult, root string, depth int) map[string]bool {
+	keep := make(map[string]bool)
+	for _, n := range g.Nodes {
+		if n.ADRID == root {
+			keep[root] = true
+		}
+	}
+	if !keep[root] {
+		return keep
+	}
+
+	neighbors := make(map[string][]string)
+	for _, e := range g.Edges {
+		neighbors[e.From] = append(neighbors[e.From], e.To)
+		neighbors[e.To] = append(neighbors[e.To], e.From)
+	}
+	frontier := []string{root}
+	for level := 1; len(frontier) > 0 && (depth == 0 || level <= depth); level++ {
+		var next []string
+		for _, id := range frontier {
+			for _, n := range neighbors[id] {
+				if !keep[n] {
+					keep[n] = true
+					next = append(next, n)
+				}
+			}
+		}
+		frontier = next
+	}
+	return keep
+}
+
+// filterGraph keeps the nodes in keep and the edges between them.
+func filterGraph(g *GraphResult, keep map[string]bool) *GraphResult {
+	result := &GraphResult{Nodes: []GraphNode{}, Edges: []GraphEdge{}}
+	for _, n := range g.Nodes {
+		if keep[n.ADRID] {
+			result.Nodes = append(result.Nodes, n)
+		}
+	}
+	for _, e := range g.Edges {
+		if keep[e.From] && keep[e.To] {
+			result.Edges = append(result.Edges, e)
+		}
+	}
+	return result
+}
+
+// writeMermaid writes the graph as a Mermaid flowchart, ready to paste into
+// a ```mermaid block. Node IDs are derived from ADR IDs, and each status is
+// a class.
+func writeMermaid(w io.Writer, g *GraphResult) {
+	var b strings.Builder
+	b.WriteString("flowchart LR\n")
+	for _, n := range g.Nodes {
+		fmt.Fprintf(&b, "  %s[\"%s\"]\n", mermaidID(n.ADRID), mermaidEscape(n.ADRID+": "+n.Title))
+	}
+	for _, e := range g.Edges {
+		arrow := "-->"
+		if e.Type == EdgeRelated {
+			arrow = "-.-"
+		}
+		fmt.Fprintf(&b, "  %s %s|%s| %s\n", mermaidID(e.From), arrow, e.Type, mermaidID(e.To))
+	}
+	for _, status := range adr.ValidStatuses() {
+		var ids []string
+		for _, n := range g.Nodes {
+			if n.Status == string(status) {
+				ids = append(ids, mermaidID(n.ADRID))
+			}
+		}
+		if len(ids) == 0 {
+			continue
+		}
+		colors := graphColors[status]
+		fmt.Fprintf(&b, "  classDef %s fill:%s,stroke:%s,color:#1f2328\n", status, colors[0], colors[1])
+		fmt.Fprintf(&b, "  class %s %s\n", strings.Join(ids, ","), status)
+	}
+	_, _ = io.WriteString(w, b.String())
+}
+
+// mermaidID turns an ADR ID into a Mermaid node ID, which may only contain
+// letters, digits and underscores.
+func mermaidID(id string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '_' || r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' {
+			return r
+		}
+		return '_'
+	}, id)
+}
+
+// mermaidEscape escapes a quoted Mermaid label using entity codes.
+func mermaidEscape(s string) string {
+	return strings.NewReplacer("#", "#35;", `"`, "#quot;", "<", "#lt;", ">", "#gt;").Replace(s)
+}
+
+// writeDOT writes the graph as a Graphviz digraph.
+func writeDOT(w io.Writer, g *GraphResult) {
+	var b strings.Builder
+	b.WriteString("digraph decisions {\n")
+	b.WriteString("  rankdir=LR;\n")
+	b.WriteString("  node [shape=box, style=\"rounded,filled\", fontname=\"Helvetica\"];\n")
+	for _, n := range g.Nodes {
+		attrs := fmt.Sprintf("label=%s", dotQuote(n.ADRID+"\n"+n.Title))
+		if colors, ok := graphColors[adr.Status(n.Status)]; ok {
+			attrs += fmt.Sprintf(", fillcolor=%q, color=%q", colors[0], colors[1])
+		}
+		fmt.Fprintf(&b, "  %s [%s];\n", dotQuote(n.ADRID), attrs)
+	}
+	for _, e := range g.Edges {
+		attrs := fmt.Sprintf("label=%q", e.Type)
+		if e.Type == EdgeRelated {
+			attrs += ", style=dashed, dir=none"
+		}
+		fmt.Fprintf(&b, "  %s -> %s [%s];\n", dotQuote(e.From), dotQuote(e.To), attrs)
+	}
+	b.WriteString("}\n")
+	_, _ = io.WriteString(w, b.String())
+}
+
+// dotQuote quotes a DOT ID; newlines become centered line breaks.
+func dotQuote(s string) string {
+	s = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s)
+	return `"` + s + `"`
+}
@@ -0,0 +1,201 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// writeGraphADRs writes ADR-0001 superseded by ADR-0002, which is related to
+// ADR-0003, which is related to ADR-0004; ADR-0005 stands alone.
+func writeGraphADRs(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	writeTestADR(t, dir, "0001-use-mysql.md", `
+adr_id: ADR-0001
+title: Use MySQL
+status: superseded
+date: 2026-01-10
+tags: [database]
+superseded_by: [ADR-0002]
+`)
+	writeTestADR(t, dir, "0002-use-postgresql.md", `
+adr_id: ADR-0002
+title: Use "PostgreSQL"
+status: adopted
+date: 2026-01-12
+tags: [database]
+supersedes: [ADR-0001]
+related_adrs: [ADR-0003, ADR-0099]
+`)
+	writeTestADR(t, dir, "0003-migrations.md", `
+adr_id: ADR-0003
+title: Migrations
+status: proposed
+date: 2026-01-13
+tags: [database, tooling]
+related_adrs: [ADR-0002, ADR-0004]
+`)
+	writeTestADR(t, dir, "0004-ci.md", `
+adr_id: ADR-0004
+title: CI
+status: adopted
+date: 2026-01-14
+tags: [tooling]
+`)
+	writeTestADR(t, dir, "0005-logging.md", `
+adr_id: ADR-0005
+title: Logging
+status: rejected
+date: 2026-01-15
+`)
+	return dir
+}
+
+func TestRunGraph(t *testing.T) {
+	dir := writeGraphADRs(t)
+
+	tests := []struct {
+		name      string
+		cfg       GraphConfig
+		wantNodes []string
+		wantEdges []GraphEdge
+		wantErr   bool
+	}{
+		{
+			name:      "all",
+			wantNodes: []string{"ADR-0001", "ADR-0002", "ADR-0003", "ADR-0004", "ADR-0005"},
+			wantEdges: []GraphEdge{
+				{From: "ADR-0002", To: "ADR-0001", Type: EdgeSupersedes},
+				{From: "ADR-0002", To: "ADR-0003", Type: EdgeRelated},
+				{From: "ADR-0003", To: "ADR-0004", Type: EdgeRelated},
+			},
+		},
+		{
+			name:      "tag",
+			cfg:       GraphConfig{Tags: []string{"tooling"}},
+			wantNodes: []string{"ADR-0003", "ADR-0004"},
+			wantEdges: []GraphEdge{{From: "ADR-0003", To: "ADR-0004", Type: EdgeRelated}},
+		},
+		{
+			name:      "root",
+			cfg:       GraphConfig{Root: "ADR-0001"},
+			wantNodes: []string{"ADR-0001", "ADR-0002", "ADR-0003", "ADR-0004"},
+			wantEdges: []GraphEdge{
+				{From: "ADR-0002", To: "ADR-0001", Type: EdgeSupersedes},
+				{From: "ADR-0002", To: "ADR-0003", Type: EdgeRelated},
+				{From: "ADR-0003", To: "ADR-0004", Type: EdgeRelated},
+			},
+		},
+		{
+			name:      "root by number with depth",
+			cfg:       GraphConfig{Root: "4", Depth: 2},
+			wantNodes: []string{"ADR-0002", "ADR-0003", "ADR-0004"},
+			wantEdges: []GraphEdge{
+				{From: "ADR-0002", To: "ADR-0003", Type: EdgeRelated},
+				{From: "ADR-0003", To: "ADR-0004", Type: EdgeRelated},
+			},
+		},
+		{
+			name:      "root and tag",
+			cfg:       GraphConfig{Root: "ADR-0001", Tags: []string{"database"}},
+			wantNodes: []string{"ADR-0001", "ADR-0002", "ADR-0003"},
+			wantEdges: []GraphEdge{
+				{From: "ADR-0002", To: "ADR-0001", Type: EdgeSupersedes},
+				{From: "ADR-0002", To: "ADR-0003", Type: EdgeRelated},
+			},
+		},
+		{name: "root without the tag", cfg: GraphConfig{Root: "ADR-0005", Tags: []string{"database"}}, wantErr: true},
+		{name: "unknown root", cfg: GraphConfig{Root: "ADR-0042"}, wantErr: true},
+		{name: "depth without root", cfg: GraphConfig{Depth: 1}, wantErr: true},
+		{name: "negative depth", cfg: GraphConfig{Root: "ADR-0001", Depth: -1}, wantErr: true},
+		{name: "text format", cfg: GraphConfig{Format: FormatText}, wantErr: true},
+	}
+	for _, tt := range tests {
+		cfg := tt.cfg
+		cfg.Dir = dir
+		if cfg.Format == "" {
+			cfg.Format = FormatJSON
+		}
+		cfg.Output = &Output{Format: cfg.Format, Writer: &bytes.Buffer{}}
+
+		result, err := RunGraph(&cfg)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: error = %v, wantErr %v", tt.name, err, tt.wantErr)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		var nodes []string
+		for _, n := range result.Nodes {
+			nodes = append(nodes, n.ADRID)
+		}
+		if !reflect.DeepEqual(nodes, tt.wantNodes) {
+			t.Errorf("%s: nodes = %v, want %v", tt.name, nodes, tt.wantNodes)
+		}
+		if !reflect.DeepEqual(result.Edges, tt.wantEdges) {
+			t.Errorf("%s: edges = %v, want %v", tt.name, result.Edges, tt.wantEdges)
+		}
+	}
+}
+
+func TestRunGraphRender(t *testing.T) {
+	dir := writeGraphADRs(t)
+
+	tests := []struct {
+		format OutputFormat
+		golden string
+	}{
+		{FormatMermaid, `flowchart LR
+  ADR_0001["ADR-0001: Use MySQL"]
+  ADR_0002["ADR-0002: Use #quot;PostgreSQL#quot;"]
+  ADR_0003["ADR-0003: Migrations"]
+  ADR_0002 -->|supersedes| ADR_0001
+  ADR_0002 -.-|related| ADR_0003
+  classDef proposed fill:#ddf4ff,stroke:#0969da,color:#1f2328
+  class ADR_0003 proposed
+  classDef adopted fill:#dafbe1,stroke:#1a7f37,color:#1f2328
+  class ADR_0002 adopted
+  classDef superseded fill:#fff8c5,stroke:#9a6700,color:#1f2328
+  class ADR_0001 superseded
+`},
+		{FormatDOT, `digraph decisions {
+  rankdir=LR;
+  node [shape=box, style="rounded,filled", fontname="Helvetica"];
+  "ADR-0001" [label="ADR-0001\nUse MySQL", fillcolor="#fff8c5", color="#9a6700"];
+  "ADR-0002" [label="ADR-0002\nUse \"PostgreSQL\"", fillcolor="#dafbe1", color="#1a7f37"];
+  "ADR-0003" [label="ADR-0003\nMigrations", fillcolor="#ddf4ff", color="#0969da"];
+  "ADR-0002" -> "ADR-0001" [label="supersedes"];
+  "ADR-0002" -> "ADR-0003" [label="related", style=dashed, dir=none];
+}
+`},
+	}
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		cfg := &GraphConfig{Dir: dir, Tags: []string{"database"}, Format: tt.format, Output: &Output{Format: tt.format, Writer: &buf}}
+		if _, err := RunGraph(cfg); err != nil {
+			t.Fatal(err)
+		}
+		if buf.String() != tt.golden {
+			t.Errorf("%s output:\n%s\nwant:\n%s", tt.format, buf.String(), tt.golden)
+		}
+	}
+}
+
+func TestRunGraphEmpty(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "templates"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	result, err := RunGraph(&GraphConfig{Dir: dir, Format: FormatMermaid, Output: &Output{Format: FormatMermaid, Writer: &buf}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Nodes) != 0 || buf.String() != "flowchart LR\n" {
+		t.Errorf("empty graph = %+v, output %q", result, buf.String())
+	}
+}
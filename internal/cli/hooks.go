@@ -0,0 +1,344 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/sventorben/decider/internal/adr"
+	"github.com/sventorben/decider/internal/git"
+	"github.com/sventorben/decider/internal/hooks"
+)
+
+// AckTrailer is the commit trailer that acknowledges ADRs, e.g.
+// "ADR-Ack: ADR-0001, ADR-0004".
+const AckTrailer = "ADR-Ack"
+
+// HooksConfig holds configuration for the hooks install, uninstall and
+// status commands.
+type HooksConfig struct {
+	HooksDir string   // Git hooks directory; asked from git when empty
+	Hooks    []string // Hooks to act on; all of hooks.Names when empty
+	Format   OutputFormat
+	Output   *Output
+}
+
+// HooksResult holds the state of the hooks after a hooks command.
+type HooksResult struct {
+	Dir   string         `json:"dir"`
+	Hooks []hooks.Status `json:"hooks"`
+}
+
+// RunHooksInstall installs decider's git hooks, keeping existing hooks so
+// that they still run first.
+func RunHooksInstall(cfg *HooksConfig) (*HooksResult, error) {
+	result, err := runHooks(cfg, hooks.Install)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Format == FormatTOON || cfg.Format == FormatJSON {
+		_ = cfg.Output.PrintStructured(result)
+		return result, nil
+	}
+	for _, h := range result.Hooks {
+		if h.Chained {
+			cfg.Output.Success("Installed %s (runs the existing hook, kept as %s%s, first)", h.Hook, h.Hook, hooks.ChainSuffix)
+		} else {
+			cfg.Output.Success("Installed %s", h.Hook)
+		}
+	}
+	return result, nil
+}
+
+// RunHooksUninstall removes decider's git hooks and restores the hooks they
+// replaced.
+func RunHooksUninstall(cfg *HooksConfig) (*HooksResult, error) {
+	var before []hooks.Status
+	result, err := runHooks(cfg, func(dir string, names []string) ([]hooks.Status, error) {
+		var err error
+		if before, err = hooks.Inspect(dir, names); err != nil {
+			return nil, err
+		}
+		return hooks.Uninstall(dir, names)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Format == FormatTOON || cfg.Format == FormatJSON {
+		_ = cfg.Output.PrintStructured(result)
+		return result, nil
+	}
+	for i, h := range result.Hooks {
+		switch {
+		case !before[i].Installed:
+			cfg.Output.Println("%s is not installed", h.Hook)
+		case before[i].Chained:
+			cfg.Output.Success("Removed %s and restored the previous hook", h.Hook)
+		default:
+			cfg.Output.Success("Removed %s", h.Hook)
+		}
+	}
+	return result, nil
+}
+
+// RunHooksStatus reports which of decider's git hooks are installed.
+func RunHooksStatus(cfg *HooksConfig) (*HooksResult, error) {
+	result, err := runHooks(cfg, hooks.Inspect)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Format == FormatTOON || cfg.Format == FormatJSON {
+		_ = cfg.Output.PrintStructured(result)
+		return result, nil
+	}
+	cfg.Output.Println("Hooks directory: %s", result.Dir)
+	for _, h := range result.Hooks {
+		switch {
+		case h.Installed && h.Chained:
+			cfg.Output.Println("  %-11s installed, runs %s%s first", h.Hook, h.Hook, hooks.ChainSuffix)
+		case h.Installed:
+			cfg.Output.Println("  %-11s installed", h.Hook)
+		case h.Foreign:
+			cfg.Output.Println("  %-11s not installed (another hook is present)", h.Hook)
+		default:
+			cfg.Output.Println("  %-11s not installed", h.Hook)
+		}
+	}
+	return result, nil
+}
+
+func runHooks(cfg *HooksConfig, action func(dir string, names []string) ([]hooks.Status, error)) (*HooksResult, error) {
+	names := cfg.Hooks
+	if len(names) == 0 {
+		names = hooks.Names
+	}
+	if err := hooks.Validate(names); err != nil {
+		return nil, err
+	}
+
+	dir := cfg.HooksDir
+	if dir == "" {
+		var err error
+		if dir, err = git.HooksDir(); err != nil {
+			return nil, err
+		}
+	}
+
+	statuses, err := action(dir, names)
+	if err != nil {
+		return nil, err
+	}
+	return &HooksResult{Dir: dir, Hooks: statuses}, nil
+}
+
+// HookRunConfig holds configuration for running one of decider's git hooks.
+type HookRunConfig struct {
+	Hook   string   // pre-commit or commit-msg
+	Args   []string // Arguments git passed to the hook
+	Dir    string
+	Output *Output
+
+	// Settings for check adr, as for CheckADRConfig.
+	Strict           bool
+	RequiredSections []string
+	AllowedTags      []string
+	IDPrefix         string
+}
+
+// HookRunResult holds the result of running a git hook.
+type HookRunResult struct {
+	Passed bool `json:"passed"`
+
+	// Unacknowledged lists the ADRs the commit message should acknowledge
+	// but does not (commit-msg only).
+	Unacknowledged []string `json:"unacknowledged,omitempty"`
+}
+
+// RunHook runs the checks of a git hook against the staged changes.
+//
+// pre-commit validates the staged ADR files with check adr and enforces
+// structured constraints on the staged changes with check diff. commit-msg
+// requires an ADR-Ack trailer for every adopted ADR with constraints whose
+// scope covers a staged file.
+func RunHook(cfg *HookRunConfig) (*HookRunResult, error) {
+	switch cfg.Hook {
+	case "pre-commit":
+		return runPreCommit(cfg)
+	case "commit-msg":
+		return runCommitMsg(cfg)
+	}
+	return nil, hooks.Validate([]string{cfg.Hook})
+}
+
+func runPreCommit(cfg *HookRunConfig) (*HookRunResult, error) {
+	changes, err := git.Changes(git.Range{Staged: true})
+	if err != nil {
+		return nil, fmt.Errorf("getting staged changes: %w", err)
+	}
+	root, err := git.Root()
+	if err != nil {
+		return nil, err
+	}
+
+	// Check the commit as staged, ADRs included
+	adrs, err := loadStagedADRs(root, cfg.Dir)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &HookRunResult{Passed: true}
+
+	adrDir := relativePath(root, cfg.Dir)
+	var adrFiles []string
+	for _, c := range changes {
+		if c.Type != git.Deleted && path.Dir(c.Path) == adrDir && adr.IsADRFilename(path.Base(c.Path)) {
+			adrFiles = append(adrFiles, path.Base(c.Path))
+		}
+	}
+	if len(adrFiles) > 0 {
+		checked, err := RunCheckADR(&CheckADRConfig{
+			Dir:              cfg.Dir,
+			Strict:           cfg.Strict,
+			Format:           FormatText,
+			Output:           cfg.Output,
+			RequiredSections: cfg.RequiredSections,
+			AllowedTags:      cfg.AllowedTags,
+			IDPrefix:         cfg.IDPrefix,
+			Files:            adrFiles,
+			ADRs:             adrs,
+		})
+		if err != nil {
+			return nil, err
+		}
+		result.Passed = checked.Valid
+	}
+
+	diff, err := RunCheckDiff(&CheckDiffConfig{
+		Dir:    cfg.Dir,
+		Staged: true,
+		Format: FormatText,
+		Output: cfg.Output,
+		ADRs:   adrs,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(diff.Violations) > 0 {
+		result.Passed = false
+	}
+	return result, nil
+}
+
+// loadStagedADRs loads the ADRs in dir as they are staged in the index.
+// Their FilePath is still the path in the working tree.
+func loadStagedADRs(root, dir string) ([]*adr.ADR, error) {
+	index := &git.Snapshot{}
+	files, err := index.Files(relativePath(root, dir))
+	if err != nil {
+		return nil, fmt.Errorf("listing staged ADRs: %w", err)
+	}
+	adrs := []*adr.ADR{}
+	for _, f := range files {
+		name := path.Base(f)
+		if !adr.IsADRFilename(name) {
+			continue
+		}
+		data, ok, err := index.Read(f)
+		if err != nil {
+			return nil, fmt.Errorf("loading staged %s: %w", name, err)
+		}
+		if !ok {
+			return nil, fmt.Errorf("loading staged %s: file too large", name)
+		}
+		a, err := adr.ParseADR(string(data), name, filepath.Join(dir, name))
+		if err != nil {
+			return nil, fmt.Errorf("loading staged %s: %w", name, err)
+		}
+		adrs = append(adrs, a)
+	}
+	return adrs, nil
+}
+
+func runCommitMsg(cfg *HookRunConfig) (*HookRunResult, error) {
+	if len(cfg.Args) == 0 {
+		return nil, fmt.Errorf("commit-msg: missing commit message file")
+	}
+	message, err := os.ReadFile(cfg.Args[0])
+	if err != nil {
+		return nil, fmt.Errorf("reading commit message: %w", err)
+	}
+	values, err := git.Trailers(string(message), AckTrailer)
+	if err != nil {
+		return nil, err
+	}
+	acked := parseAcks(values)
+
+	changes, err := git.Changes(git.Range{Staged: true})
+	if err != nil {
+		return nil, fmt.Errorf("getting staged changes: %w", err)
+	}
+	root, err := git.Root()
+	if err != nil {
+		return nil, err
+	}
+	// Match the commit as staged, like pre-commit
+	adrs, err := loadStagedADRs(root, cfg.Dir)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &HookRunResult{Passed: true}
+	var missing []*adr.ADR
+	for _, a := range governingADRs(adrs, changes) {
+		if !acked[strings.ToUpper(a.Frontmatter.ADRID)] {
+			missing = append(missing, a)
+			result.Unacknowledged = append(result.Unacknowledged, a.Frontmatter.ADRID)
+		}
+	}
+	if len(missing) == 0 {
+		return result, nil
+	}
+
+	result.Passed = false
+	cfg.Output.Error("this commit changes files governed by %d unacknowledged ADR(s)", len(missing))
+	cfg.Output.Println("Read them and add a trailer to the commit message:")
+	cfg.Output.Println("")
+	cfg.Output.Println("  %s: %s", AckTrailer, strings.Join(result.Unacknowledged, ", "))
+	cfg.Output.Println("")
+	for _, a := range missing {
+		cfg.Output.Println("  %s: %s (%s)", a.Frontmatter.ADRID, a.Frontmatter.Title, relativePath(".", a.FilePath))
+	}
+	return result, nil
+}
+
+// governingADRs returns the adopted ADRs with constraints whose scope
+// covers one of the changes.
+func governingADRs(adrs []*adr.ADR, changes []git.Change) []*adr.ADR {
+	var governing []*adr.ADR
+	for _, a := range adrs {
+		if a.Frontmatter.Status != adr.StatusAdopted || len(a.Frontmatter.Constraints) == 0 {
+			continue
+		}
+		for _, c := range changes {
+			if m, _ := matchChange(a.Frontmatter.Scope.Paths, c); m.Matched {
+				governing = append(governing, a)
+				break
+			}
+		}
+	}
+	return governing
+}
+
+// parseAcks returns the upper-cased ADR IDs listed in ADR-Ack trailer
+// values, which may hold several IDs separated by commas or spaces.
+func parseAcks(values []string) map[string]bool {
+	acked := make(map[string]bool)
+	for _, v := range values {
+		for _, id := range strings.FieldsFunc(v, func(r rune) bool { return r == ',' || r == ' ' || r == '\t' }) {
+			acked[strings.ToUpper(id)] = true
+		}
+	}
+	return acked
+}
@@ -0,0 +1,170 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/sventorben/decider/internal/adr"
+	"github.com/sventorben/decider/internal/git"
+)
+
+func TestParseAcks(t *testing.T) {
+	got := parseAcks([]string{"ADR-0001, adr-0004", "ADR-0007 ADR-0008,"})
+	want := map[string]bool{"ADR-0001": true, "ADR-0004": true, "ADR-0007": true, "ADR-0008": true}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseAcks() = %v, want %v", got, want)
+	}
+}
+
+func TestGoverningADRs(t *testing.T) {
+	newADR := func(id string, status adr.Status, constraints int) *adr.ADR {
+		a := &adr.ADR{Frontmatter: adr.Frontmatter{ADRID: id, Status: status}}
+		a.Frontmatter.Scope.Paths = []string{"src/db/**"}
+		for i := 0; i < constraints; i++ {
+			a.Frontmatter.Constraints = append(a.Frontmatter.Constraints, adr.Constraint{Text: "Use repositories"})
+		}
+		return a
+	}
+	adrs := []*adr.ADR{
+		newADR("ADR-0001", adr.StatusAdopted, 1),
+		newADR("ADR-0002", adr.StatusAdopted, 0),  // no constraints
+		newADR("ADR-0003", adr.StatusProposed, 1), // not adopted
+	}
+
+	// A file moved out of scope is still governed
+	changes := []git.Change{{Path: "legacy/user.go", OldPath: "src/db/user.go", Type: git.Renamed}}
+	var ids []string
+	for _, a := range governingADRs(adrs, changes) {
+		ids = append(ids, a.Frontmatter.ADRID)
+	}
+	if want := []string{"ADR-0001"}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("governingADRs() = %v, want %v", ids, want)
+	}
+	if got := governingADRs(adrs, []git.Change{{Path: "README.md"}}); len(got) != 0 {
+		t.Errorf("governingADRs(README.md) = %v, want none", got)
+	}
+}
+
+func TestRunHooksInstallAndStatus(t *testing.T) {
+	dir := t.TempDir()
+	cfg := &HooksConfig{HooksDir: dir, Hooks: []string{"commit-msg"}, Format: FormatText, Output: testOutput()}
+
+	if _, err := RunHooksInstall(cfg); err != nil {
+		t.Fatalf("RunHooksInstall() error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "pre-commit")); !os.IsNotExist(err) {
+		t.Errorf("pre-commit installed although only commit-msg was asked for: %v", err)
+	}
+	result, err := RunHooksStatus(cfg)
+	if err != nil {
+		t.Fatalf("RunHooksStatus() error = %v", err)
+	}
+	if len(result.Hooks) != 1 || !result.Hooks[0].Installed {
+		t.Errorf("status = %+v, want commit-msg installed", result.Hooks)
+	}
+
+	cfg.Hooks = []string{"post-merge"}
+	if _, err := RunHooksStatus(cfg); err == nil {
+		t.Error("RunHooksStatus(post-merge) error = nil, want unknown hook")
+	}
+}
+
+func TestRunHookPreCommitChecksIndex(t *testing.T) {
+	run := initTestRepo(t)
+
+	adrDir := filepath.Join("docs", "adr")
+	for _, dir := range []string{adrDir, "src"} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	const rule = "adr_id: ADR-0001\ntitle: No TODOs\nstatus: adopted\ndate: 2026-01-16\nscope:\n  paths: [\"src/**\"]\nconstraints:\n  - forbid_regex: TODO\n"
+	writeTestADR(t, adrDir, "0001-no-todos.md", rule)
+	run("add", ".")
+	run("commit", "-q", "-m", "ADRs")
+
+	preCommit := func() bool {
+		t.Helper()
+		result, err := RunHook(&HookRunConfig{Hook: "pre-commit", Dir: adrDir, Output: testOutput()})
+		if err != nil {
+			t.Fatalf("RunHook() error = %v", err)
+		}
+		return result.Passed
+	}
+	write := func(name, content string) {
+		t.Helper()
+		if err := os.WriteFile(name, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// A staged violation, cleaned up in the working copy only
+	source := filepath.Join("src", "a.go")
+	write(source, "package src\n// TODO\n")
+	run("add", source)
+	write(source, "package src\n")
+	if preCommit() {
+		t.Error("staged source violation: passed, want failed")
+	}
+	run("add", source)
+	if !preCommit() {
+		t.Error("clean staged source: failed, want passed")
+	}
+
+	// A staged invalid ADR, fixed in the working copy only
+	adrFile := filepath.Join(adrDir, "0002-logging.md")
+	writeTestADR(t, adrDir, "0002-logging.md", "adr_id: ADR-0002\ntitle: Logging\nstatus: finished\ndate: 2026-01-16\n")
+	run("add", adrFile)
+	writeTestADR(t, adrDir, "0002-logging.md", "adr_id: ADR-0002\ntitle: Logging\nstatus: proposed\ndate: 2026-01-16\n")
+	if preCommit() {
+		t.Error("staged invalid ADR: passed, want failed")
+	}
+
+	// A rule staged in the index applies to the commit
+	run("add", adrFile)
+	write(filepath.Join(adrDir, "0001-no-todos.md"), "---\n"+strings.ReplaceAll(rule, "TODO", "FIXME")+"---\n"+testADRBody)
+	run("add", filepath.Join(adrDir, "0001-no-todos.md"))
+	writeTestADR(t, adrDir, "0001-no-todos.md", rule)
+	write(source, "package src\n// FIXME\n")
+	run("add", source)
+	write(source, "package src\n")
+	if preCommit() {
+		t.Error("staged rule: passed, want failed")
+	}
+}
+
+func TestRunHookCommitMsgChecksIndex(t *testing.T) {
+	run := initTestRepo(t)
+
+	adrDir := filepath.Join("docs", "adr")
+	for _, dir := range []string{adrDir, "src"} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeTestADR(t, adrDir, "0001-no-todos.md", "adr_id: ADR-0001\ntitle: No TODOs\nstatus: adopted\ndate: 2026-01-16\nscope:\n  paths: [\"src/**\"]\nconstraints:\n  - forbid_regex: TODO\n")
+	message := filepath.Join(t.TempDir(), "COMMIT_EDITMSG")
+	if err := os.WriteFile(message, []byte("Add a.go\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join("src", "a.go"), []byte("package src\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// The staged ADR governs the commit, although it is gone from the
+	// working tree
+	run("add", ".")
+	if err := os.RemoveAll(adrDir); err != nil {
+		t.Fatal(err)
+	}
+	result, err := RunHook(&HookRunConfig{Hook: "commit-msg", Args: []string{message}, Dir: adrDir, Output: testOutput()})
+	if err != nil {
+		t.Fatalf("RunHook() error = %v", err)
+	}
+	if want := []string{"ADR-0001"}; result.Passed || !reflect.DeepEqual(result.Unacknowledged, want) {
+		t.Errorf("commit-msg = %+v, want ADR-0001 unacknowledged", result)
+	}
+}
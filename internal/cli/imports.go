@@ -0,0 +1,135 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/sventorben/decider/internal/adr"
+	"github.com/sventorben/decider/internal/enforce"
+	"github.com/sventorben/decider/internal/git"
+	"github.com/sventorben/decider/internal/glob"
+)
+
+// CheckImportsConfig holds configuration for the check imports command.
+type CheckImportsConfig struct {
+	Dir    string
+	Root   string // Repository root; the git top-level (or working directory) when empty
+	Format OutputFormat
+	Output *Output
+}
+
+// CheckImportsResult holds the result of the check imports command.
+type CheckImportsResult struct {
+	Valid      bool                `json:"valid"`
+	Module     string              `json:"module,omitempty"`
+	Rules      int                 `json:"rules"`
+	Files      int                 `json:"files"`
+	Packages   int                 `json:"packages"`
+	Edges      int                 `json:"edges"`
+	Violations []enforce.Violation `json:"violations,omitempty"`
+}
+
+// RunCheckImports builds the Go import graph of the files in scope of ADRs
+// with imports rules and reports every edge that breaks an adopted ADR.
+func RunCheckImports(cfg *CheckImportsConfig) (*CheckImportsResult, error) {
+	adrs, err := adr.LoadAllADRs(cfg.Dir)
+	if err != nil {
+		return nil, fmt.Errorf("loading ADRs: %w", err)
+	}
+
+	root := cfg.Root
+	if root == "" {
+		if root, err = git.Root(); err != nil {
+			root = "."
+		}
+	}
+
+	result := &CheckImportsResult{Valid: true}
+
+	// Collect the adopted ADRs with imports rules and the pattern lists they
+	// cover; each list is matched on its own, so that the exclusions of one
+	// do not take files out of another
+	var ruled []*adr.ADR
+	var scopes [][]string
+	everything := false
+	for _, a := range adrs {
+		if a.Frontmatter.Status != adr.StatusAdopted {
+			continue
+		}
+		hasRule := false
+		for _, c := range a.Frontmatter.Constraints {
+			if c.Imports == nil {
+				continue
+			}
+			hasRule = true
+			result.Rules++
+			switch {
+			case len(c.Paths) > 0:
+				scopes = append(scopes, c.Paths)
+			case len(a.Frontmatter.Scope.Paths) > 0:
+				scopes = append(scopes, a.Frontmatter.Scope.Paths)
+			default:
+				everything = true
+			}
+		}
+		if hasRule {
+			ruled = append(ruled, a)
+		}
+	}
+
+	if result.Rules > 0 {
+		files, err := enforce.FindGoFiles(root)
+		if err != nil {
+			return nil, err
+		}
+		if !everything {
+			files = filterScopes(scopes, files)
+		}
+
+		graph, err := enforce.BuildImportGraph(enforce.Dir(root), files)
+		if err != nil {
+			return nil, fmt.Errorf("building import graph: %w", err)
+		}
+
+		result.Module = graph.Module
+		result.Files = len(files)
+		result.Packages = graph.Packages()
+		result.Edges = len(graph.Edges)
+		result.Violations = enforce.CheckImports(ruled, graph)
+		result.Valid = len(result.Violations) == 0
+	}
+
+	// Output
+	if cfg.Format == FormatTOON || cfg.Format == FormatJSON {
+		_ = cfg.Output.PrintStructured(result)
+	} else if result.Rules == 0 {
+		cfg.Output.Println("No import rules found in adopted ADRs.")
+	} else {
+		cfg.Output.Println("Checked %d import rule(s) against %d Go file(s) in %d package(s), %d import edge(s)",
+			result.Rules, result.Files, result.Packages, result.Edges)
+		if result.Valid {
+			cfg.Output.Success("No import boundary violations")
+		} else {
+			cfg.Output.Error("Found %d import boundary violation(s):", len(result.Violations))
+			for _, v := range result.Violations {
+				cfg.Output.Println("  %s", v)
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// filterScopes returns the files that match any of the pattern lists, in
+// order.
+func filterScopes(scopes [][]string, files []string) []string {
+	var result []string
+	for _, f := range files {
+		for _, patterns := range scopes {
+			if glob.MatchAny(patterns, f) {
+				result = append(result, f)
+				break
+			}
+		}
+	}
+	return result
+}
@@ -0,0 +1,71 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRunCheckImports(t *testing.T) {
+	root := t.TempDir()
+	adrDir := filepath.Join(root, "docs", "adr")
+	for _, dir := range []string{adrDir, filepath.Join(root, "internal", "api"), filepath.Join(root, "internal", "db")} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	files := map[string]string{
+		"go.mod":              "module example.com/app\n",
+		"internal/api/api.go": "package api\n\nimport \"database/sql\"\n",
+		"internal/db/db.go":   "package db\n\nimport \"database/sql\"\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(root, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeTestADR(t, adrDir, "0001-db-access.md", `
+adr_id: ADR-0001
+title: Database access
+status: adopted
+date: 2026-01-16
+scope:
+  paths: ["internal/**"]
+constraints:
+  - imports:
+      to: [database/sql]
+      allow_from: ["internal/db/**"]
+`)
+	// Its exclusion must not take files out of the scope of ADR-0001
+	writeTestADR(t, adrDir, "0002-http.md", `
+adr_id: ADR-0002
+title: HTTP
+status: adopted
+date: 2026-01-16
+scope:
+  paths: ["internal/**", "!internal/api/**"]
+constraints:
+  - imports:
+      to: [net/http]
+      forbid_from: ["internal/**"]
+`)
+
+	result, err := RunCheckImports(&CheckImportsConfig{
+		Dir:    adrDir,
+		Root:   root,
+		Format: FormatText,
+		Output: testOutput(),
+	})
+	if err != nil {
+		t.Fatalf("RunCheckImports() error = %v", err)
+	}
+	if result.Valid {
+		t.Error("RunCheckImports() Valid = true, want false")
+	}
+	if result.Rules != 2 || result.Files != 2 || result.Edges != 2 || result.Packages != 2 {
+		t.Errorf("RunCheckImports() counts = %+v", result)
+	}
+	if len(result.Violations) != 1 || result.Violations[0].File != "internal/api/api.go" || result.Violations[0].Line != 3 {
+		t.Errorf("Violations = %+v", result.Violations)
+	}
+}
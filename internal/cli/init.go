@@ -54,7 +54,11 @@ adrs: []
 	return nil
 }
 
+// defaultTemplate is written to templates/adr.md. Its frontmatter only
+// documents the schema; 'decider new' renders the body and generates the
+// frontmatter from its flags.
 const defaultTemplate = `---
+# Example frontmatter only: decider new generates the real frontmatter.
 adr_id: ADR-NNNN
 title: "Your Decision Title Here"
 status: proposed
@@ -75,32 +79,7 @@ superseded_by: []
 related_adrs: []
 ---
 
-# ADR-NNNN: Your Decision Title Here
-
-## Context
-
-Describe the context and background that led to this decision. What problem are we solving? What forces are at play?
-
-## Decision
-
-State the decision clearly and concisely. Explain the reasoning behind the choice.
-
-## Alternatives Considered
-
-| Alternative | Pros | Cons |
-|-------------|------|------|
-| **Option A** | Benefits | Drawbacks |
-| **Option B** | Benefits | Drawbacks |
-
-## Consequences
-
-**Positive:**
-- First positive consequence
-- Second positive consequence
-
-**Negative:**
-- First negative consequence (and mitigation if any)
-
+` + fullTemplate + `
 ## Agent Guidance
 
 _Optional section. Include specific instructions for AI agents working in the affected scope._
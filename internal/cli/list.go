@@ -135,17 +135,8 @@ func matchesFilters(entry index.Entry, cfg *ListConfig) bool {
 	}
 
 	// Filter by path (matches against scope.paths)
-	if cfg.Path != "" {
-		found := false
-		for _, scopePath := range entry.ScopePaths {
-			if glob.Match(scopePath, cfg.Path) {
-				found = true
-				break
-			}
-		}
-		if !found {
-			return false
-		}
+	if cfg.Path != "" && !glob.MatchAny(entry.ScopePaths, cfg.Path) {
+		return false
 	}
 
 	return true
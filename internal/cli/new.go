@@ -4,7 +4,6 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
-	"strings"
 	"time"
 
 	"github.com/sventorben/decider/internal/adr"
@@ -14,15 +13,24 @@ import (
 
 // NewConfig holds configuration for the new command.
 type NewConfig struct {
-	Title   string
-	Dir     string
-	Tags    []string
-	Paths   []string
-	Owners  []string
-	Status  string
-	NoIndex bool
-	Format  OutputFormat
-	Output  *Output
+	Title    string
+	Dir      string
+	Tags     []string
+	Paths    []string
+	Owners   []string
+	Status   string
+	Template string // Template name in <dir>/templates, or a built-in; defaults to "adr"
+	NoIndex  bool
+	Format   OutputFormat
+	Output   *Output
+
+	// IDPrefix is the ADR ID prefix; defaults to "ADR".
+	IDPrefix string
+	// AllowedTags restricts the tags that may be used; any tag when empty.
+	AllowedTags []string
+
+	// Supersedes lists ADR IDs the new ADR replaces. Used by RunSupersede.
+	Supersedes []string
 }
 
 // NewResult holds the result of creating a new ADR.
@@ -36,6 +44,61 @@ type NewResult struct {
 
 // RunNew creates a new ADR.
 func RunNew(cfg *NewConfig) (*NewResult, error) {
+	draft, err := prepareNew(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	// Ensure directory exists
+	if err := os.MkdirAll(cfg.Dir, 0755); err != nil {
+		return nil, fmt.Errorf("creating directory: %w", err)
+	}
+
+	// Write file
+	if err := os.WriteFile(draft.FilePath, []byte(draft.Content), 0644); err != nil {
+		return nil, fmt.Errorf("writing ADR file: %w", err)
+	}
+
+	result := &NewResult{
+		ADRID:    draft.Frontmatter.ADRID,
+		Title:    cfg.Title,
+		File:     draft.Filename,
+		FilePath: draft.FilePath,
+		Number:   draft.Number,
+	}
+
+	// Update index unless disabled
+	if !cfg.NoIndex {
+		if err := index.WriteToDir(cfg.Dir); err != nil {
+			// Non-fatal: warn but don't fail
+			cfg.Output.Error("warning: could not update index: %v", err)
+		}
+	}
+
+	// Output result
+	if cfg.Format == FormatTOON || cfg.Format == FormatJSON {
+		_ = cfg.Output.PrintStructured(result)
+	} else {
+		cfg.Output.Success("Created %s", draft.FilePath)
+		cfg.Output.Println("  ADR ID: %s", result.ADRID)
+		cfg.Output.Println("  Title:  %s", cfg.Title)
+		cfg.Output.Println("  Status: %s", draft.Frontmatter.Status)
+	}
+
+	return result, nil
+}
+
+// newADRDraft is a fully rendered ADR that has not been written to disk yet.
+type newADRDraft struct {
+	Frontmatter *adr.Frontmatter
+	Filename    string
+	FilePath    string
+	Number      int
+	Content     string
+}
+
+// prepareNew validates the configuration and renders the new ADR in memory.
+func prepareNew(cfg *NewConfig) (*newADRDraft, error) {
 	// Validate inputs
 	if err := validate.ValidateTitle(cfg.Title); err != nil {
 		return nil, fmt.Errorf("invalid title: %w", err)
@@ -43,6 +106,13 @@ func RunNew(cfg *NewConfig) (*NewResult, error) {
 	if err := validate.ValidateTags(cfg.Tags); err != nil {
 		return nil, fmt.Errorf("invalid tags: %w", err)
 	}
+	if len(cfg.AllowedTags) > 0 {
+		for _, tag := range cfg.Tags {
+			if !contains(cfg.AllowedTags, tag) {
+				return nil, fmt.Errorf("invalid tags: %q is not in the allowed tags %v", tag, cfg.AllowedTags)
+			}
+		}
+	}
 	if err := validate.ValidateScopePaths(cfg.Paths); err != nil {
 		return nil, fmt.Errorf("invalid paths: %w", err)
 	}
@@ -62,7 +132,12 @@ func RunNew(cfg *NewConfig) (*NewResult, error) {
 	// Generate filename
 	filename := adr.GenerateFilename(number, cfg.Title)
 	filePath := filepath.Join(cfg.Dir, filename)
-	adrID := fmt.Sprintf("ADR-%04d", number)
+	adrID := adr.FormatID(cfg.IDPrefix, number)
+
+	supersedes := cfg.Supersedes
+	if supersedes == nil {
+		supersedes = []string{}
+	}
 
 	// Create frontmatter
 	fm := &adr.Frontmatter{
@@ -72,116 +147,49 @@ func RunNew(cfg *NewConfig) (*NewResult, error) {
 		Date:         time.Now().Format("2006-01-02"),
 		Scope:        adr.Scope{Paths: cfg.Paths},
 		Tags:         cfg.Tags,
-		Constraints:  []string{},
+		Constraints:  []adr.Constraint{},
 		Invariants:   []string{},
-		Supersedes:   []string{},
+		Supersedes:   supersedes,
 		SupersededBy: []string{},
 		RelatedADRs:  []string{},
 	}
 
-	// Generate content
-	content, err := generateADRContent(fm, cfg.Title)
+	// Generate content from the requested template
+	templateBody, err := loadTemplate(cfg.Dir, cfg.Template)
 	if err != nil {
-		return nil, fmt.Errorf("generating ADR content: %w", err)
-	}
-
-	// Ensure directory exists
-	if err := os.MkdirAll(cfg.Dir, 0755); err != nil {
-		return nil, fmt.Errorf("creating directory: %w", err)
-	}
-
-	// Write file
-	if err := os.WriteFile(filePath, []byte(content), 0644); err != nil {
-		return nil, fmt.Errorf("writing ADR file: %w", err)
-	}
-
-	result := &NewResult{
-		ADRID:    adrID,
-		Title:    cfg.Title,
-		File:     filename,
-		FilePath: filePath,
-		Number:   number,
-	}
-
-	// Update index unless disabled
-	if !cfg.NoIndex {
-		if err := index.WriteToDir(cfg.Dir); err != nil {
-			// Non-fatal: warn but don't fail
-			cfg.Output.Error("warning: could not update index: %v", err)
-		}
+		return nil, err
 	}
-
-	// Output result
-	if cfg.Format == FormatTOON || cfg.Format == FormatJSON {
-		_ = cfg.Output.PrintStructured(result)
-	} else {
-		cfg.Output.Success("Created %s", filePath)
-		cfg.Output.Println("  ADR ID: %s", adrID)
-		cfg.Output.Println("  Title:  %s", cfg.Title)
-		cfg.Output.Println("  Status: %s", status)
+	content, err := generateADRContent(fm, templateBody)
+	if err != nil {
+		return nil, fmt.Errorf("generating ADR content: %w", err)
 	}
 
-	return result, nil
+	return &newADRDraft{
+		Frontmatter: fm,
+		Filename:    filename,
+		FilePath:    filePath,
+		Number:      number,
+		Content:     content,
+	}, nil
 }
 
-func generateADRContent(fm *adr.Frontmatter, title string) (string, error) {
+func generateADRContent(fm *adr.Frontmatter, templateBody string) (string, error) {
 	fmStr, err := adr.SerializeFrontmatter(fm)
 	if err != nil {
 		return "", err
 	}
 
-	body := fmt.Sprintf(`
-# %s: %s
-
-## Context
-
-_Describe the context and background that led to this decision. What problem are we solving? What forces are at play?_
-
-Decision drivers:
-- _Key driver 1 that influenced the decision_
-- _Key driver 2_
-- _Key driver 3_
-
-## Decision
-
-_State the decision clearly and concisely._
-
-### [Chosen Option]: Adopted
-
-**Adopted because:**
-- _Clear, concrete reason why this option was chosen_
-- _Tie reasons to decision drivers above_
-- _Technical, operational, or strategic justification_
-
-**Adopted despite:**
-- _Known downside or trade-off we consciously accepted_
-- _Cost or weakness compared to alternatives_
-- _Risk we are taking on_
-
-## Alternatives Considered
-
-### [Alternative A]: Rejected
-
-**Rejected because:**
-- _Clear, concrete reason why this option was not chosen_
-- _Technical, organizational, or strategic reason_
-- _How it failed to meet decision drivers_
-
-**Rejected despite:**
-- _Legitimate strength of this option_
-- _Benefit that made it attractive_
-- _Reason it was seriously considered_
-
-## Consequences
-
-**Positive:**
-- _First positive consequence_
-- _Second positive consequence_
-
-**Negative:**
-- _First negative consequence (and mitigation if any)_
-- _Second negative consequence_
-`, fm.ADRID, title)
+	body, err := renderTemplate(templateBody, TemplateData{
+		ADRID:  fm.ADRID,
+		Title:  fm.Title,
+		Date:   fm.Date,
+		Status: string(fm.Status),
+		Tags:   fm.Tags,
+		Paths:  fm.Scope.Paths,
+	})
+	if err != nil {
+		return "", err
+	}
 
-	return fmStr + strings.TrimPrefix(body, "\n"), nil
+	return fmStr + "\n" + body, nil
 }
@@ -19,6 +19,13 @@ const (
 	FormatTOON OutputFormat = "toon"
 	FormatJSON OutputFormat = "json"
 	FormatYAML OutputFormat = "yaml"
+
+	// Report formats, supported only by the commands that document them
+	FormatSARIF    OutputFormat = "sarif"
+	FormatGitHub   OutputFormat = "github"
+	FormatMarkdown OutputFormat = "markdown"
+	FormatMermaid  OutputFormat = "mermaid"
+	FormatDOT      OutputFormat = "dot"
 )
 
 // DefaultStructuredFormat is the default format for machine-readable output.
@@ -35,11 +42,27 @@ func ParseOutputFormat(s string) (OutputFormat, error) {
 		return FormatJSON, nil
 	case "yaml":
 		return FormatYAML, nil
+	case "sarif":
+		return FormatSARIF, nil
+	case "github":
+		return FormatGitHub, nil
+	case "markdown", "md":
+		return FormatMarkdown, nil
+	case "mermaid":
+		return FormatMermaid, nil
+	case "dot":
+		return FormatDOT, nil
 	default:
-		return "", fmt.Errorf("invalid format %q: must be text, toon, json, or yaml", s)
+		return "", fmt.Errorf("invalid format %q: must be text, toon, json, yaml, sarif, github, markdown, mermaid, or dot", s)
 	}
 }
 
+// IsReport reports whether the format is a report format, which only some
+// commands support.
+func (f OutputFormat) IsReport() bool {
+	return f == FormatSARIF || f == FormatGitHub || f == FormatMarkdown || f == FormatMermaid || f == FormatDOT
+}
+
 // Output handles writing output in different formats.
 type Output struct {
 	Format OutputFormat
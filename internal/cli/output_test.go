@@ -21,6 +21,8 @@ func TestParseOutputFormat(t *testing.T) {
 		{"JSON", FormatJSON, false},
 		{"yaml", FormatYAML, false},
 		{"YAML", FormatYAML, false},
+		{"mermaid", FormatMermaid, false},
+		{"dot", FormatDOT, false},
 		{"invalid", "", true},
 		{"xml", "", true},
 	}
@@ -0,0 +1,187 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/sventorben/decider/internal/adr"
+	"github.com/sventorben/decider/internal/glob"
+)
+
+// CheckOverlapsConfig holds configuration for the check overlaps command.
+type CheckOverlapsConfig struct {
+	Dir    string
+	Strict bool // Fail on overlaps between unlinked ADRs
+	Format OutputFormat
+	Output *Output
+
+	// Files, when non-nil, are the repository files instead of the ones git
+	// lists.
+	Files []string
+}
+
+// CheckOverlapsResult holds the result of the check overlaps command.
+type CheckOverlapsResult struct {
+	Valid    bool           `json:"valid"`
+	ADRs     int            `json:"adrs"`  // Adopted ADRs with scope paths
+	Files    int            `json:"files"` // Files in the working tree
+	Overlaps []ScopeOverlap `json:"overlaps"`
+	Unlinked int            `json:"unlinked"` // Overlaps between unlinked ADRs
+}
+
+// ScopeOverlap describes the files two adopted ADRs both govern.
+type ScopeOverlap struct {
+	ADR   OverlapSide `json:"adr"`
+	Other OverlapSide `json:"other"`
+
+	// Link is "related" or "supersedes" when the ADRs reference each other,
+	// and empty otherwise.
+	Link  string   `json:"link,omitempty"`
+	Count int      `json:"count"`
+	Files []string `json:"files"`
+}
+
+// OverlapSide is one of the ADRs of an overlap, with the scope patterns that
+// cover the shared files.
+type OverlapSide struct {
+	ADRID    string   `json:"adr_id"`
+	Title    string   `json:"title"`
+	File     string   `json:"file"`
+	Patterns []string `json:"patterns"`
+}
+
+// RunCheckOverlaps reports every pair of adopted ADRs whose scopes cover
+// the same files, and flags the pairs that are not linked via related_adrs
+// or supersession.
+func RunCheckOverlaps(cfg *CheckOverlapsConfig) (*CheckOverlapsResult, error) {
+	adrs, err := adr.LoadAllADRs(cfg.Dir)
+	if err != nil {
+		return nil, fmt.Errorf("loading ADRs: %w", err)
+	}
+
+	root, files, err := repositoryFiles(cfg.Files)
+	if err != nil {
+		return nil, err
+	}
+
+	var scoped []*adr.ADR
+	for _, a := range adrs {
+		if a.Frontmatter.Status == adr.StatusAdopted && len(a.Frontmatter.Scope.Paths) > 0 {
+			scoped = append(scoped, a)
+		}
+	}
+
+	// The deciding pattern of each ADR for each file it covers
+	covered := make([]map[string]string, len(scoped))
+	for i, a := range scoped {
+		covered[i] = make(map[string]string)
+		for _, f := range files {
+			if m := glob.MatchScope(a.Frontmatter.Scope.Paths, f); m.Matched {
+				covered[i][f] = m.Pattern
+			}
+		}
+	}
+
+	result := &CheckOverlapsResult{
+		ADRs:     len(scoped),
+		Files:    len(files),
+		Overlaps: []ScopeOverlap{},
+	}
+	for i, a := range scoped {
+		for j := i + 1; j < len(scoped); j++ {
+			b := scoped[j]
+			var shared []string
+			for _, f := range files {
+				if _, ok := covered[i][f]; ok {
+					if _, ok := covered[j][f]; ok {
+						shared = append(shared, f)
+					}
+				}
+			}
+			if len(shared) == 0 {
+				continue
+			}
+
+			overlap := ScopeOverlap{
+				ADR:   overlapSide(root, a, covered[i], shared),
+				Other: overlapSide(root, b, covered[j], shared),
+				Link:  adrLink(a, b),
+				Count: len(shared),
+				Files: shared,
+			}
+			if overlap.Link == "" {
+				result.Unlinked++
+			}
+			result.Overlaps = append(result.Overlaps, overlap)
+		}
+	}
+	result.Valid = !cfg.Strict || result.Unlinked == 0
+
+	// Output
+	if cfg.Format == FormatTOON || cfg.Format == FormatJSON {
+		_ = cfg.Output.PrintStructured(result)
+		return result, nil
+	}
+
+	cfg.Output.Println("Checked %d adopted ADR(s) against %d file(s)", result.ADRs, result.Files)
+	cfg.Output.Println("")
+	if len(result.Overlaps) == 0 {
+		cfg.Output.Success("No ADRs govern the same files")
+		return result, nil
+	}
+	for _, o := range result.Overlaps {
+		link := "not linked"
+		if o.Link != "" {
+			link = o.Link
+		}
+		cfg.Output.Println("%s and %s share %d file(s) (%s)", o.ADR.ADRID, o.Other.ADRID, o.Count, link)
+		for _, side := range []OverlapSide{o.ADR, o.Other} {
+			cfg.Output.Println("  %s: %s", side.ADRID, strings.Join(side.Patterns, ", "))
+		}
+	}
+	cfg.Output.Println("")
+	if result.Unlinked == 0 {
+		cfg.Output.Success("All %d overlap(s) are between linked ADRs", len(result.Overlaps))
+	} else if cfg.Strict {
+		cfg.Output.Error("%d of %d overlap(s) are between ADRs not linked via related_adrs or supersession; link or rescope them", result.Unlinked, len(result.Overlaps))
+	} else {
+		cfg.Output.Warn("%d of %d overlap(s) are between ADRs not linked via related_adrs or supersession; link or rescope them", result.Unlinked, len(result.Overlaps))
+	}
+
+	return result, nil
+}
+
+// overlapSide describes a, listing its patterns that cover one of shared in
+// the order of its scope.
+func overlapSide(root string, a *adr.ADR, covered map[string]string, shared []string) OverlapSide {
+	used := make(map[string]bool)
+	for _, f := range shared {
+		used[covered[f]] = true
+	}
+	side := OverlapSide{
+		ADRID: a.Frontmatter.ADRID,
+		Title: a.Frontmatter.Title,
+		File:  relativePath(root, a.FilePath),
+	}
+	for _, p := range a.Frontmatter.Scope.Paths {
+		if used[p] && !contains(side.Patterns, p) {
+			side.Patterns = append(side.Patterns, p)
+		}
+	}
+	return side
+}
+
+// adrLink returns how a and b reference each other: "supersedes" for a
+// supersession link in either direction, "related" for related_adrs, or
+// empty if they do not.
+func adrLink(a, b *adr.ADR) string {
+	fa, fb := a.Frontmatter, b.Frontmatter
+	switch {
+	case contains(fa.Supersedes, fb.ADRID), contains(fa.SupersededBy, fb.ADRID),
+		contains(fb.Supersedes, fa.ADRID), contains(fb.SupersededBy, fa.ADRID):
+		return "supersedes"
+	case contains(fa.RelatedADRs, fb.ADRID), contains(fb.RelatedADRs, fa.ADRID):
+		return "related"
+	}
+	return ""
+}
@@ -0,0 +1,115 @@
+package cli
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/sventorben/decider/internal/adr"
+)
+
+func TestRunCheckOverlaps(t *testing.T) {
+	dir := t.TempDir()
+	writeTestADR(t, dir, "0001-layering.md", `
+adr_id: ADR-0001
+title: Layering
+status: adopted
+date: 2026-01-16
+scope:
+  paths: ["src/**", "!src/gen/**"]
+`)
+	writeTestADR(t, dir, "0002-database-access.md", `
+adr_id: ADR-0002
+title: Database Access
+status: adopted
+date: 2026-01-16
+related_adrs: [ADR-0001]
+scope:
+  paths: ["src/db/**"]
+`)
+	writeTestADR(t, dir, "0003-code-generation.md", `
+adr_id: ADR-0003
+title: Code Generation
+status: adopted
+date: 2026-01-16
+scope:
+  paths: ["src/gen/**", "src/db/schema.go"]
+`)
+	writeTestADR(t, dir, "0004-old-generator.md", `
+adr_id: ADR-0004
+title: Old Generator
+status: deprecated
+date: 2026-01-16
+scope:
+  paths: ["src/gen/**"]
+`)
+	file := func(name string) string { return relativePath(".", filepath.Join(dir, name)) }
+	files := []string{"src/api/handler.go", "src/db/query.go", "src/db/schema.go", "src/gen/types.go"}
+
+	for _, strict := range []bool{false, true} {
+		result, err := RunCheckOverlaps(&CheckOverlapsConfig{
+			Dir:    dir,
+			Strict: strict,
+			Files:  files,
+			Format: FormatJSON,
+			Output: testOutput(),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result.Valid == strict {
+			t.Errorf("strict %v: Valid = %v", strict, result.Valid)
+		}
+		if result.ADRs != 3 || result.Unlinked != 2 {
+			t.Errorf("ADRs = %d, Unlinked = %d, want 3 and 2", result.ADRs, result.Unlinked)
+		}
+
+		// The excluded src/gen/types.go is shared with no one
+		want := []ScopeOverlap{
+			{
+				ADR:   OverlapSide{ADRID: "ADR-0001", Title: "Layering", File: file("0001-layering.md"), Patterns: []string{"src/**"}},
+				Other: OverlapSide{ADRID: "ADR-0002", Title: "Database Access", File: file("0002-database-access.md"), Patterns: []string{"src/db/**"}},
+				Link:  "related",
+				Count: 2,
+				Files: []string{"src/db/query.go", "src/db/schema.go"},
+			},
+			{
+				ADR:   OverlapSide{ADRID: "ADR-0001", Title: "Layering", File: file("0001-layering.md"), Patterns: []string{"src/**"}},
+				Other: OverlapSide{ADRID: "ADR-0003", Title: "Code Generation", File: file("0003-code-generation.md"), Patterns: []string{"src/db/schema.go"}},
+				Count: 1,
+				Files: []string{"src/db/schema.go"},
+			},
+			{
+				ADR:   OverlapSide{ADRID: "ADR-0002", Title: "Database Access", File: file("0002-database-access.md"), Patterns: []string{"src/db/**"}},
+				Other: OverlapSide{ADRID: "ADR-0003", Title: "Code Generation", File: file("0003-code-generation.md"), Patterns: []string{"src/db/schema.go"}},
+				Count: 1,
+				Files: []string{"src/db/schema.go"},
+			},
+		}
+		if !reflect.DeepEqual(result.Overlaps, want) {
+			t.Errorf("Overlaps = %+v\nwant %+v", result.Overlaps, want)
+		}
+	}
+}
+
+func TestADRLink(t *testing.T) {
+	newADR := func(id string, fm adr.Frontmatter) *adr.ADR {
+		fm.ADRID = id
+		return &adr.ADR{Frontmatter: fm}
+	}
+	tests := []struct {
+		name string
+		a, b *adr.ADR
+		want string
+	}{
+		{"unlinked", newADR("ADR-0001", adr.Frontmatter{}), newADR("ADR-0002", adr.Frontmatter{}), ""},
+		{"related", newADR("ADR-0001", adr.Frontmatter{}), newADR("ADR-0002", adr.Frontmatter{RelatedADRs: []string{"ADR-0001"}}), "related"},
+		{"supersedes", newADR("ADR-0001", adr.Frontmatter{Supersedes: []string{"ADR-0002"}}), newADR("ADR-0002", adr.Frontmatter{}), "supersedes"},
+		{"superseded by", newADR("ADR-0001", adr.Frontmatter{}), newADR("ADR-0002", adr.Frontmatter{SupersededBy: []string{"ADR-0001"}}), "supersedes"},
+	}
+	for _, tt := range tests {
+		if got := adrLink(tt.a, tt.b); got != tt.want {
+			t.Errorf("%s: adrLink() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,252 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/sventorben/decider/internal/git"
+)
+
+// Report formats for pull requests: GitHub Actions workflow commands that
+// annotate the changed files, and a Markdown comment that a CI job can post.
+// decider only writes the text; posting it is left to the CI job.
+
+// Hidden markers identifying decider's pull request comments, so that a bot
+// can find and update its previous comment instead of adding a new one.
+const (
+	CheckDiffCommentMarker = "<!-- decider:check-diff -->"
+	ExplainCommentMarker   = "<!-- decider:explain -->"
+)
+
+// githubEscapeData escapes the message of a workflow command.
+func githubEscapeData(s string) string {
+	s = strings.ReplaceAll(s, "%", "%25")
+	s = strings.ReplaceAll(s, "\r", "%0D")
+	return strings.ReplaceAll(s, "\n", "%0A")
+}
+
+// githubEscapeProperty escapes a property value of a workflow command.
+func githubEscapeProperty(s string) string {
+	s = githubEscapeData(s)
+	s = strings.ReplaceAll(s, ":", "%3A")
+	return strings.ReplaceAll(s, ",", "%2C")
+}
+
+// githubAnnotation formats a workflow command such as
+// "::warning file=src/a.go,line=3,title=ADR-0001::message". An empty file
+// annotates the workflow run instead of a file.
+func githubAnnotation(level, file string, line int, title, message string) string {
+	var props []string
+	if file != "" {
+		props = append(props, "file="+githubEscapeProperty(file))
+	}
+	if line > 0 {
+		props = append(props, fmt.Sprintf("line=%d", line))
+	}
+	if title != "" {
+		props = append(props, "title="+githubEscapeProperty(title))
+	}
+	return fmt.Sprintf("::%s %s::%s\n", level, strings.Join(props, ","), githubEscapeData(message))
+}
+
+// adrGuidance lists constraints and invariants for an annotation message.
+func adrGuidance(constraints, invariants []string) string {
+	var b strings.Builder
+	if len(constraints) > 0 {
+		b.WriteString("\nConstraints:")
+		for _, c := range constraints {
+			b.WriteString("\n- " + c)
+		}
+	}
+	if len(invariants) > 0 {
+		b.WriteString("\nInvariants:")
+		for _, i := range invariants {
+			b.WriteString("\n- " + i)
+		}
+	}
+	return b.String()
+}
+
+// markdownLink links an ADR file, relative to linkBase when set.
+func markdownLink(text, file, linkBase string) string {
+	target := file
+	if linkBase != "" {
+		target = strings.TrimSuffix(linkBase, "/") + "/" + strings.TrimPrefix(file, "./")
+	}
+	return fmt.Sprintf("[%s](%s)", text, strings.ReplaceAll(target, " ", "%20"))
+}
+
+// maxInlineFiles is the number of changed files listed before the list is
+// collapsed into a details block.
+const maxInlineFiles = 10
+
+// markdownChange formats a changed file, noting deletions and renames.
+func markdownChange(file, change, oldFile string) string {
+	switch git.ChangeType(change) {
+	case git.Deleted:
+		return fmt.Sprintf("`%s` (deleted)", file)
+	case git.Renamed:
+		return fmt.Sprintf("`%s` (renamed from `%s`)", file, oldFile)
+	}
+	return fmt.Sprintf("`%s`", file)
+}
+
+// markdownFileList writes the changed files an ADR applies to.
+func markdownFileList(b *strings.Builder, files []FileChange) {
+	if len(files) > maxInlineFiles {
+		fmt.Fprintf(b, "<details>\n<summary>%d changed files</summary>\n\n", len(files))
+	} else {
+		b.WriteString("Changed files:\n\n")
+	}
+	for _, f := range files {
+		fmt.Fprintf(b, "- %s\n", markdownChange(f.File, f.Change, f.OldFile))
+	}
+	if len(files) > maxInlineFiles {
+		b.WriteString("\n</details>\n")
+	}
+}
+
+// markdownChecklist writes constraints as checkboxes and invariants as a list.
+func markdownChecklist(b *strings.Builder, constraints, invariants []string) {
+	if len(constraints) > 0 {
+		b.WriteString("\n**Constraints**\n\n")
+		for _, c := range constraints {
+			fmt.Fprintf(b, "- [ ] %s\n", c)
+		}
+	}
+	if len(invariants) > 0 {
+		b.WriteString("\n**Invariants to preserve**\n\n")
+		for _, i := range invariants {
+			fmt.Fprintf(b, "- %s\n", i)
+		}
+	}
+}
+
+// checkDiffGitHub renders a check diff result as workflow commands: a
+// warning on each changed file an ADR applies to, and an error for each
+// constraint violation.
+func checkDiffGitHub(result *CheckDiffResult) string {
+	var b strings.Builder
+	for _, aa := range result.ApplicableADRs {
+		title := fmt.Sprintf("%s: %s", aa.ADRID, aa.Title)
+		guidance := adrGuidance(aa.Constraints, aa.Invariants)
+		matched := aa.MatchedPaths
+		if len(aa.Via) > 0 {
+			matched = append(append([]string{}, matched...), "supersedes "+strings.Join(aa.Via, ", "))
+		}
+		for _, c := range aa.Changes {
+			message := fmt.Sprintf("%s applies to this file%s (%s)", aa.ADRID, changeNote(c.Change, c.OldFile), strings.Join(matched, "; ")) + guidance
+			b.WriteString(githubAnnotation("warning", c.File, 0, title, message))
+		}
+	}
+	for _, v := range result.Violations {
+		b.WriteString(githubAnnotation("error", v.File, v.Line, fmt.Sprintf("%s %s", v.ADRID, v.Rule), v.Message))
+	}
+	for _, id := range result.Unacknowledged {
+		b.WriteString(githubAnnotation("error", "", 0, id+" not acknowledged",
+			fmt.Sprintf("No commit in the range has an %s trailer for %s", AckTrailer, id)))
+	}
+	return b.String()
+}
+
+// checkDiffMarkdown renders a check diff result as a pull request comment.
+func checkDiffMarkdown(result *CheckDiffResult, linkBase string) string {
+	var b strings.Builder
+	b.WriteString(CheckDiffCommentMarker + "\n")
+	b.WriteString("## Architecture decisions for this change\n\n")
+
+	if len(result.ApplicableADRs) == 0 {
+		fmt.Fprintf(&b, "No ADRs apply to the %d changed file(s).\n", len(result.ChangedFiles))
+	} else {
+		fmt.Fprintf(&b, "%d ADR(s) apply to the %d changed file(s).\n", len(result.ApplicableADRs), len(result.ChangedFiles))
+	}
+
+	for _, aa := range result.ApplicableADRs {
+		fmt.Fprintf(&b, "\n### %s\n\n", markdownLink(fmt.Sprintf("%s: %s", aa.ADRID, aa.Title), aa.File, linkBase))
+		if len(aa.Via) > 0 {
+			fmt.Fprintf(&b, "Supersedes %s\n\n", strings.Join(aa.Via, ", "))
+		}
+		if aa.Acknowledged {
+			fmt.Fprintf(&b, "Acknowledged in %s\n\n", strings.Join(shortHashes(aa.AcknowledgedBy), ", "))
+		}
+		markdownFileList(&b, aa.Changes)
+		markdownChecklist(&b, aa.Constraints, aa.Invariants)
+	}
+	markdownHistorical(&b, result.Historical, linkBase)
+
+	if len(result.Violations) > 0 {
+		fmt.Fprintf(&b, "\n### Constraint violations (%d)\n\n", len(result.Violations))
+		for _, v := range result.Violations {
+			location := v.File
+			if v.Line > 0 {
+				location = fmt.Sprintf("%s:%d", v.File, v.Line)
+			}
+			fmt.Fprintf(&b, "- :x: `%s` **%s** %s\n", location, v.ADRID, v.Message)
+		}
+	}
+
+	if len(result.Unacknowledged) > 0 {
+		fmt.Fprintf(&b, "\n### Unacknowledged ADRs (%d)\n\n", len(result.Unacknowledged))
+		b.WriteString("Name them in a commit trailer after reading them:\n\n")
+		fmt.Fprintf(&b, "```\n%s: %s\n```\n", AckTrailer, strings.Join(result.Unacknowledged, ", "))
+	}
+	return b.String()
+}
+
+// explainGitHub renders an explain result as workflow commands: a warning
+// on each changed file an ADR applies to, saying why it applies.
+func explainGitHub(result *ExplainResult) string {
+	var b strings.Builder
+	for _, exp := range result.Explanations {
+		title := fmt.Sprintf("%s: %s", exp.ADRID, exp.Title)
+		guidance := adrGuidance(exp.Constraints, exp.Invariants)
+		for _, m := range exp.Matches {
+			message := fmt.Sprintf("%s (%s) applies: %s", exp.ADRID, exp.Status, m.Reason) + guidance
+			b.WriteString(githubAnnotation("warning", m.File, 0, title, message))
+		}
+	}
+	return b.String()
+}
+
+// explainMarkdown renders an explain result as a pull request comment.
+func explainMarkdown(result *ExplainResult, linkBase string) string {
+	var b strings.Builder
+	b.WriteString(ExplainCommentMarker + "\n")
+	b.WriteString("## Why these ADRs apply\n\n")
+
+	if len(result.Explanations) == 0 {
+		fmt.Fprintf(&b, "No ADRs apply to the %d changed file(s).\n", len(result.ChangedFiles))
+	} else {
+		fmt.Fprintf(&b, "%d ADR(s) apply to the %d changed file(s).\n", len(result.Explanations), len(result.ChangedFiles))
+	}
+
+	for _, exp := range result.Explanations {
+		fmt.Fprintf(&b, "\n### %s\n\n", markdownLink(fmt.Sprintf("%s: %s", exp.ADRID, exp.Title), exp.File, linkBase))
+		fmt.Fprintf(&b, "Status: %s\n\n", exp.Status)
+		if len(exp.Via) > 0 {
+			fmt.Fprintf(&b, "Supersedes %s\n\n", strings.Join(exp.Via, ", "))
+		}
+		b.WriteString("| File | Pattern | Reason |\n")
+		b.WriteString("|------|---------|--------|\n")
+		for _, m := range exp.Matches {
+			fmt.Fprintf(&b, "| %s | `%s` | %s |\n", markdownChange(m.File, m.Change, m.OldFile), m.Pattern, strings.ReplaceAll(m.Reason, "|", "\\|"))
+		}
+		markdownChecklist(&b, exp.Constraints, exp.Invariants)
+	}
+	markdownHistorical(&b, result.Historical, linkBase)
+	return b.String()
+}
+
+// markdownHistorical lists the ADRs that cover changed files but do not
+// apply, without their constraints.
+func markdownHistorical(b *strings.Builder, historical []HistoricalADR, linkBase string) {
+	if len(historical) == 0 {
+		return
+	}
+	fmt.Fprintf(b, "\n### Historical context (%d)\n\n", len(historical))
+	b.WriteString("These ADRs also cover the changed files but do not apply:\n\n")
+	for _, h := range historical {
+		fmt.Fprintf(b, "- %s (%s%s, %d file(s))\n",
+			markdownLink(fmt.Sprintf("%s: %s", h.ADRID, h.Title), h.File, linkBase), h.Status, supersessionNote(h.SupersededBy), len(h.MatchedFiles))
+	}
+}
@@ -0,0 +1,171 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// reportFixture writes an ADR scoped to src/ with a forbid_import rule, and
+// a changed file that violates it.
+func reportFixture(t *testing.T) (root, adrDir string) {
+	t.Helper()
+	root = t.TempDir()
+	adrDir = filepath.Join(root, "docs", "adr")
+	if err := os.MkdirAll(filepath.Join(root, "src"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(adrDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	writeTestADR(t, adrDir, "0001-repositories.md", `
+adr_id: ADR-0001
+title: Repositories, not SQL
+status: adopted
+date: 2026-01-16
+scope:
+  paths: ["src/**"]
+constraints:
+  - "Use the repository pattern"
+  - forbid_import: database/sql
+    message: Use repositories
+invariants:
+  - "100% of queries go through repositories"
+`)
+	if err := os.WriteFile(filepath.Join(root, "src", "user.go"), []byte("package src\n\nimport \"database/sql\"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return root, adrDir
+}
+
+func TestCheckDiffGitHub(t *testing.T) {
+	root, adrDir := reportFixture(t)
+
+	var buf bytes.Buffer
+	if _, err := RunCheckDiff(&CheckDiffConfig{
+		Dir:    adrDir,
+		Files:  []string{"src/user.go", "README.md"},
+		Root:   root,
+		Format: FormatGitHub,
+		Output: &Output{Format: FormatGitHub, Writer: &buf},
+	}); err != nil {
+		t.Fatalf("RunCheckDiff() error = %v", err)
+	}
+
+	want := "::warning file=src/user.go,title=ADR-0001%3A Repositories%2C not SQL::" +
+		"ADR-0001 applies to this file (src/**)%0AConstraints:%0A- Use the repository pattern%0A- Use repositories" +
+		"%0AInvariants:%0A- 100%25 of queries go through repositories\n" +
+		"::error file=src/user.go,line=3,title=ADR-0001 forbid_import::imports forbidden package database/sql (Use repositories)\n"
+	if buf.String() != want {
+		t.Errorf("output =\n%s\nwant\n%s", buf.String(), want)
+	}
+}
+
+func TestCheckDiffMarkdown(t *testing.T) {
+	root, adrDir := reportFixture(t)
+
+	var buf bytes.Buffer
+	if _, err := RunCheckDiff(&CheckDiffConfig{
+		Dir:      adrDir,
+		Files:    []string{"src/user.go", "README.md"},
+		Root:     root,
+		Format:   FormatMarkdown,
+		Output:   &Output{Format: FormatMarkdown, Writer: &buf},
+		LinkBase: "https://example.com/blob/main/",
+	}); err != nil {
+		t.Fatalf("RunCheckDiff() error = %v", err)
+	}
+
+	want := CheckDiffCommentMarker + `
+## Architecture decisions for this change
+
+1 ADR(s) apply to the 2 changed file(s).
+
+### [ADR-0001: Repositories, not SQL](https://example.com/blob/main/docs/adr/0001-repositories.md)
+
+Changed files:
+
+- ` + "`src/user.go`" + `
+
+**Constraints**
+
+- [ ] Use the repository pattern
+- [ ] Use repositories
+
+**Invariants to preserve**
+
+- 100% of queries go through repositories
+
+### Constraint violations (1)
+
+- :x: ` + "`src/user.go:3`" + ` **ADR-0001** imports forbidden package database/sql (Use repositories)
+`
+	if buf.String() != want {
+		t.Errorf("output =\n%s\nwant\n%s", buf.String(), want)
+	}
+}
+
+func TestExplainReports(t *testing.T) {
+	_, adrDir := reportFixture(t)
+
+	tests := []struct {
+		format OutputFormat
+		want   []string
+	}{
+		{FormatGitHub, []string{
+			"::warning file=src/user.go,title=ADR-0001%3A Repositories%2C not SQL::ADR-0001 (adopted) applies: File is anywhere under 'src/'%0AConstraints:",
+		}},
+		{FormatMarkdown, []string{
+			ExplainCommentMarker + "\n## Why these ADRs apply\n",
+			"| `src/user.go` | `src/**` | File is anywhere under 'src/' |",
+			"- [ ] Use repositories\n",
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.format), func(t *testing.T) {
+			var buf bytes.Buffer
+			if _, err := RunExplain(&ExplainConfig{
+				Dir:    adrDir,
+				Files:  []string{"src/user.go"},
+				Format: tt.format,
+				Output: &Output{Format: tt.format, Writer: &buf},
+			}); err != nil {
+				t.Fatalf("RunExplain() error = %v", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(buf.String(), want) {
+					t.Errorf("output lacks %q:\n%s", want, buf.String())
+				}
+			}
+		})
+	}
+}
+
+func TestMarkdownNoApplicableADRs(t *testing.T) {
+	got := checkDiffMarkdown(&CheckDiffResult{ChangedFiles: []string{"README.md"}}, "")
+	want := CheckDiffCommentMarker + "\n## Architecture decisions for this change\n\nNo ADRs apply to the 1 changed file(s).\n"
+	if got != want {
+		t.Errorf("checkDiffMarkdown() = %q, want %q", got, want)
+	}
+}
+
+func TestMarkdownChanges(t *testing.T) {
+	var b strings.Builder
+	markdownFileList(&b, []FileChange{
+		{File: "src/db/user.go", Change: "modified"},
+		{File: "src/db/old.go", Change: "deleted"},
+		{File: "legacy/x.go", OldFile: "src/db/x.go", Change: "renamed"},
+		{File: "src/db/y.go"},
+	})
+	want := "Changed files:\n\n" +
+		"- `src/db/user.go`\n" +
+		"- `src/db/old.go` (deleted)\n" +
+		"- `legacy/x.go` (renamed from `src/db/x.go`)\n" +
+		"- `src/db/y.go`\n"
+	if b.String() != want {
+		t.Errorf("markdownFileList() =\n%s\nwant\n%s", b.String(), want)
+	}
+}
@@ -0,0 +1,322 @@
+package cli
+
+import (
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/sventorben/decider/internal/git"
+)
+
+// SARIF 2.1.0 output for code-scanning dashboards. Only the subset of the
+// format that decider fills in is modelled; field order and slice order are
+// fixed so that the same input always produces the same bytes. File URIs are
+// relative to the repository root, which the run declares as %SRCROOT%.
+
+const (
+	sarifVersion = "2.1.0"
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	sarifToolURI = "https://github.com/sventorben/decider"
+	sarifSrcRoot = "%SRCROOT%"
+)
+
+type sarifLog struct {
+	Version string     `json:"version"`
+	Schema  string     `json:"$schema"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool               sarifTool                        `json:"tool"`
+	OriginalURIBaseIDs map[string]sarifArtifactLocation `json:"originalUriBaseIds"`
+	Results            []sarifResult                    `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID                   string          `json:"id"`
+	Name                 string          `json:"name,omitempty"`
+	ShortDescription     sarifMessage    `json:"shortDescription"`
+	FullDescription      *sarifMessage   `json:"fullDescription,omitempty"`
+	DefaultConfiguration sarifRuleConfig `json:"defaultConfiguration"`
+}
+
+type sarifRuleConfig struct {
+	Level string `json:"level"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	RuleIndex int             `json:"ruleIndex"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations,omitempty"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI       string `json:"uri"`
+	URIBaseID string `json:"uriBaseId,omitempty"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn,omitempty"`
+}
+
+// sarifBuilder collects rules and results for a single run. File locations
+// are made relative to root, an absolute path.
+type sarifBuilder struct {
+	root    string
+	rules   []sarifRule
+	index   map[string]int
+	results []sarifResult
+}
+
+func newSARIFBuilder(root string, rules []sarifRule) *sarifBuilder {
+	b := &sarifBuilder{root: root, index: make(map[string]int)}
+	for _, r := range rules {
+		b.addRule(r)
+	}
+	return b
+}
+
+// addRule registers a rule unless one with the same ID exists.
+func (b *sarifBuilder) addRule(r sarifRule) {
+	if _, ok := b.index[r.ID]; ok {
+		return
+	}
+	b.index[r.ID] = len(b.rules)
+	b.rules = append(b.rules, r)
+}
+
+// addResult records a result for a registered rule.
+func (b *sarifBuilder) addResult(ruleID, level, message string, locations ...sarifLocation) {
+	b.results = append(b.results, sarifResult{
+		RuleID:    ruleID,
+		RuleIndex: b.index[ruleID],
+		Level:     level,
+		Message:   sarifMessage{Text: message},
+		Locations: locations,
+	})
+}
+
+func (b *sarifBuilder) log() *sarifLog {
+	results := b.results
+	if results == nil {
+		results = []sarifResult{}
+	}
+	// Base URIs end with a slash so that relative URIs resolve below them
+	rootURI := sarifFileURI(b.root)
+	if !strings.HasSuffix(rootURI, "/") {
+		rootURI += "/"
+	}
+	return &sarifLog{
+		Version: sarifVersion,
+		Schema:  sarifSchema,
+		Runs: []sarifRun{{
+			Tool: sarifTool{Driver: sarifDriver{
+				Name:           "decider",
+				InformationURI: sarifToolURI,
+				Rules:          b.rules,
+			}},
+			OriginalURIBaseIDs: map[string]sarifArtifactLocation{
+				sarifSrcRoot: {URI: rootURI},
+			},
+			Results: results,
+		}},
+	}
+}
+
+// location builds a location for a file, with a region when the line is
+// known. Relative paths are resolved against the working directory.
+func (b *sarifBuilder) location(path string, line, column int) sarifLocation {
+	loc := sarifLocation{PhysicalLocation: sarifPhysicalLocation{
+		ArtifactLocation: b.artifact(path),
+	}}
+	if line > 0 {
+		loc.PhysicalLocation.Region = &sarifRegion{StartLine: line, StartColumn: column}
+	}
+	return loc
+}
+
+// artifact returns a URI relative to %SRCROOT% for a file below the root,
+// and an absolute file URI otherwise.
+func (b *sarifBuilder) artifact(path string) sarifArtifactLocation {
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	if rel, err := filepath.Rel(b.root, path); err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return sarifArtifactLocation{
+			URI:       (&url.URL{Path: filepath.ToSlash(rel)}).String(),
+			URIBaseID: sarifSrcRoot,
+		}
+	}
+	return sarifArtifactLocation{URI: sarifFileURI(path)}
+}
+
+// sarifFileURI returns the file URI of an absolute path.
+func sarifFileURI(path string) string {
+	p := filepath.ToSlash(path)
+	if !strings.HasPrefix(p, "/") {
+		p = "/" + p
+	}
+	return (&url.URL{Scheme: "file", Path: p}).String()
+}
+
+// sarifRoot returns the absolute directory SARIF locations are relative to:
+// root when set, else the repository root, or the working directory outside
+// a repository.
+func sarifRoot(root string) string {
+	if root == "" {
+		var err error
+		if root, err = git.Root(); err != nil {
+			root = "."
+		}
+	}
+	if abs, err := filepath.Abs(root); err == nil {
+		return abs
+	}
+	return root
+}
+
+func sarifLevel(severity string) string {
+	if severity == "warning" {
+		return "warning"
+	}
+	return "error"
+}
+
+// checkADRRules describes every check of 'decider check adr', keyed by the
+// code reported in CheckADRError.
+var checkADRRules = map[string]string{
+	"validation_error":                    "Frontmatter field or body section is missing or malformed",
+	"tag_not_allowed":                     "Tag is not in the project's allowed tags",
+	"invalid_constraint":                  "Structured constraint is malformed",
+	"invalid_scope_path":                  "Scope path is not a well-formed glob pattern",
+	"superseded_without_successor":        "Superseded ADR has no existing successor",
+	"successor_without_superseded_status": "ADR lists superseded_by but its status is not superseded",
+	"status_date_before_date":             "Status changed before the decision date",
+	"dangling_reference":                  "Reference to an ADR that does not exist",
+	"self_reference":                      "ADR references itself",
+	"asymmetric_supersession":             "Supersession link is missing its back-link",
+	"supersession_cycle":                  "Supersession links form a cycle",
+	"duplicate_adr_id":                    "ADR ID is used by more than one file",
+	"missing_adopted_because":             "Decision lacks an 'Adopted because:' rationale",
+	"missing_adopted_despite":             "Decision lacks an 'Adopted despite:' rationale",
+	"missing_rejected_because":            "Rejected alternative lacks a 'Rejected because:' rationale",
+	"missing_rejected_despite":            "Rejected alternative lacks a 'Rejected despite:' rationale",
+}
+
+// checkADRSARIF converts a check adr result to SARIF with locations relative
+// to root. File names are resolved against the ADR directory.
+func checkADRSARIF(root, dir string, result *CheckADRResult) *sarifLog {
+	codes := make([]string, 0, len(checkADRRules))
+	for code := range checkADRRules {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+
+	level := func(code string) string {
+		if strings.HasPrefix(code, "missing_") {
+			return "warning"
+		}
+		return "error"
+	}
+	var rules []sarifRule
+	for _, code := range codes {
+		rules = append(rules, sarifRule{
+			ID:                   code,
+			ShortDescription:     sarifMessage{Text: checkADRRules[code]},
+			DefaultConfiguration: sarifRuleConfig{Level: level(code)},
+		})
+	}
+	b := newSARIFBuilder(root, rules)
+
+	for _, fr := range result.Results {
+		for _, e := range append(append([]CheckADRError{}, fr.Errors...), fr.Warnings...) {
+			b.addRule(sarifRule{
+				ID:                   e.Code,
+				ShortDescription:     sarifMessage{Text: e.Code},
+				DefaultConfiguration: sarifRuleConfig{Level: sarifLevel(e.Severity)},
+			})
+			b.addResult(e.Code, sarifLevel(e.Severity), fmt.Sprintf("%s: %s", e.Field, e.Message),
+				b.location(filepath.Join(dir, e.File), e.Line, e.Column))
+		}
+	}
+	return b.log()
+}
+
+// checkDiffSARIF converts a check diff result to SARIF. Each applicable ADR
+// is a rule whose constraints are reported as notes on the matched files;
+// constraint violations are errors under a rule per ADR and rule kind, and
+// unacknowledged ADRs are errors located at the ADR file. Locations are
+// relative to root; the paths in the result are resolved against dir.
+func checkDiffSARIF(root, dir string, result *CheckDiffResult) *sarifLog {
+	b := newSARIFBuilder(root, nil)
+
+	for _, aa := range result.ApplicableADRs {
+		b.addRule(sarifRule{
+			ID:                   aa.ADRID,
+			Name:                 aa.Title,
+			ShortDescription:     sarifMessage{Text: fmt.Sprintf("%s: %s", aa.ADRID, aa.Title)},
+			FullDescription:      &sarifMessage{Text: fmt.Sprintf("Applies to %s", strings.Join(aa.MatchedPaths, ", "))},
+			DefaultConfiguration: sarifRuleConfig{Level: "note"},
+		})
+
+		var locations []sarifLocation
+		for _, f := range aa.MatchedFiles {
+			locations = append(locations, b.location(filepath.Join(dir, f), 0, 0))
+		}
+		for _, c := range aa.Constraints {
+			b.addResult(aa.ADRID, "note", c, locations...)
+		}
+	}
+
+	for _, v := range result.Violations {
+		id := v.ADRID + "/" + v.Rule
+		b.addRule(sarifRule{
+			ID:                   id,
+			ShortDescription:     sarifMessage{Text: fmt.Sprintf("%s constraint of %s", v.Rule, v.ADRID)},
+			DefaultConfiguration: sarifRuleConfig{Level: "error"},
+		})
+		b.addResult(id, "error", v.Message, b.location(filepath.Join(dir, v.File), v.Line, 0))
+	}
+
+	for _, aa := range result.ApplicableADRs {
+		if !contains(result.Unacknowledged, aa.ADRID) {
+			continue
+		}
+		b.addRule(sarifRule{
+			ID:                   "unacknowledged_adr",
+			ShortDescription:     sarifMessage{Text: "Applicable ADR is not named in an " + AckTrailer + " commit trailer"},
+			DefaultConfiguration: sarifRuleConfig{Level: "error"},
+		})
+		b.addResult("unacknowledged_adr", "error", fmt.Sprintf("No commit in the range acknowledges %s", aa.ADRID),
+			b.location(filepath.Join(dir, aa.File), 0, 0))
+	}
+	return b.log()
+}
@@ -0,0 +1,245 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCheckDiffSARIFGolden(t *testing.T) {
+	root := t.TempDir()
+	adrDir := filepath.Join(root, "docs", "adr")
+	if err := os.MkdirAll(filepath.Join(root, "src"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(adrDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	writeTestADR(t, adrDir, "0001-repositories.md", `
+adr_id: ADR-0001
+title: Repositories
+status: adopted
+date: 2026-01-16
+scope:
+  paths: ["src/**"]
+constraints:
+  - "Use the repository pattern"
+  - forbid_import: database/sql
+    message: Use repositories
+`)
+	if err := os.WriteFile(filepath.Join(root, "src", "user.go"), []byte("package src\n\nimport \"database/sql\"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if _, err := RunCheckDiff(&CheckDiffConfig{
+		Dir:    adrDir,
+		Files:  []string{"src/user.go", "README.md"},
+		Root:   root,
+		Format: FormatSARIF,
+		Output: &Output{Format: FormatSARIF, Writer: &buf},
+	}); err != nil {
+		t.Fatalf("RunCheckDiff() error = %v", err)
+	}
+	got := strings.ReplaceAll(buf.String(), sarifFileURI(root)+"/", "file:///repo/")
+	if got != checkDiffSARIFGolden {
+		t.Errorf("SARIF output differs from golden:\n%s", got)
+	}
+}
+
+const checkDiffSARIFGolden = `{
+  "version": "2.1.0",
+  "$schema": "https://json.schemastore.org/sarif-2.1.0.json",
+  "runs": [
+    {
+      "tool": {
+        "driver": {
+          "name": "decider",
+          "informationUri": "https://github.com/sventorben/decider",
+          "rules": [
+            {
+              "id": "ADR-0001",
+              "name": "Repositories",
+              "shortDescription": {
+                "text": "ADR-0001: Repositories"
+              },
+              "fullDescription": {
+                "text": "Applies to src/**"
+              },
+              "defaultConfiguration": {
+                "level": "note"
+              }
+            },
+            {
+              "id": "ADR-0001/forbid_import",
+              "shortDescription": {
+                "text": "forbid_import constraint of ADR-0001"
+              },
+              "defaultConfiguration": {
+                "level": "error"
+              }
+            }
+          ]
+        }
+      },
+      "originalUriBaseIds": {
+        "%SRCROOT%": {
+          "uri": "file:///repo/"
+        }
+      },
+      "results": [
+        {
+          "ruleId": "ADR-0001",
+          "ruleIndex": 0,
+          "level": "note",
+          "message": {
+            "text": "Use the repository pattern"
+          },
+          "locations": [
+            {
+              "physicalLocation": {
+                "artifactLocation": {
+                  "uri": "src/user.go",
+                  "uriBaseId": "%SRCROOT%"
+                }
+              }
+            }
+          ]
+        },
+        {
+          "ruleId": "ADR-0001",
+          "ruleIndex": 0,
+          "level": "note",
+          "message": {
+            "text": "Use repositories"
+          },
+          "locations": [
+            {
+              "physicalLocation": {
+                "artifactLocation": {
+                  "uri": "src/user.go",
+                  "uriBaseId": "%SRCROOT%"
+                }
+              }
+            }
+          ]
+        },
+        {
+          "ruleId": "ADR-0001/forbid_import",
+          "ruleIndex": 1,
+          "level": "error",
+          "message": {
+            "text": "imports forbidden package database/sql (Use repositories)"
+          },
+          "locations": [
+            {
+              "physicalLocation": {
+                "artifactLocation": {
+                  "uri": "src/user.go",
+                  "uriBaseId": "%SRCROOT%"
+                },
+                "region": {
+                  "startLine": 3
+                }
+              }
+            }
+          ]
+        }
+      ]
+    }
+  ]
+}
+`
+
+func TestCheckADRSARIF(t *testing.T) {
+	root := t.TempDir()
+	dir := filepath.Join(root, "docs", "adr")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	writeTestADR(t, dir, "0001-first.md", `
+adr_id: ADR-0001
+title: First
+status: adopted
+date: 2026-01-16
+related_adrs:
+  - ADR-0099
+`)
+
+	run := func() string {
+		var buf bytes.Buffer
+		if _, err := RunCheckADR(&CheckADRConfig{
+			Dir:    dir,
+			Root:   root,
+			Format: FormatSARIF,
+			Output: &Output{Format: FormatSARIF, Writer: &buf},
+		}); err != nil {
+			t.Fatalf("RunCheckADR() error = %v", err)
+		}
+		return buf.String()
+	}
+	out := run()
+	if again := run(); again != out {
+		t.Error("SARIF output is not deterministic")
+	}
+
+	var log sarifLog
+	if err := json.Unmarshal([]byte(out), &log); err != nil {
+		t.Fatalf("decoding SARIF: %v", err)
+	}
+	if log.Version != "2.1.0" || len(log.Runs) != 1 {
+		t.Fatalf("log = %+v", log)
+	}
+	run0 := log.Runs[0]
+	if base := run0.OriginalURIBaseIDs["%SRCROOT%"].URI; base != sarifFileURI(root)+"/" {
+		t.Errorf("%%SRCROOT%% = %s", base)
+	}
+	if len(run0.Tool.Driver.Rules) != len(checkADRRules) {
+		t.Errorf("rules = %d, want one per check (%d)", len(run0.Tool.Driver.Rules), len(checkADRRules))
+	}
+
+	var found bool
+	for _, r := range run0.Results {
+		if run0.Tool.Driver.Rules[r.RuleIndex].ID != r.RuleID {
+			t.Errorf("result %s has ruleIndex %d of rule %s", r.RuleID, r.RuleIndex, run0.Tool.Driver.Rules[r.RuleIndex].ID)
+		}
+		if r.RuleID != "dangling_reference" {
+			continue
+		}
+		found = true
+		loc := r.Locations[0].PhysicalLocation
+		if loc.ArtifactLocation.URI != "docs/adr/0001-first.md" || loc.ArtifactLocation.URIBaseID != "%SRCROOT%" {
+			t.Errorf("artifactLocation = %+v", loc.ArtifactLocation)
+		}
+		if r.Level != "error" || loc.Region == nil || loc.Region.StartLine != 7 || loc.Region.StartColumn != 5 {
+			t.Errorf("dangling_reference result = %+v", r)
+		}
+	}
+	if !found {
+		t.Errorf("no dangling_reference result in %s", out)
+	}
+}
+
+func TestSARIFArtifact(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := newSARIFBuilder(filepath.Dir(wd), nil)
+	tests := []struct {
+		path string
+		want sarifArtifactLocation
+	}{
+		{"testdata/0001-x.md", sarifArtifactLocation{URI: filepath.Base(wd) + "/testdata/0001-x.md", URIBaseID: "%SRCROOT%"}},
+		{filepath.Join(wd, "..", "docs//adr", "0001 x.md"), sarifArtifactLocation{URI: "docs/adr/0001%20x.md", URIBaseID: "%SRCROOT%"}},
+		{"/elsewhere/0001 x.md", sarifArtifactLocation{URI: "file:///elsewhere/0001%20x.md"}},
+	}
+	for _, tt := range tests {
+		if got := b.artifact(tt.path); got != tt.want {
+			t.Errorf("artifact(%q) = %+v, want %+v", tt.path, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,210 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/sventorben/decider/internal/adr"
+	"github.com/sventorben/decider/internal/git"
+	"github.com/sventorben/decider/internal/glob"
+)
+
+// CheckScopeConfig holds configuration for the check scope command.
+type CheckScopeConfig struct {
+	Dir    string
+	Format OutputFormat
+	Output *Output
+
+	// Files, when non-nil, are the repository files instead of the ones git
+	// lists.
+	Files []string
+
+	// Sources selects the files that an ADR should cover, as scope patterns
+	// that may include '!' exclusions. When empty, every file outside the
+	// ADR directory is a source file.
+	Sources []string
+
+	// MaxOrphaned and MaxUncovered fail the check when there are more
+	// orphaned patterns or uncovered files; negative values disable them.
+	MaxOrphaned  int
+	MaxUncovered int
+}
+
+// CheckScopeResult holds the result of the check scope command.
+type CheckScopeResult struct {
+	Passed    bool              `json:"passed"`
+	ADRs      int               `json:"adrs"`     // Adopted ADRs with scope paths
+	Patterns  int               `json:"patterns"` // Scope patterns checked
+	Files     int               `json:"files"`    // Files in the working tree
+	Sources   int               `json:"sources"`  // Files an ADR should cover
+	Covered   int               `json:"covered"`  // Source files covered by an adopted ADR
+	Orphaned  []OrphanedPattern `json:"orphaned"`
+	Uncovered []string          `json:"uncovered"`
+
+	// UnusedExclusions are '!' scope patterns that exclude no file yet.
+	// They are warnings: an exclusion may guard files that do not exist.
+	UnusedExclusions []OrphanedPattern `json:"unused_exclusions,omitempty"`
+
+	// Failures describes each exceeded threshold.
+	Failures []string `json:"failures,omitempty"`
+}
+
+// OrphanedPattern is a scope pattern of an adopted ADR that matches no file.
+type OrphanedPattern struct {
+	ADRID   string `json:"adr_id"`
+	Title   string `json:"title"`
+	File    string `json:"file"`
+	Pattern string `json:"pattern"`
+}
+
+// RunCheckScope reports the scope patterns of adopted ADRs that match no
+// file in the working tree, and the source files no adopted ADR covers.
+func RunCheckScope(cfg *CheckScopeConfig) (*CheckScopeResult, error) {
+	adrs, err := adr.LoadAllADRs(cfg.Dir)
+	if err != nil {
+		return nil, fmt.Errorf("loading ADRs: %w", err)
+	}
+
+	root, files, err := repositoryFiles(cfg.Files)
+	if err != nil {
+		return nil, err
+	}
+
+	sources := cfg.Sources
+	if len(sources) == 0 {
+		sources = []string{"**", "!" + relativePath(root, cfg.Dir) + "/**"}
+	}
+
+	result := &CheckScopeResult{
+		Files:     len(files),
+		Orphaned:  []OrphanedPattern{},
+		Uncovered: []string{},
+	}
+
+	var scoped []*adr.ADR
+	for _, a := range adrs {
+		if a.Frontmatter.Status != adr.StatusAdopted || len(a.Frontmatter.Scope.Paths) == 0 {
+			continue
+		}
+		scoped = append(scoped, a)
+		for _, pattern := range a.Frontmatter.Scope.Paths {
+			result.Patterns++
+			exclusion, isExclusion := strings.CutPrefix(pattern, "!")
+			if matchesAnyFile(exclusion, files) {
+				continue
+			}
+			unused := OrphanedPattern{
+				ADRID:   a.Frontmatter.ADRID,
+				Title:   a.Frontmatter.Title,
+				File:    relativePath(root, a.FilePath),
+				Pattern: pattern,
+			}
+			if isExclusion {
+				result.UnusedExclusions = append(result.UnusedExclusions, unused)
+			} else {
+				result.Orphaned = append(result.Orphaned, unused)
+			}
+		}
+	}
+	result.ADRs = len(scoped)
+
+	for _, f := range glob.FilterPaths(sources, files) {
+		result.Sources++
+		if coveredBy(scoped, f) {
+			result.Covered++
+		} else {
+			result.Uncovered = append(result.Uncovered, f)
+		}
+	}
+
+	if cfg.MaxOrphaned >= 0 && len(result.Orphaned) > cfg.MaxOrphaned {
+		result.Failures = append(result.Failures,
+			fmt.Sprintf("%d orphaned scope pattern(s) exceed the maximum of %d", len(result.Orphaned), cfg.MaxOrphaned))
+	}
+	if cfg.MaxUncovered >= 0 && len(result.Uncovered) > cfg.MaxUncovered {
+		result.Failures = append(result.Failures,
+			fmt.Sprintf("%d uncovered file(s) exceed the maximum of %d", len(result.Uncovered), cfg.MaxUncovered))
+	}
+	result.Passed = len(result.Failures) == 0
+
+	// Output
+	if cfg.Format == FormatTOON || cfg.Format == FormatJSON {
+		_ = cfg.Output.PrintStructured(result)
+		return result, nil
+	}
+
+	cfg.Output.Println("Checked %d scope pattern(s) of %d adopted ADR(s) against %d file(s)", result.Patterns, result.ADRs, result.Files)
+	cfg.Output.Println("")
+	if len(result.Orphaned) == 0 {
+		cfg.Output.Success("Every scope pattern matches at least one file")
+	} else {
+		cfg.Output.Println("Orphaned scope patterns (%d), matching no file:", len(result.Orphaned))
+		for _, o := range result.Orphaned {
+			cfg.Output.Println("  %s %-24s %s", o.ADRID, o.Pattern, o.File)
+		}
+	}
+	if len(result.UnusedExclusions) > 0 {
+		cfg.Output.Warn("%d scope exclusion(s) exclude no file", len(result.UnusedExclusions))
+		for _, u := range result.UnusedExclusions {
+			cfg.Output.Println("  %s %-24s %s", u.ADRID, u.Pattern, u.File)
+		}
+	}
+	cfg.Output.Println("")
+	cfg.Output.Println("Coverage: %d of %d source file(s) covered by an adopted ADR (%s)", result.Covered, result.Sources, percent(result.Covered, result.Sources))
+	if len(result.Uncovered) > 0 {
+		cfg.Output.Println("Uncovered files (%d):", len(result.Uncovered))
+		for _, f := range result.Uncovered {
+			cfg.Output.Println("  %s", f)
+		}
+	}
+	for _, f := range result.Failures {
+		cfg.Output.Error("%s", f)
+	}
+
+	return result, nil
+}
+
+// repositoryFiles returns the repository root and the files git lists in
+// the working tree, or "." and files when files is non-nil.
+func repositoryFiles(files []string) (string, []string, error) {
+	if files != nil {
+		return ".", files, nil
+	}
+	root, err := git.Root()
+	if err != nil {
+		return "", nil, err
+	}
+	if files, err = git.Files(); err != nil {
+		return "", nil, fmt.Errorf("listing files: %w", err)
+	}
+	return root, files, nil
+}
+
+// matchesAnyFile reports whether pattern matches at least one of files.
+func matchesAnyFile(pattern string, files []string) bool {
+	for _, f := range files {
+		if glob.Match(pattern, f) {
+			return true
+		}
+	}
+	return false
+}
+
+// coveredBy reports whether the scope of one of adrs covers file.
+func coveredBy(adrs []*adr.ADR, file string) bool {
+	for _, a := range adrs {
+		if glob.MatchAny(a.Frontmatter.Scope.Paths, file) {
+			return true
+		}
+	}
+	return false
+}
+
+// percent formats part of total as a whole percentage, rounded down so that
+// 100% means complete coverage.
+func percent(part, total int) string {
+	if total == 0 {
+		return "100%"
+	}
+	return fmt.Sprintf("%d%%", part*100/total)
+}
@@ -0,0 +1,124 @@
+package cli
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRunCheckScope(t *testing.T) {
+	dir := t.TempDir()
+	writeTestADR(t, dir, "0001-api-layer.md", `
+adr_id: ADR-0001
+title: API Layer
+status: adopted
+date: 2026-01-16
+scope:
+  paths:
+    - "src/api/**"
+    - "src/moved/**"
+    - "!src/api/gen/**"
+`)
+	writeTestADR(t, dir, "0002-old-layout.md", `
+adr_id: ADR-0002
+title: Old Layout
+status: superseded
+date: 2026-01-16
+superseded_by: [ADR-0001]
+scope:
+  paths:
+    - "lib/**"
+`)
+	files := []string{"README.md", "src/api/handler.go", "src/api/gen/types.go", "src/web/index.ts"}
+
+	tests := []struct {
+		name      string
+		cfg       CheckScopeConfig
+		passed    bool
+		uncovered []string
+		failures  []string
+	}{
+		{
+			name:      "no thresholds",
+			cfg:       CheckScopeConfig{MaxOrphaned: -1, MaxUncovered: -1},
+			passed:    true,
+			uncovered: []string{"README.md", "src/api/gen/types.go", "src/web/index.ts"},
+		},
+		{
+			name:      "sources",
+			cfg:       CheckScopeConfig{Sources: []string{"src/**", "!**/gen/**"}, MaxOrphaned: -1, MaxUncovered: 1},
+			passed:    true,
+			uncovered: []string{"src/web/index.ts"},
+		},
+		{
+			name:      "thresholds exceeded",
+			cfg:       CheckScopeConfig{MaxOrphaned: 0, MaxUncovered: 2},
+			uncovered: []string{"README.md", "src/api/gen/types.go", "src/web/index.ts"},
+			failures:  []string{"1 orphaned scope pattern(s) exceed the maximum of 0", "3 uncovered file(s) exceed the maximum of 2"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := tt.cfg
+			cfg.Dir = dir
+			cfg.Files = files
+			cfg.Format = FormatJSON
+			cfg.Output = testOutput()
+
+			result, err := RunCheckScope(&cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result.Passed != tt.passed {
+				t.Errorf("Passed = %v, want %v", result.Passed, tt.passed)
+			}
+			// Only the adopted ADR's unused pattern is orphaned
+			if len(result.Orphaned) != 1 || result.Orphaned[0].ADRID != "ADR-0001" || result.Orphaned[0].Pattern != "src/moved/**" {
+				t.Errorf("Orphaned = %+v", result.Orphaned)
+			}
+			if !reflect.DeepEqual(result.Uncovered, tt.uncovered) {
+				t.Errorf("Uncovered = %v, want %v", result.Uncovered, tt.uncovered)
+			}
+			if !reflect.DeepEqual(result.Failures, tt.failures) {
+				t.Errorf("Failures = %q, want %q", result.Failures, tt.failures)
+			}
+		})
+	}
+}
+
+func TestRunCheckScopeText(t *testing.T) {
+	dir := t.TempDir()
+	writeTestADR(t, dir, "0001-api-layer.md", `
+adr_id: ADR-0001
+title: API Layer
+status: adopted
+date: 2026-01-16
+scope:
+  paths: ["src/**", "!src/gone/**"]
+`)
+
+	out := testOutput()
+	result, err := RunCheckScope(&CheckScopeConfig{
+		Dir:          dir,
+		Files:        []string{"src/a.go", "src/b.go", "main.go"},
+		MaxOrphaned:  0,
+		MaxUncovered: -1,
+		Format:       FormatText,
+		Output:       out,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// An exclusion that excludes nothing yet is a warning, not orphaned
+	if !result.Passed || len(result.Orphaned) != 0 || len(result.UnusedExclusions) != 1 {
+		t.Errorf("result = %+v, want passed with one unused exclusion", result)
+	}
+
+	text := out.Writer.(interface{ String() string }).String()
+	for _, want := range []string{"ADR-0001 !src/gone/**", "Coverage: 2 of 3 source file(s) covered by an adopted ADR (66%)", "  main.go"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("output lacks %q:\n%s", want, text)
+		}
+	}
+}
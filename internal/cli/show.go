@@ -55,7 +55,7 @@ func RunShow(cfg *ShowConfig) (*ShowResult, error) {
 		Date:        a.Frontmatter.Date,
 		Tags:        a.Frontmatter.Tags,
 		ScopePaths:  a.Frontmatter.Scope.Paths,
-		Constraints: a.Frontmatter.Constraints,
+		Constraints: adr.ConstraintStrings(a.Frontmatter.Constraints),
 		Invariants:  a.Frontmatter.Invariants,
 		Decision:    decision,
 		File:        a.Filename,
@@ -119,13 +119,8 @@ func resolveADRPath(id string, dir string) (string, error) {
 		return filepath.Join(dir, id), nil
 	}
 
-	// Extract number from ADR-NNNN or just NNNN
-	var num int
-	if strings.HasPrefix(strings.ToUpper(id), "ADR-") {
-		_, _ = fmt.Sscanf(id[4:], "%d", &num)
-	} else {
-		_, _ = fmt.Sscanf(id, "%d", &num)
-	}
+	// Extract number from PREFIX-NNNN (e.g. ADR-NNNN) or just NNNN
+	num, _ := adr.ExtractNumber(id)
 
 	if num == 0 {
 		return "", fmt.Errorf("cannot parse ADR identifier: %s", id)
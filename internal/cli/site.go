@@ -0,0 +1,87 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/sventorben/decider/internal/adr"
+	"github.com/sventorben/decider/internal/site"
+)
+
+// SiteConfig holds configuration for the site command.
+type SiteConfig struct {
+	Dir    string
+	Out    string // Output directory
+	Title  string // Site title; site.DefaultTitle when empty
+	Format OutputFormat
+	Output *Output
+}
+
+// SiteResult holds the result of the site command.
+type SiteResult struct {
+	Out     string   `json:"out"`
+	ADRs    int      `json:"adrs"`
+	Files   []string `json:"files"`
+	Removed []string `json:"removed,omitempty"` // Stale pages of ADRs or tags that no longer exist
+}
+
+// RunSite renders all ADRs into a static HTML site in the output directory.
+// Pages left over from ADRs or tags that no longer exist are removed; other
+// files in the directory are left alone.
+func RunSite(cfg *SiteConfig) (*SiteResult, error) {
+	adrs, err := adr.LoadAllADRs(cfg.Dir)
+	if err != nil {
+		return nil, fmt.Errorf("loading ADRs: %w", err)
+	}
+
+	files, err := site.Render(adrs, site.Options{Title: cfg.Title})
+	if err != nil {
+		return nil, fmt.Errorf("rendering site: %w", err)
+	}
+
+	result := &SiteResult{Out: cfg.Out, ADRs: len(adrs)}
+	written := make(map[string]bool)
+	for _, f := range files {
+		path := filepath.Join(cfg.Out, filepath.FromSlash(f.Path))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return nil, fmt.Errorf("creating directory: %w", err)
+		}
+		if err := os.WriteFile(path, f.Data, 0644); err != nil {
+			return nil, fmt.Errorf("writing %s: %w", path, err)
+		}
+		written[path] = true
+		result.Files = append(result.Files, f.Path)
+	}
+
+	for _, sub := range []string{"adr", "tags"} {
+		stale, err := filepath.Glob(filepath.Join(cfg.Out, sub, "*.html"))
+		if err != nil {
+			return nil, err
+		}
+		sort.Strings(stale)
+		for _, path := range stale {
+			if written[path] {
+				continue
+			}
+			if err := os.Remove(path); err != nil {
+				return nil, fmt.Errorf("removing %s: %w", path, err)
+			}
+			result.Removed = append(result.Removed, sub+"/"+filepath.Base(path))
+		}
+	}
+
+	// Output
+	if cfg.Format == FormatTOON || cfg.Format == FormatJSON {
+		_ = cfg.Output.PrintStructured(result)
+		return result, nil
+	}
+
+	for _, path := range result.Removed {
+		cfg.Output.Println("Removed stale page %s", path)
+	}
+	cfg.Output.Success("Generated %d file(s) for %d ADR(s) in %s", len(result.Files), result.ADRs, cfg.Out)
+
+	return result, nil
+}
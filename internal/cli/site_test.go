@@ -0,0 +1,83 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRunSite(t *testing.T) {
+	root := t.TempDir()
+	dir := filepath.Join(root, "docs", "adr")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	writeTestADR(t, dir, "0001-database-access.md", `
+adr_id: ADR-0001
+title: Database Access
+status: adopted
+date: 2026-01-16
+tags: [database]
+`)
+	writeTestADR(t, dir, "0002-layering.md", `
+adr_id: ADR-0002
+title: Layering
+status: proposed
+date: 2026-01-17
+tags: [architecture]
+`)
+
+	out := filepath.Join(root, "public")
+	// Pages of an ADR and a tag that no longer exist, and an unrelated file
+	for name, content := range map[string]string{
+		"adr/0003-removed.html": "stale",
+		"tags/removed.html":     "stale",
+		"CNAME":                 "adr.example.com",
+	} {
+		path := filepath.Join(out, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	result, err := RunSite(&SiteConfig{Dir: dir, Out: out, Title: "Team Decisions", Format: FormatText, Output: testOutput()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.ADRs != 2 || len(result.Files) == 0 {
+		t.Errorf("RunSite() = %+v", result)
+	}
+	if want := []string{"adr/0003-removed.html", "tags/removed.html"}; !reflect.DeepEqual(result.Removed, want) {
+		t.Errorf("Removed = %v, want %v", result.Removed, want)
+	}
+
+	for _, f := range result.Files {
+		if _, err := os.Stat(filepath.Join(out, filepath.FromSlash(f))); err != nil {
+			t.Errorf("%s not written: %v", f, err)
+		}
+	}
+	index, err := os.ReadFile(filepath.Join(out, "index.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(index), "<h1>Team Decisions</h1>") || !strings.Contains(string(index), "adr/0002-layering.html") {
+		t.Errorf("unexpected index.html:\n%s", index)
+	}
+	if _, err := os.Stat(filepath.Join(out, "CNAME")); err != nil {
+		t.Errorf("unrelated file removed: %v", err)
+	}
+
+	// A second run writes the same files and removes nothing
+	again, err := RunSite(&SiteConfig{Dir: dir, Out: out, Format: FormatText, Title: "Team Decisions", Output: testOutput()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again.Removed != nil || !reflect.DeepEqual(again.Files, result.Files) {
+		t.Errorf("second run = %+v", again)
+	}
+}
@@ -0,0 +1,109 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/sventorben/decider/internal/adr"
+	"github.com/sventorben/decider/internal/index"
+)
+
+// StatusConfig holds configuration for the status command.
+type StatusConfig struct {
+	ID      string // ADR-NNNN, NNNN, or filename
+	Status  string // Target status
+	Date    string // Transition date (YYYY-MM-DD); defaults to today
+	Dir     string
+	NoIndex bool
+	Format  OutputFormat
+	Output  *Output
+}
+
+// StatusResult holds the result of the status command.
+type StatusResult struct {
+	ADRID      string `json:"adr_id"`
+	From       string `json:"from"`
+	To         string `json:"to"`
+	StatusDate string `json:"status_date"`
+	File       string `json:"file"`
+}
+
+// RunStatus moves an ADR to a new status if the lifecycle state machine allows it.
+func RunStatus(cfg *StatusConfig) (*StatusResult, error) {
+	to, err := adr.ParseStatus(cfg.Status)
+	if err != nil {
+		return nil, err
+	}
+
+	date := cfg.Date
+	if date == "" {
+		date = time.Now().Format("2006-01-02")
+	} else if _, err := time.Parse("2006-01-02", date); err != nil {
+		return nil, fmt.Errorf("invalid date %q: must be in YYYY-MM-DD format", date)
+	}
+
+	filePath, err := resolveADRPath(cfg.ID, cfg.Dir)
+	if err != nil {
+		return nil, err
+	}
+	a, err := adr.LoadADR(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	from := a.Frontmatter.Status
+	if err := adr.CheckTransition(from, to); err != nil {
+		return nil, fmt.Errorf("%s: %w", a.Frontmatter.ADRID, err)
+	}
+	if to == adr.StatusSuperseded && len(a.Frontmatter.SupersededBy) == 0 {
+		return nil, fmt.Errorf("%s: superseding requires a successor; use 'decider supersede'", a.Frontmatter.ADRID)
+	}
+
+	raw, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("reading file %s: %w", filePath, err)
+	}
+	content, err := setStatus(string(raw), to, date)
+	if err != nil {
+		return nil, fmt.Errorf("updating %s: %w", a.Filename, err)
+	}
+	if err := writeFileAtomic(filePath, []byte(content)); err != nil {
+		return nil, fmt.Errorf("writing %s: %w", filePath, err)
+	}
+
+	result := &StatusResult{
+		ADRID:      a.Frontmatter.ADRID,
+		From:       string(from),
+		To:         string(to),
+		StatusDate: date,
+		File:       filePath,
+	}
+
+	// Update index unless disabled
+	if !cfg.NoIndex {
+		if err := index.WriteToDir(cfg.Dir); err != nil {
+			// Non-fatal: warn but don't fail
+			cfg.Output.Error("warning: could not update index: %v", err)
+		}
+	}
+
+	// Output
+	if cfg.Format == FormatTOON || cfg.Format == FormatJSON {
+		_ = cfg.Output.PrintStructured(result)
+	} else {
+		cfg.Output.Success("%s: %s -> %s (%s)", result.ADRID, from, to, date)
+	}
+
+	return result, nil
+}
+
+// setStatus rewrites the status line of raw ADR content and stamps the
+// transition date, leaving the rest of the file untouched.
+func setStatus(content string, status adr.Status, date string) (string, error) {
+	content, err := adr.SetFrontmatterField(content, "status", status)
+	if err != nil {
+		return "", err
+	}
+	return adr.SetFrontmatterField(content, "status_date", date)
+}
@@ -0,0 +1,84 @@
+package cli
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestRunStatus(t *testing.T) {
+	dir := t.TempDir()
+	path := writeTestADR(t, dir, "0001-test.md", `
+adr_id: ADR-0001
+title: Test   # comment is preserved
+status: proposed
+date: 2026-01-16
+tags: [a, b]
+`)
+	before, _ := os.ReadFile(path)
+
+	result, err := RunStatus(&StatusConfig{
+		ID:      "ADR-0001",
+		Status:  "adopted",
+		Date:    "2026-02-01",
+		Dir:     dir,
+		NoIndex: true,
+		Format:  FormatText,
+		Output:  testOutput(),
+	})
+	if err != nil {
+		t.Fatalf("RunStatus() error = %v", err)
+	}
+	if result.From != "proposed" || result.To != "adopted" {
+		t.Errorf("RunStatus() = %+v, want proposed -> adopted", result)
+	}
+
+	after, _ := os.ReadFile(path)
+	want := strings.Replace(string(before), "status: proposed", "status: adopted", 1)
+	want = strings.Replace(want, "tags: [a, b]\n", "tags: [a, b]\nstatus_date: \"2026-02-01\"\n", 1)
+	if string(after) != want {
+		t.Errorf("ADR not rewritten in place:\n%s\nwant:\n%s", after, want)
+	}
+}
+
+func TestRunStatusRejectsIllegalTransitions(t *testing.T) {
+	tests := []struct {
+		name string
+		from string
+		to   string
+	}{
+		{"proposed to deprecated", "proposed", "deprecated"},
+		{"rejected is terminal", "rejected", "adopted"},
+		{"superseded needs successor", "adopted", "superseded"},
+		{"unknown status", "adopted", "archived"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			path := writeTestADR(t, dir, "0001-test.md", `
+adr_id: ADR-0001
+title: Test
+status: `+tt.from+`
+date: 2026-01-16
+`)
+			before, _ := os.ReadFile(path)
+
+			if _, err := RunStatus(&StatusConfig{
+				ID:      "ADR-0001",
+				Status:  tt.to,
+				Dir:     dir,
+				NoIndex: true,
+				Format:  FormatText,
+				Output:  testOutput(),
+			}); err == nil {
+				t.Fatal("RunStatus() expected error")
+			}
+
+			after, _ := os.ReadFile(path)
+			if string(before) != string(after) {
+				t.Error("rejected transition modified the ADR")
+			}
+		})
+	}
+}
@@ -0,0 +1,243 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/sventorben/decider/internal/adr"
+	"github.com/sventorben/decider/internal/index"
+)
+
+// SupersedeConfig holds configuration for the supersede command.
+type SupersedeConfig struct {
+	ID       string // ADR being superseded: ADR-NNNN, NNNN, or filename
+	Title    string // Title of a new replacement ADR (mutually exclusive with By)
+	By       string // Existing replacement ADR (mutually exclusive with Title)
+	Dir      string
+	Tags     []string // Tags for a new replacement; defaults to the old ADR's tags
+	Paths    []string // Scope paths for a new replacement; defaults to the old ADR's paths
+	Status   string   // Status for a new replacement
+	Template string   // Template for a new replacement; defaults to "adr"
+	NoIndex  bool
+	Format   OutputFormat
+	Output   *Output
+
+	// IDPrefix and AllowedTags apply to a new replacement as in NewConfig.
+	IDPrefix    string
+	AllowedTags []string
+}
+
+// SupersedeResult holds the result of the supersede command.
+type SupersedeResult struct {
+	Superseded   string   `json:"superseded"`
+	SupersededBy string   `json:"superseded_by"`
+	Created      bool     `json:"created"`
+	Files        []string `json:"files"`
+}
+
+// RunSupersede marks an ADR as superseded and links it with its replacement.
+// Both files are rewritten in place, touching only the affected frontmatter
+// keys; if either write fails the other file is restored. The old ADR must be
+// allowed to move to superseded by the lifecycle state machine.
+func RunSupersede(cfg *SupersedeConfig) (*SupersedeResult, error) {
+	if (cfg.Title == "") == (cfg.By == "") {
+		return nil, fmt.Errorf("exactly one of a new title or an existing replacement ADR is required")
+	}
+
+	oldPath, err := resolveADRPath(cfg.ID, cfg.Dir)
+	if err != nil {
+		return nil, err
+	}
+	oldADR, err := adr.LoadADR(oldPath)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkSupersedable(oldADR); err != nil {
+		return nil, err
+	}
+	oldID := oldADR.Frontmatter.ADRID
+
+	var (
+		newID      string
+		newPath    string
+		newContent string
+		created    bool
+	)
+
+	if cfg.By != "" {
+		newPath, err = resolveADRPath(cfg.By, cfg.Dir)
+		if err != nil {
+			return nil, err
+		}
+		if filepath.Clean(newPath) == filepath.Clean(oldPath) {
+			return nil, fmt.Errorf("an ADR cannot supersede itself")
+		}
+		newADR, err := adr.LoadADR(newPath)
+		if err != nil {
+			return nil, err
+		}
+		switch newADR.Frontmatter.Status {
+		case adr.StatusSuperseded, adr.StatusRejected:
+			return nil, fmt.Errorf("%s is %s and cannot replace another ADR", newADR.Frontmatter.ADRID, newADR.Frontmatter.Status)
+		}
+		newID = newADR.Frontmatter.ADRID
+
+		raw, err := os.ReadFile(newPath)
+		if err != nil {
+			return nil, fmt.Errorf("reading file %s: %w", newPath, err)
+		}
+		newContent, err = adr.SetFrontmatterField(string(raw), "supersedes", appendUnique(newADR.Frontmatter.Supersedes, oldID))
+		if err != nil {
+			return nil, fmt.Errorf("updating %s: %w", newADR.Filename, err)
+		}
+	} else {
+		tags := cfg.Tags
+		if tags == nil {
+			tags = oldADR.Frontmatter.Tags
+		}
+		paths := cfg.Paths
+		if paths == nil {
+			paths = oldADR.Frontmatter.Scope.Paths
+		}
+		status := cfg.Status
+		if status == "" {
+			status = string(adr.StatusAdopted)
+		}
+
+		draft, err := prepareNew(&NewConfig{
+			Title:       cfg.Title,
+			Dir:         cfg.Dir,
+			Tags:        tags,
+			Paths:       paths,
+			Status:      status,
+			Template:    cfg.Template,
+			IDPrefix:    cfg.IDPrefix,
+			AllowedTags: cfg.AllowedTags,
+			Supersedes:  []string{oldID},
+		})
+		if err != nil {
+			return nil, err
+		}
+		newID = draft.Frontmatter.ADRID
+		newPath = draft.FilePath
+		newContent = draft.Content
+		created = true
+	}
+
+	// Rewrite the superseded ADR
+	oldRaw, err := os.ReadFile(oldPath)
+	if err != nil {
+		return nil, fmt.Errorf("reading file %s: %w", oldPath, err)
+	}
+	oldContent, err := setStatus(string(oldRaw), adr.StatusSuperseded, time.Now().Format("2006-01-02"))
+	if err != nil {
+		return nil, fmt.Errorf("updating %s: %w", oldADR.Filename, err)
+	}
+	oldContent, err = adr.SetFrontmatterField(oldContent, "superseded_by", appendUnique(oldADR.Frontmatter.SupersededBy, newID))
+	if err != nil {
+		return nil, fmt.Errorf("updating %s: %w", oldADR.Filename, err)
+	}
+
+	// Write the replacement first, then the superseded ADR; undo on failure
+	var newOriginal []byte
+	if !created {
+		newOriginal, err = os.ReadFile(newPath)
+		if err != nil {
+			return nil, fmt.Errorf("reading file %s: %w", newPath, err)
+		}
+	}
+	if err := writeFileAtomic(newPath, []byte(newContent)); err != nil {
+		return nil, fmt.Errorf("writing %s: %w", newPath, err)
+	}
+	if err := writeFileAtomic(oldPath, []byte(oldContent)); err != nil {
+		if created {
+			_ = os.Remove(newPath)
+		} else {
+			_ = writeFileAtomic(newPath, newOriginal)
+		}
+		return nil, fmt.Errorf("writing %s: %w", oldPath, err)
+	}
+
+	result := &SupersedeResult{
+		Superseded:   oldID,
+		SupersededBy: newID,
+		Created:      created,
+		Files:        []string{oldPath, newPath},
+	}
+
+	// Update index unless disabled
+	if !cfg.NoIndex {
+		if err := index.WriteToDir(cfg.Dir); err != nil {
+			// Non-fatal: warn but don't fail
+			cfg.Output.Error("warning: could not update index: %v", err)
+		}
+	}
+
+	// Output
+	if cfg.Format == FormatTOON || cfg.Format == FormatJSON {
+		_ = cfg.Output.PrintStructured(result)
+	} else {
+		if created {
+			cfg.Output.Success("Created %s", newPath)
+		}
+		cfg.Output.Success("%s is now superseded by %s", oldID, newID)
+		cfg.Output.Println("  Updated: %s", oldPath)
+		cfg.Output.Println("  Updated: %s", newPath)
+	}
+
+	return result, nil
+}
+
+// checkSupersedable rejects ADRs whose status does not allow supersession.
+func checkSupersedable(a *adr.ADR) error {
+	switch a.Frontmatter.Status {
+	case adr.StatusSuperseded:
+		return fmt.Errorf("%s is already superseded by %v", a.Frontmatter.ADRID, a.Frontmatter.SupersededBy)
+	case adr.StatusRejected:
+		return fmt.Errorf("%s was rejected and cannot be superseded", a.Frontmatter.ADRID)
+	}
+	if err := adr.CheckTransition(a.Frontmatter.Status, adr.StatusSuperseded); err != nil {
+		return fmt.Errorf("%s: %w", a.Frontmatter.ADRID, err)
+	}
+	return nil
+}
+
+// appendUnique returns a copy of list with item appended if not already present.
+func appendUnique(list []string, item string) []string {
+	result := append([]string{}, list...)
+	if !contains(result, item) {
+		result = append(result, item)
+	}
+	return result
+}
+
+// writeFileAtomic writes data to a temporary file next to path and renames it
+// into place, so readers never observe a partially written ADR.
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	tmpName := tmp.Name()
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmpName)
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmpName)
+		return err
+	}
+	if err := os.Chmod(tmpName, 0644); err != nil {
+		_ = os.Remove(tmpName)
+		return err
+	}
+	if err := os.Rename(tmpName, path); err != nil {
+		_ = os.Remove(tmpName)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,192 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sventorben/decider/internal/adr"
+)
+
+const testADRBody = `
+# Test
+
+## Context
+
+Context.
+
+## Decision
+
+Decision.
+
+## Alternatives Considered
+
+None.
+
+## Consequences
+
+Consequences.
+`
+
+// writeTestADR writes an ADR file with the given frontmatter lines into dir.
+func writeTestADR(t *testing.T, dir, filename, frontmatter string) string {
+	t.Helper()
+	path := filepath.Join(dir, filename)
+	content := "---\n" + strings.TrimLeft(frontmatter, "\n") + "---\n" + testADRBody
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("writing %s: %v", path, err)
+	}
+	return path
+}
+
+func testOutput() *Output {
+	return &Output{Format: FormatText, Writer: &bytes.Buffer{}}
+}
+
+func TestRunSupersedeCreatesReplacement(t *testing.T) {
+	dir := t.TempDir()
+	oldPath := writeTestADR(t, dir, "0001-use-redux.md", `
+adr_id: ADR-0001
+title: Use Redux  # keep this comment
+status: adopted
+date: 2026-01-16
+scope:
+  paths:
+    - "src/frontend/**"
+tags:
+  - frontend
+supersedes: []
+superseded_by: []
+`)
+	before, _ := os.ReadFile(oldPath)
+
+	result, err := RunSupersede(&SupersedeConfig{
+		ID:     "ADR-0001",
+		Title:  "Use Zustand",
+		Dir:    dir,
+		Format: FormatText,
+		Output: testOutput(),
+	})
+	if err != nil {
+		t.Fatalf("RunSupersede() error = %v", err)
+	}
+	if !result.Created || result.SupersededBy != "ADR-0002" {
+		t.Errorf("RunSupersede() = %+v, want created ADR-0002", result)
+	}
+
+	after, _ := os.ReadFile(oldPath)
+	want := strings.Replace(string(before), "status: adopted", "status: superseded", 1)
+	want = strings.Replace(want, "superseded_by: []\n", "superseded_by:\n  - ADR-0002\nstatus_date: \""+time.Now().Format("2006-01-02")+"\"\n", 1)
+	if string(after) != want {
+		t.Errorf("superseded ADR not rewritten in place:\n%s\nwant:\n%s", after, want)
+	}
+
+	newADR, err := adr.LoadADR(filepath.Join(dir, "0002-use-zustand.md"))
+	if err != nil {
+		t.Fatalf("loading new ADR: %v", err)
+	}
+	fm := newADR.Frontmatter
+	if len(fm.Supersedes) != 1 || fm.Supersedes[0] != "ADR-0001" {
+		t.Errorf("new ADR supersedes = %v, want [ADR-0001]", fm.Supersedes)
+	}
+	if fm.Status != adr.StatusAdopted {
+		t.Errorf("new ADR status = %q, want adopted", fm.Status)
+	}
+	if len(fm.Scope.Paths) != 1 || fm.Scope.Paths[0] != "src/frontend/**" {
+		t.Errorf("new ADR scope paths = %v, want inherited paths", fm.Scope.Paths)
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "index.yaml")); err != nil {
+		t.Errorf("index was not regenerated: %v", err)
+	}
+}
+
+func TestRunSupersedeByExisting(t *testing.T) {
+	dir := t.TempDir()
+	writeTestADR(t, dir, "0001-old.md", `
+adr_id: ADR-0001
+title: Old
+status: adopted
+date: 2026-01-16
+superseded_by: []
+`)
+	writeTestADR(t, dir, "0002-new.md", `
+adr_id: ADR-0002
+title: New
+status: adopted
+date: 2026-01-17
+supersedes: []
+`)
+
+	if _, err := RunSupersede(&SupersedeConfig{
+		ID:      "1",
+		By:      "ADR-0002",
+		Dir:     dir,
+		NoIndex: true,
+		Format:  FormatText,
+		Output:  testOutput(),
+	}); err != nil {
+		t.Fatalf("RunSupersede() error = %v", err)
+	}
+
+	oldADR, _ := adr.LoadADR(filepath.Join(dir, "0001-old.md"))
+	newADR, _ := adr.LoadADR(filepath.Join(dir, "0002-new.md"))
+	if oldADR.Frontmatter.Status != adr.StatusSuperseded {
+		t.Errorf("old status = %q, want superseded", oldADR.Frontmatter.Status)
+	}
+	if len(oldADR.Frontmatter.SupersededBy) != 1 || oldADR.Frontmatter.SupersededBy[0] != "ADR-0002" {
+		t.Errorf("old superseded_by = %v, want [ADR-0002]", oldADR.Frontmatter.SupersededBy)
+	}
+	if len(newADR.Frontmatter.Supersedes) != 1 || newADR.Frontmatter.Supersedes[0] != "ADR-0001" {
+		t.Errorf("new supersedes = %v, want [ADR-0001]", newADR.Frontmatter.Supersedes)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "index.yaml")); !os.IsNotExist(err) {
+		t.Error("index written despite NoIndex")
+	}
+}
+
+func TestRunSupersedeRefusesInvalidStatus(t *testing.T) {
+	tests := []struct {
+		name   string
+		status string
+	}{
+		{"already superseded", "superseded"},
+		{"rejected", "rejected"},
+		{"proposed", "proposed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			path := writeTestADR(t, dir, "0001-old.md", `
+adr_id: ADR-0001
+title: Old
+status: `+tt.status+`
+date: 2026-01-16
+`)
+			before, _ := os.ReadFile(path)
+
+			_, err := RunSupersede(&SupersedeConfig{
+				ID:     "ADR-0001",
+				Title:  "Replacement",
+				Dir:    dir,
+				Format: FormatText,
+				Output: testOutput(),
+			})
+			if err == nil {
+				t.Fatal("RunSupersede() expected error")
+			}
+
+			after, _ := os.ReadFile(path)
+			if string(before) != string(after) {
+				t.Error("refused supersede modified the ADR")
+			}
+			if _, err := os.Stat(filepath.Join(dir, "0002-replacement.md")); !os.IsNotExist(err) {
+				t.Error("refused supersede created a replacement ADR")
+			}
+		})
+	}
+}
@@ -0,0 +1,228 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/sventorben/decider/internal/adr"
+)
+
+// DefaultTemplateName is the template used by new when none is requested.
+// It corresponds to templates/adr.md in the ADR directory.
+const DefaultTemplateName = "adr"
+
+// TemplateData holds the values available to ADR templates as placeholders,
+// e.g. {{.ADRID}}, {{.Title}} or {{join .Tags ", "}}.
+type TemplateData struct {
+	ADRID  string
+	Title  string
+	Date   string
+	Status string
+	Tags   []string
+	Paths  []string
+}
+
+var templateNameRegex = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+
+// legacyHeading is the title line of templates written by earlier versions
+// of 'decider init', which used literal placeholders instead of actions.
+const legacyHeading = "# ADR-NNNN: Your Decision Title Here"
+
+var templateFuncs = template.FuncMap{
+	"join": strings.Join,
+}
+
+// loadTemplate returns the body of the named template. Templates are read from
+// the templates directory inside the ADR directory; any frontmatter in the
+// file is dropped because decider generates the frontmatter itself, and a
+// legacy heading becomes the ADR's heading. When no file exists, the built-in
+// template of the same name is used.
+func loadTemplate(adrDir, name string) (string, error) {
+	if name == "" {
+		name = DefaultTemplateName
+	}
+	if !templateNameRegex.MatchString(name) {
+		return "", fmt.Errorf("invalid template name %q", name)
+	}
+
+	path := filepath.Join(adrDir, "templates", name+".md")
+	data, err := os.ReadFile(path)
+	if err == nil {
+		_, body, err := adr.ExtractFrontmatter(string(data))
+		if err != nil {
+			return "", fmt.Errorf("reading template %s: %w", path, err)
+		}
+		body = strings.TrimLeft(body, "\r\n")
+		if line, rest, _ := strings.Cut(body, "\n"); strings.TrimSuffix(line, "\r") == legacyHeading {
+			body = "# {{.ADRID}}: {{.Title}}\n" + rest
+		}
+		return body, nil
+	}
+	if !os.IsNotExist(err) {
+		return "", fmt.Errorf("reading template %s: %w", path, err)
+	}
+
+	body, ok := builtinTemplates[name]
+	if !ok {
+		return "", fmt.Errorf("template %q not found in %s and no built-in template exists (built-in: %s)",
+			name, filepath.Join(adrDir, "templates"), strings.Join(BuiltinTemplateNames(), ", "))
+	}
+	return body, nil
+}
+
+// renderTemplate executes a template body with the given data.
+func renderTemplate(body string, data TemplateData) (string, error) {
+	tmpl, err := template.New("adr").Funcs(templateFuncs).Parse(body)
+	if err != nil {
+		return "", fmt.Errorf("parsing template: %w", err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("rendering template: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// BuiltinTemplateNames returns the names of the built-in ADR templates.
+func BuiltinTemplateNames() []string {
+	names := make([]string, 0, len(builtinTemplates))
+	for name := range builtinTemplates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// builtinTemplates are used when the ADR directory has no template file of
+// the requested name. All of them contain the required sections and the
+// rationale pattern, so generated ADRs pass 'check adr'.
+var builtinTemplates = map[string]string{
+	DefaultTemplateName: fullTemplate,
+	"full":              fullTemplate,
+	"lightweight":       lightweightTemplate,
+	"spike":             spikeTemplate,
+}
+
+const fullTemplate = `# {{.ADRID}}: {{.Title}}
+
+## Context
+
+_Describe the context and background that led to this decision. What problem are we solving? What forces are at play?_
+
+Decision drivers:
+- _Key driver 1 that influenced the decision_
+- _Key driver 2_
+- _Key driver 3_
+
+## Decision
+
+_State the decision clearly and concisely._
+
+### [Chosen Option]: Adopted
+
+**Adopted because:**
+- _Clear, concrete reason why this option was chosen_
+- _Tie reasons to decision drivers above_
+- _Technical, operational, or strategic justification_
+
+**Adopted despite:**
+- _Known downside or trade-off we consciously accepted_
+- _Cost or weakness compared to alternatives_
+- _Risk we are taking on_
+
+## Alternatives Considered
+
+### [Alternative A]: Rejected
+
+**Rejected because:**
+- _Clear, concrete reason why this option was not chosen_
+- _Technical, organizational, or strategic reason_
+- _How it failed to meet decision drivers_
+
+**Rejected despite:**
+- _Legitimate strength of this option_
+- _Benefit that made it attractive_
+- _Reason it was seriously considered_
+
+## Consequences
+
+**Positive:**
+- _First positive consequence_
+- _Second positive consequence_
+
+**Negative:**
+- _First negative consequence (and mitigation if any)_
+- _Second negative consequence_
+`
+
+const lightweightTemplate = `# {{.ADRID}}: {{.Title}}
+
+## Context
+
+_What problem are we solving, and why now?_
+
+## Decision
+
+### [Chosen Option]: Adopted
+
+**Adopted because:**
+- _Main reason this option was chosen_
+
+**Adopted despite:**
+- _Main trade-off we accept_
+
+## Alternatives Considered
+
+_List the alternatives briefly, or state that none were considered._
+
+## Consequences
+
+- _Most important consequence_
+`
+
+const spikeTemplate = `# {{.ADRID}}: {{.Title}}
+
+## Context
+
+_What question does this spike need to answer? What is the time box?_
+
+- Question: _The question to answer_
+- Time box: _e.g. 3 days_
+- Success criteria: _What we need to learn to decide_
+
+## Decision
+
+_State what the spike concluded._
+
+### [Chosen Option]: Adopted
+
+**Adopted because:**
+- _Finding that supports this option_
+
+**Adopted despite:**
+- _Open risk or unanswered question_
+
+## Alternatives Considered
+
+### [Alternative A]: Rejected
+
+**Rejected because:**
+- _Finding that ruled this option out_
+
+**Rejected despite:**
+- _Strength that made it worth investigating_
+
+## Consequences
+
+**Follow-up work:**
+- _Task needed to act on the decision_
+
+**Open questions:**
+- _What the spike did not answer_
+`
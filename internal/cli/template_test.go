@@ -0,0 +1,169 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sventorben/decider/internal/adr"
+)
+
+func writeTemplate(t *testing.T, dir, name, content string) {
+	t.Helper()
+	templatesDir := filepath.Join(dir, "templates")
+	if err := os.MkdirAll(templatesDir, 0755); err != nil {
+		t.Fatalf("creating templates dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(templatesDir, name+".md"), []byte(content), 0644); err != nil {
+		t.Fatalf("writing template: %v", err)
+	}
+}
+
+func runNewForTest(t *testing.T, dir, template string) string {
+	t.Helper()
+	result, err := RunNew(&NewConfig{
+		Title:    "Use Redis",
+		Dir:      dir,
+		Tags:     []string{"cache", "infra"},
+		Paths:    []string{"src/cache/**"},
+		Status:   "proposed",
+		Template: template,
+		NoIndex:  true,
+		Format:   FormatText,
+		Output:   testOutput(),
+	})
+	if err != nil {
+		t.Fatalf("RunNew() error = %v", err)
+	}
+	data, err := os.ReadFile(result.FilePath)
+	if err != nil {
+		t.Fatalf("reading new ADR: %v", err)
+	}
+	return string(data)
+}
+
+func TestRunNewUsesRepositoryTemplate(t *testing.T) {
+	dir := t.TempDir()
+	writeTemplate(t, dir, "adr", `---
+adr_id: ADR-NNNN
+title: ignored
+---
+
+# {{.ADRID}}: {{.Title}}
+
+Status {{.Status}} since {{.Date}}. Tags: {{join .Tags ", "}}. Scope: {{join .Paths ", "}}.
+
+## Team Section
+`)
+
+	content := runNewForTest(t, dir, "")
+	if !strings.Contains(content, "# ADR-0001: Use Redis\n") {
+		t.Errorf("template placeholders not rendered:\n%s", content)
+	}
+	if !strings.Contains(content, "Tags: cache, infra. Scope: src/cache/**.") {
+		t.Errorf("list placeholders not rendered:\n%s", content)
+	}
+	if !strings.Contains(content, "## Team Section") {
+		t.Errorf("custom template body not used:\n%s", content)
+	}
+	if strings.Contains(content, "title: ignored") {
+		t.Errorf("template frontmatter leaked into ADR:\n%s", content)
+	}
+
+	a, err := adr.ParseADR(content, "0001-use-redis.md", "")
+	if err != nil {
+		t.Fatalf("parsing generated ADR: %v", err)
+	}
+	if a.Frontmatter.Title != "Use Redis" || a.Frontmatter.ADRID != "ADR-0001" {
+		t.Errorf("generated frontmatter = %+v", a.Frontmatter)
+	}
+}
+
+func TestRunNewLegacyHeading(t *testing.T) {
+	dir := t.TempDir()
+	writeTemplate(t, dir, "adr", "---\nadr_id: ADR-NNNN\n---\n\n# ADR-NNNN: Your Decision Title Here\n\nIDs look like ADR-NNNN, dates like YYYY-MM-DD.\n")
+
+	content := runNewForTest(t, dir, "")
+	if !strings.Contains(content, "# ADR-0001: Use Redis\n") {
+		t.Errorf("legacy heading not replaced:\n%s", content)
+	}
+	// Only the heading is rewritten; the literals elsewhere are kept
+	if !strings.Contains(content, "IDs look like ADR-NNNN, dates like YYYY-MM-DD.\n") {
+		t.Errorf("literals outside the heading replaced:\n%s", content)
+	}
+}
+
+func TestRunNewEscapedBraces(t *testing.T) {
+	dir := t.TempDir()
+	writeTemplate(t, dir, "adr", "# {{.ADRID}}: {{.Title}}\n\n```go\ntmpl := `{{`{{.Name}}`}}`\n```\n")
+
+	content := runNewForTest(t, dir, "")
+	if !strings.Contains(content, "tmpl := `{{.Name}}`\n") {
+		t.Errorf("escaped braces not rendered literally:\n%s", content)
+	}
+}
+
+func TestRunNewBuiltinTemplates(t *testing.T) {
+	for _, name := range BuiltinTemplateNames() {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			content := runNewForTest(t, dir, name)
+
+			a, err := adr.ParseADR(content, "0001-use-redis.md", "")
+			if err != nil {
+				t.Fatalf("parsing generated ADR: %v", err)
+			}
+			if vr := adr.Validate(a); !vr.IsValidStrict() {
+				t.Errorf("built-in template %q does not validate: %v %v", name, vr.Errors, vr.Warnings)
+			}
+		})
+	}
+}
+
+func TestRunNewNamedTemplateOverridesBuiltin(t *testing.T) {
+	dir := t.TempDir()
+	writeTemplate(t, dir, "spike", "# {{.ADRID}} spike: {{.Title}}\n")
+
+	content := runNewForTest(t, dir, "spike")
+	if !strings.Contains(content, "# ADR-0001 spike: Use Redis") {
+		t.Errorf("repository spike template not used:\n%s", content)
+	}
+}
+
+func TestRunNewTemplateErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		content  string
+	}{
+		{"unknown template", "missing", ""},
+		{"path traversal", "../secret", ""},
+		{"unknown placeholder", "broken", "{{.Owner}}"},
+		{"malformed template", "broken", "{{.Title"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			if tt.content != "" {
+				writeTemplate(t, dir, tt.template, tt.content)
+			}
+			_, err := RunNew(&NewConfig{
+				Title:    "Use Redis",
+				Dir:      dir,
+				Status:   "proposed",
+				Template: tt.template,
+				NoIndex:  true,
+				Format:   FormatText,
+				Output:   testOutput(),
+			})
+			if err == nil {
+				t.Fatal("RunNew() expected error")
+			}
+			if entries, _ := adr.ListADRFiles(dir); len(entries) != 0 {
+				t.Errorf("failed RunNew() wrote %v", entries)
+			}
+		})
+	}
+}
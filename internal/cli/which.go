@@ -0,0 +1,251 @@
+package cli
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/sventorben/decider/internal/adr"
+	"github.com/sventorben/decider/internal/git"
+	"github.com/sventorben/decider/internal/glob"
+)
+
+// WhichConfig holds configuration for the which command.
+type WhichConfig struct {
+	Paths  []string // Files or directories; directories are expanded recursively
+	Dir    string
+	Root   string // Repository root; the git top-level (or working directory) when empty
+	Format OutputFormat
+	Output *Output
+}
+
+// WhichResult holds the result of the which command.
+type WhichResult struct {
+	Paths []WhichPath `json:"paths"`
+	ADRs  []WhichADR  `json:"adrs"` // Every ADR that governs one of the paths
+}
+
+// WhichPath lists the ADRs whose scope covers a path.
+type WhichPath struct {
+	Path    string       `json:"path"` // Relative to the repository root
+	Matches []WhichMatch `json:"matches"`
+}
+
+// WhichMatch names an ADR that covers a path and the pattern that decides it.
+type WhichMatch struct {
+	ADRID   string `json:"adr_id"`
+	Pattern string `json:"pattern"`
+}
+
+// WhichADR holds what to know about an ADR before editing a file it governs.
+type WhichADR struct {
+	ADRID         string   `json:"adr_id"`
+	Title         string   `json:"title"`
+	Status        string   `json:"status"`
+	File          string   `json:"file"`
+	Constraints   []string `json:"constraints,omitempty"`
+	Invariants    []string `json:"invariants,omitempty"`
+	AgentGuidance string   `json:"agent_guidance,omitempty"`
+}
+
+// RunWhich finds the ADRs whose scope covers each of the given paths.
+func RunWhich(cfg *WhichConfig) (*WhichResult, error) {
+	adrs, err := adr.LoadAllADRs(cfg.Dir)
+	if err != nil {
+		return nil, fmt.Errorf("loading ADRs: %w", err)
+	}
+
+	root, paths, err := resolvePaths(cfg.Root, cfg.Paths)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &WhichResult{Paths: []WhichPath{}, ADRs: []WhichADR{}}
+	governing := make(map[string]bool)
+	for _, p := range paths {
+		entry := WhichPath{Path: p, Matches: []WhichMatch{}}
+		for _, a := range adrs {
+			m := glob.MatchScope(a.Frontmatter.Scope.Paths, p)
+			if !m.Matched {
+				continue
+			}
+			entry.Matches = append(entry.Matches, WhichMatch{ADRID: a.Frontmatter.ADRID, Pattern: m.Pattern})
+			governing[a.FilePath] = true
+		}
+		result.Paths = append(result.Paths, entry)
+	}
+	for _, a := range adrs {
+		if governing[a.FilePath] {
+			result.ADRs = append(result.ADRs, WhichADR{
+				ADRID:         a.Frontmatter.ADRID,
+				Title:         a.Frontmatter.Title,
+				Status:        string(a.Frontmatter.Status),
+				File:          relativePath(root, a.FilePath),
+				Constraints:   adr.ConstraintStrings(a.Frontmatter.Constraints),
+				Invariants:    a.Frontmatter.Invariants,
+				AgentGuidance: extractSection(a.Body, "Agent Guidance"),
+			})
+		}
+	}
+
+	// Output
+	if cfg.Format == FormatTOON || cfg.Format == FormatJSON {
+		_ = cfg.Output.PrintStructured(result)
+		return result, nil
+	}
+
+	for _, p := range result.Paths {
+		cfg.Output.Println("%s", p.Path)
+		if len(p.Matches) == 0 {
+			cfg.Output.Println("  (no ADRs)")
+		}
+		for _, m := range p.Matches {
+			cfg.Output.Println("  %s via %s", m.ADRID, m.Pattern)
+		}
+	}
+
+	for _, a := range result.ADRs {
+		cfg.Output.Println("")
+		cfg.Output.Println("## %s: %s", a.ADRID, a.Title)
+		cfg.Output.Println("Status: %s", a.Status)
+		cfg.Output.Println("File:   %s", a.File)
+
+		if len(a.Constraints) > 0 {
+			cfg.Output.Println("")
+			cfg.Output.Println("### Constraints")
+			for _, c := range a.Constraints {
+				cfg.Output.Println("  - %s", c)
+			}
+		}
+
+		if len(a.Invariants) > 0 {
+			cfg.Output.Println("")
+			cfg.Output.Println("### Invariants")
+			for _, i := range a.Invariants {
+				cfg.Output.Println("  - %s", i)
+			}
+		}
+
+		if a.AgentGuidance != "" {
+			cfg.Output.Println("")
+			cfg.Output.Println("### Agent Guidance")
+			cfg.Output.Println("%s", a.AgentGuidance)
+		}
+	}
+
+	return result, nil
+}
+
+// resolvePaths returns the repository root, the git top-level when root is
+// empty (or the working directory outside git), and paths expanded against
+// it by expandPaths.
+func resolvePaths(root string, paths []string) (string, []string, error) {
+	// Directories are expanded with the files git lists when the root is
+	// the repository's
+	inGit := false
+	if root == "" {
+		var err error
+		if root, err = git.Root(); err == nil {
+			inGit = true
+		} else {
+			root = "."
+		}
+	}
+	abs, err := filepath.Abs(root)
+	if err != nil {
+		return "", nil, fmt.Errorf("resolving %s: %w", root, err)
+	}
+
+	expanded, err := expandPaths(abs, paths, inGit)
+	if err != nil {
+		return "", nil, err
+	}
+	return abs, expanded, nil
+}
+
+// expandPaths makes paths relative to root and replaces each directory
+// with the files below it, as listed by git if inGit is set. Paths that do
+// not exist are kept, so the ADRs governing a file can be looked up before
+// it is created.
+func expandPaths(root string, paths []string, inGit bool) ([]string, error) {
+	var gitFiles []string
+	var result []string
+	seen := make(map[string]bool)
+	add := func(p string) {
+		if !seen[p] {
+			seen[p] = true
+			result = append(result, p)
+		}
+	}
+
+	for _, p := range paths {
+		abs, err := filepath.Abs(p)
+		if err != nil {
+			return nil, fmt.Errorf("resolving %s: %w", p, err)
+		}
+		rel, err := filepath.Rel(root, abs)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return nil, fmt.Errorf("%s is outside the repository %s", p, root)
+		}
+		rel = filepath.ToSlash(rel)
+
+		info, err := os.Stat(abs)
+		if err != nil || !info.IsDir() {
+			add(rel)
+			continue
+		}
+
+		if inGit {
+			// git honours .gitignore
+			if gitFiles == nil {
+				if gitFiles, err = git.Files(); err != nil {
+					return nil, fmt.Errorf("listing files: %w", err)
+				}
+			}
+			for _, f := range gitFiles {
+				if rel == "." || strings.HasPrefix(f, rel+"/") {
+					add(f)
+				}
+			}
+			continue
+		}
+
+		files, err := walkFiles(root, abs)
+		if err != nil {
+			return nil, err
+		}
+		for _, f := range files {
+			add(f)
+		}
+	}
+	return result, nil
+}
+
+// walkFiles lists the files below dir relative to root, skipping hidden
+// directories.
+func walkFiles(root, dir string) ([]string, error) {
+	var files []string
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if p != dir && strings.HasPrefix(d.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return err
+		}
+		files = append(files, filepath.ToSlash(rel))
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("listing %s: %w", dir, err)
+	}
+	return files, nil
+}
@@ -0,0 +1,97 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRunWhich(t *testing.T) {
+	root := t.TempDir()
+	t.Chdir(root)
+	dir := filepath.Join(root, "docs", "adr")
+	for _, d := range []string{dir, "src/db/.cache", "src/api"} {
+		if err := os.MkdirAll(d, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, f := range []string{"src/db/query.go", "src/db/gen.go", "src/db/.cache/x", "src/api/handler.go"} {
+		if err := os.WriteFile(f, nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeTestADR(t, dir, "0001-database-access.md", `
+adr_id: ADR-0001
+title: Database Access
+status: adopted
+date: 2026-01-16
+scope:
+  paths: ["src/db/**", "!src/db/gen.go"]
+constraints:
+  - "Use the repository pattern"
+invariants:
+  - "Queries are parameterized"
+`)
+	writeTestADR(t, dir, "0002-layering.md", `
+adr_id: ADR-0002
+title: Layering
+status: proposed
+date: 2026-01-16
+scope:
+  paths: ["src/**"]
+`)
+	guidance := "## Agent Guidance\n\nNever build SQL from strings.\n"
+	path := filepath.Join(dir, "0001-database-access.md")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, append(data, guidance...), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	out := testOutput()
+	result, err := RunWhich(&WhichConfig{
+		Paths:  []string{"src/db", "./src/api/handler.go", "src/new.go"},
+		Dir:    dir,
+		Root:   root,
+		Format: FormatText,
+		Output: out,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []WhichPath{
+		{Path: "src/db/gen.go", Matches: []WhichMatch{{ADRID: "ADR-0002", Pattern: "src/**"}}},
+		{Path: "src/db/query.go", Matches: []WhichMatch{{ADRID: "ADR-0001", Pattern: "src/db/**"}, {ADRID: "ADR-0002", Pattern: "src/**"}}},
+		{Path: "src/api/handler.go", Matches: []WhichMatch{{ADRID: "ADR-0002", Pattern: "src/**"}}},
+		{Path: "src/new.go", Matches: []WhichMatch{{ADRID: "ADR-0002", Pattern: "src/**"}}},
+	}
+	if !reflect.DeepEqual(result.Paths, want) {
+		t.Errorf("Paths = %+v\nwant %+v", result.Paths, want)
+	}
+
+	if len(result.ADRs) != 2 {
+		t.Fatalf("ADRs = %+v, want ADR-0001 and ADR-0002", result.ADRs)
+	}
+	first := result.ADRs[0]
+	if first.File != "docs/adr/0001-database-access.md" || first.AgentGuidance != "Never build SQL from strings." ||
+		!reflect.DeepEqual(first.Constraints, []string{"Use the repository pattern"}) || !reflect.DeepEqual(first.Invariants, []string{"Queries are parameterized"}) {
+		t.Errorf("ADRs[0] = %+v", first)
+	}
+
+	text := out.Writer.(*bytes.Buffer).String()
+	for _, want := range []string{"src/db/query.go\n  ADR-0001 via src/db/**\n", "### Agent Guidance\nNever build SQL from strings."} {
+		if !strings.Contains(text, want) {
+			t.Errorf("output lacks %q:\n%s", want, text)
+		}
+	}
+
+	if _, err := RunWhich(&WhichConfig{Paths: []string{".."}, Dir: dir, Root: root, Format: FormatJSON, Output: testOutput()}); err == nil {
+		t.Error("path outside the repository: expected an error")
+	}
+}
@@ -0,0 +1,137 @@
+// Package config loads the optional .decider.yaml project configuration.
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"github.com/sventorben/decider/internal/adr"
+	"github.com/sventorben/decider/internal/validate"
+	"gopkg.in/yaml.v3"
+)
+
+// Filename is the name of the project configuration file.
+const Filename = ".decider.yaml"
+
+// Config holds project-wide defaults for all commands. Command-line flags
+// take precedence over values from the configuration file.
+type Config struct {
+	ADRDir           string   `yaml:"adr_dir" json:"adr_dir"`
+	Format           string   `yaml:"format" json:"format"`
+	Strict           bool     `yaml:"strict" json:"strict"`
+	RequiredSections []string `yaml:"required_sections" json:"required_sections"`
+	AllowedTags      []string `yaml:"allowed_tags" json:"allowed_tags"`
+	IDPrefix         string   `yaml:"id_prefix" json:"id_prefix"`
+	Template         string   `yaml:"template" json:"template"`
+
+	// Source is the configuration file the values were loaded from, or
+	// empty when only defaults apply.
+	Source string `yaml:"-" json:"source"`
+}
+
+var idPrefixRegex = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9]*$`)
+
+// Default returns the built-in configuration used when no file is found.
+func Default() *Config {
+	return &Config{
+		ADRDir:           "docs/adr",
+		Format:           "text",
+		Strict:           false,
+		RequiredSections: append([]string{}, adr.RequiredSections...),
+		AllowedTags:      []string{},
+		IDPrefix:         adr.DefaultIDPrefix,
+		Template:         "adr",
+	}
+}
+
+// Discover walks up from startDir looking for a configuration file and
+// returns its path, or an empty string if none exists.
+func Discover(startDir string) (string, error) {
+	dir, err := filepath.Abs(startDir)
+	if err != nil {
+		return "", fmt.Errorf("resolving %s: %w", startDir, err)
+	}
+
+	for {
+		candidate := filepath.Join(dir, Filename)
+		info, err := os.Stat(candidate)
+		if err == nil && !info.IsDir() {
+			return candidate, nil
+		}
+		if err != nil && !os.IsNotExist(err) {
+			return "", fmt.Errorf("checking %s: %w", candidate, err)
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", nil
+		}
+		dir = parent
+	}
+}
+
+// Load reads a configuration file and merges it onto the defaults. Unknown
+// keys are rejected so typos do not silently fall back to defaults. A
+// relative adr_dir is resolved against the directory containing the file.
+func Load(path string) (*Config, error) {
+	if err := validate.CheckFileSize(path); err != nil {
+		return nil, fmt.Errorf("file size check failed for %s: %w", path, err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading config file: %w", err)
+	}
+
+	cfg := Default()
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("parsing config file %s: %w", path, err)
+	}
+	cfg.Source = path
+
+	if cfg.ADRDir == "" {
+		return nil, fmt.Errorf("%s: adr_dir cannot be empty", path)
+	}
+	if !filepath.IsAbs(cfg.ADRDir) {
+		cfg.ADRDir = filepath.Join(filepath.Dir(path), cfg.ADRDir)
+	}
+	if !idPrefixRegex.MatchString(cfg.IDPrefix) {
+		return nil, fmt.Errorf("%s: id_prefix %q must start with a letter and contain only letters and digits", path, cfg.IDPrefix)
+	}
+	if err := validate.ValidateTags(cfg.AllowedTags); err != nil {
+		return nil, fmt.Errorf("%s: allowed_tags: %w", path, err)
+	}
+
+	return cfg, nil
+}
+
+// LoadFromDir discovers the configuration file starting at dir and loads it.
+// Without a file, the defaults are returned. The ADR directory is made
+// relative to dir where possible so paths in output stay short.
+func LoadFromDir(dir string) (*Config, error) {
+	path, err := Discover(dir)
+	if err != nil {
+		return nil, err
+	}
+	if path == "" {
+		return Default(), nil
+	}
+
+	cfg, err := Load(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if absDir, err := filepath.Abs(dir); err == nil {
+		if rel, err := filepath.Rel(absDir, cfg.ADRDir); err == nil {
+			cfg.ADRDir = rel
+		}
+	}
+	return cfg, nil
+}
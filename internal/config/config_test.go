@@ -0,0 +1,150 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/sventorben/decider/internal/adr"
+)
+
+func writeConfig(t *testing.T, dir, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, Filename)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("writing config: %v", err)
+	}
+	return path
+}
+
+func TestDiscoverWalksUp(t *testing.T) {
+	root := t.TempDir()
+	nested := filepath.Join(root, "a", "b")
+	if err := os.MkdirAll(nested, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := Discover(nested)
+	if err != nil {
+		t.Fatalf("Discover() error = %v", err)
+	}
+	if got != "" {
+		// A config file above the temp dir would make this test meaningless.
+		t.Skipf("found unrelated config file %s", got)
+	}
+
+	want := writeConfig(t, root, "strict: true\n")
+	got, err = Discover(nested)
+	if err != nil {
+		t.Fatalf("Discover() error = %v", err)
+	}
+	if got != want {
+		t.Errorf("Discover() = %q, want %q", got, want)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	path := writeConfig(t, dir, `adr_dir: decisions
+format: json
+strict: true
+required_sections: [Context, Decision]
+allowed_tags: [api, security]
+id_prefix: DEC
+template: lightweight
+`)
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	want := &Config{
+		ADRDir:           filepath.Join(dir, "decisions"),
+		Format:           "json",
+		Strict:           true,
+		RequiredSections: []string{"Context", "Decision"},
+		AllowedTags:      []string{"api", "security"},
+		IDPrefix:         "DEC",
+		Template:         "lightweight",
+		Source:           path,
+	}
+	if !reflect.DeepEqual(cfg, want) {
+		t.Errorf("Load() = %+v, want %+v", cfg, want)
+	}
+}
+
+func TestLoadMergesDefaults(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{"empty file", ""},
+		{"single key", "strict: true\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			cfg, err := Load(writeConfig(t, dir, tt.content))
+			if err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+			if cfg.ADRDir != filepath.Join(dir, "docs/adr") {
+				t.Errorf("ADRDir = %q", cfg.ADRDir)
+			}
+			if cfg.Format != "text" || cfg.IDPrefix != adr.DefaultIDPrefix || cfg.Template != "adr" {
+				t.Errorf("defaults not applied: %+v", cfg)
+			}
+			if !reflect.DeepEqual(cfg.RequiredSections, adr.RequiredSections) {
+				t.Errorf("RequiredSections = %v, want %v", cfg.RequiredSections, adr.RequiredSections)
+			}
+		})
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{"unknown key", "adr_directory: docs\n", "field adr_directory not found"},
+		{"invalid prefix", "id_prefix: ADR-\n", "id_prefix"},
+		{"empty adr_dir", "adr_dir: \"\"\n", "adr_dir cannot be empty"},
+		{"invalid tag", "allowed_tags: [\"Bad Tag\"]\n", "allowed_tags"},
+		{"malformed yaml", "strict: [\n", "parsing config file"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Load(writeConfig(t, t.TempDir(), tt.content))
+			if err == nil {
+				t.Fatal("Load() expected error")
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Load() error = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoadFromDirRelativeADRDir(t *testing.T) {
+	root := t.TempDir()
+	writeConfig(t, root, "adr_dir: decisions\n")
+	nested := filepath.Join(root, "services", "api")
+	if err := os.MkdirAll(nested, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadFromDir(nested)
+	if err != nil {
+		t.Fatalf("LoadFromDir() error = %v", err)
+	}
+	want := filepath.Join("..", "..", "decisions")
+	if cfg.ADRDir != want {
+		t.Errorf("ADRDir = %q, want %q", cfg.ADRDir, want)
+	}
+}
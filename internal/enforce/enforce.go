@@ -0,0 +1,288 @@
+// Package enforce evaluates machine-checkable ADR constraints against the
+// contents of changed files.
+package enforce
+
+import (
+	"fmt"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/sventorben/decider/internal/adr"
+	"github.com/sventorben/decider/internal/glob"
+	"github.com/sventorben/decider/internal/validate"
+)
+
+// Violation is a structured constraint that a changed file breaks.
+type Violation struct {
+	ADRID   string `json:"adr_id"`
+	Rule    string `json:"rule"`           // Rule kind, e.g. "forbid_import"
+	File    string `json:"file"`           // Changed file, relative to the repository root
+	Line    int    `json:"line,omitempty"` // 1-based line, or 0 if the violation is about the whole file
+	Message string `json:"message"`
+}
+
+// String formats the violation as "file:line: [ADR-ID] message".
+func (v Violation) String() string {
+	loc := v.File
+	if v.Line > 0 {
+		loc = fmt.Sprintf("%s:%d", v.File, v.Line)
+	}
+	return fmt.Sprintf("%s: [%s] %s", loc, v.ADRID, v.Message)
+}
+
+// Source reads the files that constraints are checked against, such as the
+// working tree, the index or a commit. Paths are relative to the repository
+// root.
+type Source interface {
+	// Exists reports whether there is a file at path.
+	Exists(path string) (bool, error)
+	// Read returns the contents of the file at path. It returns false if
+	// there is no such file or it exceeds the maximum file size.
+	Read(path string) ([]byte, bool, error)
+}
+
+// Dir is a Source reading files from a directory on disk.
+type Dir string
+
+// Exists reports whether there is a regular file at path below d.
+func (d Dir) Exists(path string) (bool, error) {
+	info, err := os.Stat(filepath.Join(string(d), path))
+	return err == nil && !info.IsDir(), nil
+}
+
+// Read returns the contents of the file at path below d.
+func (d Dir) Read(path string) ([]byte, bool, error) {
+	full := filepath.Join(string(d), path)
+	if ok, _ := d.Exists(path); !ok {
+		return nil, false, nil
+	}
+	if err := validate.CheckFileSize(full); err != nil {
+		return nil, false, nil
+	}
+	data, err := os.ReadFile(full)
+	if err != nil {
+		return nil, false, fmt.Errorf("reading %s: %w", path, err)
+	}
+	return data, true, nil
+}
+
+// Target is an ADR whose structured constraints are enforced.
+type Target struct {
+	ADR *adr.ADR
+
+	// Inherited are changed files the ADR governs outside its own scope,
+	// such as those in the scope of the ADRs it supersedes.
+	Inherited []string
+}
+
+// Evaluate checks the structured constraints of the targets against the
+// changed files, as read from src. Every target is enforced, whatever its
+// status; callers pick the ADRs that apply. A rule applies to the changed
+// files matching its paths, or else the files in the ADR's scope and its
+// inherited files, or every changed file when the ADR has no scope. Files
+// that no longer exist (deletions) are only checked by require_file.
+func Evaluate(targets []Target, changedFiles []string, src Source) ([]Violation, error) {
+	var violations []Violation
+	var graph *ImportGraph
+	for _, t := range targets {
+		a := t.ADR
+		for _, c := range a.Frontmatter.Constraints {
+			if !c.IsRule() {
+				continue
+			}
+			if c.Imports != nil {
+				if graph == nil {
+					var err error
+					if graph, err = BuildImportGraph(src, changedFiles); err != nil {
+						return nil, err
+					}
+				}
+				violations = append(violations, importViolations(a.Frontmatter.ADRID, c, graph, ruleFiles(t, c, changedFiles))...)
+				continue
+			}
+			vs, err := evaluateRule(a.Frontmatter.ADRID, c, ruleFiles(t, c, changedFiles), changedFiles, src)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", a.Frontmatter.ADRID, err)
+			}
+			violations = append(violations, vs...)
+		}
+	}
+	return violations, nil
+}
+
+// ruleFiles returns the changed files a rule applies to, in order.
+func ruleFiles(t Target, c adr.Constraint, changedFiles []string) []string {
+	if len(c.Paths) > 0 {
+		return glob.FilterPaths(c.Paths, changedFiles)
+	}
+	scope := t.ADR.Frontmatter.Scope.Paths
+	if len(scope) == 0 && len(t.Inherited) == 0 {
+		return changedFiles
+	}
+	var files []string
+	for _, f := range changedFiles {
+		if glob.MatchAny(scope, f) || contains(t.Inherited, f) {
+			files = append(files, f)
+		}
+	}
+	return files
+}
+
+func contains(slice []string, item string) bool {
+	for _, s := range slice {
+		if s == item {
+			return true
+		}
+	}
+	return false
+}
+
+func evaluateRule(adrID string, c adr.Constraint, files, changedFiles []string, src Source) ([]Violation, error) {
+	kind, arg := c.Rule()
+	newViolation := func(file string, line int, detail string) Violation {
+		msg := detail
+		if c.Message != "" {
+			msg = fmt.Sprintf("%s (%s)", detail, c.Message)
+		}
+		return Violation{ADRID: adrID, Rule: kind, File: file, Line: line, Message: msg}
+	}
+
+	var violations []Violation
+	switch kind {
+	case adr.RuleRequireFile:
+		var trigger string
+		for _, f := range files {
+			if !glob.Match(arg, f) {
+				trigger = f
+				break
+			}
+		}
+		if trigger != "" && len(glob.FilterPaths([]string{arg}, changedFiles)) == 0 {
+			violations = append(violations, newViolation(trigger, 0,
+				fmt.Sprintf("changes here require a change to %s", arg)))
+		}
+		return violations, nil
+
+	case adr.RuleForbidPath:
+		for _, f := range files {
+			if !glob.Match(arg, f) {
+				continue
+			}
+			ok, err := src.Exists(f)
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				violations = append(violations, newViolation(f, 0,
+					fmt.Sprintf("files matching %s must not be added or modified", arg)))
+			}
+		}
+		return violations, nil
+
+	case adr.RuleForbidRegex:
+		re, err := regexp.Compile(arg)
+		if err != nil {
+			return nil, fmt.Errorf("invalid forbid_regex: %w", err)
+		}
+		for _, f := range files {
+			data, ok, err := src.Read(f)
+			if err != nil {
+				return nil, err
+			}
+			if !ok {
+				continue
+			}
+			for i, line := range splitLines(data) {
+				if re.MatchString(line) {
+					violations = append(violations, newViolation(f, i+1,
+						fmt.Sprintf("line matches forbidden pattern %s", arg)))
+				}
+			}
+		}
+		return violations, nil
+
+	case adr.RuleForbidImport:
+		for _, f := range files {
+			data, ok, err := src.Read(f)
+			if err != nil {
+				return nil, err
+			}
+			if !ok {
+				continue
+			}
+			for _, imp := range extractImports(f, data) {
+				if matchImport(arg, imp.path) {
+					violations = append(violations, newViolation(f, imp.line,
+						fmt.Sprintf("imports forbidden package %s", imp.path)))
+				}
+			}
+		}
+		return violations, nil
+	}
+	return nil, nil
+}
+
+// matchImport reports whether an import path is forbidden by pattern: the
+// package itself, any package below it, or a glob match.
+func matchImport(pattern, path string) bool {
+	return path == pattern || strings.HasPrefix(path, pattern+"/") || glob.Match(pattern, path)
+}
+
+type importRef struct {
+	path string
+	line int
+}
+
+// jsImportRegex matches quoted module specifiers in JavaScript and
+// TypeScript import, export-from and require statements.
+var jsImportRegex = regexp.MustCompile(`(?:^\s*(?:import|export)\b[^"'\n]*?["']([^"'\n]+)["'])|(?:\brequire\(\s*["']([^"'\n]+)["']\s*\))`)
+
+// extractImports returns the imports of a Go, JavaScript or TypeScript file.
+// Other file types have no imports.
+func extractImports(file string, data []byte) []importRef {
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".go":
+		fset := token.NewFileSet()
+		parsed, err := parser.ParseFile(fset, file, data, parser.ImportsOnly)
+		if err != nil {
+			return nil
+		}
+		refs := make([]importRef, 0, len(parsed.Imports))
+		for _, spec := range parsed.Imports {
+			path, err := strconv.Unquote(spec.Path.Value)
+			if err != nil {
+				continue
+			}
+			refs = append(refs, importRef{path: path, line: fset.Position(spec.Pos()).Line})
+		}
+		return refs
+
+	case ".js", ".jsx", ".mjs", ".cjs", ".ts", ".tsx":
+		var refs []importRef
+		for i, line := range splitLines(data) {
+			for _, m := range jsImportRegex.FindAllStringSubmatch(line, -1) {
+				path := m[1]
+				if path == "" {
+					path = m[2]
+				}
+				refs = append(refs, importRef{path: path, line: i + 1})
+			}
+		}
+		return refs
+	}
+	return nil
+}
+
+// splitLines splits file contents into lines without line terminators.
+func splitLines(data []byte) []string {
+	lines := strings.Split(string(data), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSuffix(line, "\r")
+	}
+	return lines
+}
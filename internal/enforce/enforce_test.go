@@ -0,0 +1,139 @@
+package enforce
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/sventorben/decider/internal/adr"
+)
+
+func writeFiles(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func testADR(status adr.Status, scope []string, constraints ...adr.Constraint) *adr.ADR {
+	return &adr.ADR{Frontmatter: adr.Frontmatter{
+		ADRID:       "ADR-0001",
+		Status:      status,
+		Scope:       adr.Scope{Paths: scope},
+		Constraints: constraints,
+	}}
+}
+
+func TestEvaluate(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		"src/handlers/user.go": "package handlers\n\nimport (\n\t\"database/sql\"\n\t\"fmt\"\n)\n",
+		"src/handlers/team.go": "package handlers\n\n// TODO: remove\r\nvar x = 1\n",
+		"web/app.ts":           "import { db } from \"database/sql/driver\";\nconst fs = require('fs');\n",
+		"src/legacy/old.go":    "package legacy\n",
+	})
+
+	tests := []struct {
+		name      string
+		adr       *adr.ADR
+		inherited []string
+		changed   []string
+		want      []Violation
+	}{
+		{
+			name:    "forbid_import in Go",
+			adr:     testADR(adr.StatusAdopted, []string{"src/**"}, adr.Constraint{ForbidImport: "database/sql", Message: "Use repositories"}),
+			changed: []string{"src/handlers/user.go", "src/handlers/team.go"},
+			want: []Violation{{ADRID: "ADR-0001", Rule: "forbid_import", File: "src/handlers/user.go", Line: 4,
+				Message: "imports forbidden package database/sql (Use repositories)"}},
+		},
+		{
+			name:    "forbid_import prefix in TypeScript",
+			adr:     testADR(adr.StatusAdopted, nil, adr.Constraint{ForbidImport: "database/sql"}),
+			changed: []string{"web/app.ts"},
+			want: []Violation{{ADRID: "ADR-0001", Rule: "forbid_import", File: "web/app.ts", Line: 1,
+				Message: "imports forbidden package database/sql/driver"}},
+		},
+		{
+			name:    "forbid_regex",
+			adr:     testADR(adr.StatusAdopted, []string{"src/**"}, adr.Constraint{ForbidRegex: `TODO:.*remove$`}),
+			changed: []string{"src/handlers/team.go"},
+			want: []Violation{{ADRID: "ADR-0001", Rule: "forbid_regex", File: "src/handlers/team.go", Line: 3,
+				Message: "line matches forbidden pattern TODO:.*remove$"}},
+		},
+		{
+			name:    "rule paths narrow the scope",
+			adr:     testADR(adr.StatusAdopted, []string{"src/**"}, adr.Constraint{ForbidRegex: "TODO", Paths: []string{"src/legacy/**"}}),
+			changed: []string{"src/handlers/team.go"},
+		},
+		{
+			name:    "forbid_path ignores deleted files",
+			adr:     testADR(adr.StatusAdopted, nil, adr.Constraint{ForbidPath: "src/legacy/**"}),
+			changed: []string{"src/legacy/old.go", "src/legacy/deleted.go"},
+			want: []Violation{{ADRID: "ADR-0001", Rule: "forbid_path", File: "src/legacy/old.go",
+				Message: "files matching src/legacy/** must not be added or modified"}},
+		},
+		{
+			name:    "require_file missing",
+			adr:     testADR(adr.StatusAdopted, []string{"src/handlers/**"}, adr.Constraint{RequireFile: "docs/api/*.yaml"}),
+			changed: []string{"src/handlers/user.go"},
+			want: []Violation{{ADRID: "ADR-0001", Rule: "require_file", File: "src/handlers/user.go",
+				Message: "changes here require a change to docs/api/*.yaml"}},
+		},
+		{
+			name:    "require_file satisfied",
+			adr:     testADR(adr.StatusAdopted, []string{"src/handlers/**"}, adr.Constraint{RequireFile: "docs/api/*.yaml"}),
+			changed: []string{"src/handlers/user.go", "docs/api/openapi.yaml"},
+		},
+		{
+			name:    "free text is not enforced",
+			adr:     testADR(adr.StatusAdopted, []string{"src/**"}, adr.Constraint{Text: "Do not import database/sql"}),
+			changed: []string{"src/handlers/user.go"},
+		},
+		{
+			name:    "any status is enforced",
+			adr:     testADR(adr.StatusProposed, []string{"src/**"}, adr.Constraint{ForbidImport: "database/sql"}),
+			changed: []string{"src/handlers/user.go"},
+			want: []Violation{{ADRID: "ADR-0001", Rule: "forbid_import", File: "src/handlers/user.go", Line: 4,
+				Message: "imports forbidden package database/sql"}},
+		},
+		{
+			name:      "inherited files outside the scope",
+			adr:       testADR(adr.StatusAdopted, []string{"src/legacy/**"}, adr.Constraint{ForbidRegex: "TODO"}),
+			inherited: []string{"src/handlers/team.go"},
+			changed:   []string{"src/handlers/team.go", "src/handlers/user.go"},
+			want: []Violation{{ADRID: "ADR-0001", Rule: "forbid_regex", File: "src/handlers/team.go", Line: 3,
+				Message: "line matches forbidden pattern TODO"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Evaluate([]Target{{ADR: tt.adr, Inherited: tt.inherited}}, tt.changed, Dir(root))
+			if err != nil {
+				t.Fatalf("Evaluate() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Evaluate() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestViolationString(t *testing.T) {
+	v := Violation{ADRID: "ADR-0002", File: "a.go", Line: 3, Message: "bad"}
+	if got := v.String(); got != "a.go:3: [ADR-0002] bad" {
+		t.Errorf("String() = %q", got)
+	}
+	v.Line = 0
+	if got := v.String(); got != "a.go: [ADR-0002] bad" {
+		t.Errorf("String() = %q", got)
+	}
+}
@@ -0,0 +1,240 @@
+package enforce
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/sventorben/decider/internal/adr"
+	"github.com/sventorben/decider/internal/glob"
+)
+
+// ImportEdge is an import of one Go package by a file of another.
+type ImportEdge struct {
+	From string `json:"from"` // Importing package, relative to the module root
+	To   string `json:"to"`   // Imported package path
+	File string `json:"file"` // File containing the import, relative to the root
+	Line int    `json:"line"`
+}
+
+// ImportGraph holds the import edges of a set of Go files.
+type ImportGraph struct {
+	Module string       // Module path from go.mod, empty if there is none
+	Edges  []ImportEdge // In file order, then source order
+}
+
+// Packages returns the number of distinct importing packages.
+func (g *ImportGraph) Packages() int {
+	seen := make(map[string]bool)
+	for _, e := range g.Edges {
+		seen[e.From] = true
+	}
+	return len(seen)
+}
+
+// BuildImportGraph parses the imports of the given Go files, as read from
+// src. Importing packages are recorded by their directory, so rules can use
+// the same globs as scope paths. Files that cannot be read or parsed are
+// skipped.
+func BuildImportGraph(src Source, files []string) (*ImportGraph, error) {
+	module, err := modulePath(src)
+	if err != nil {
+		return nil, err
+	}
+	graph := &ImportGraph{Module: module}
+	for _, f := range files {
+		if !strings.HasSuffix(f, ".go") {
+			continue
+		}
+		data, ok, err := src.Read(f)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			continue
+		}
+		from := path.Dir(filepath.ToSlash(f))
+		if from == "." {
+			from = ""
+		}
+		for _, imp := range extractImports(f, data) {
+			graph.Edges = append(graph.Edges, ImportEdge{
+				From: from,
+				To:   imp.path,
+				File: f,
+				Line: imp.line,
+			})
+		}
+	}
+	return graph, nil
+}
+
+// relative returns the module-relative path of an import of one of the
+// module's own packages.
+func (g *ImportGraph) relative(importPath string) (string, bool) {
+	if g.Module == "" {
+		return "", false
+	}
+	if importPath == g.Module {
+		return "", true
+	}
+	return strings.CutPrefix(importPath, g.Module+"/")
+}
+
+// matchImported reports whether an imported package matches any of the globs,
+// by its import path or, for the module's own packages, its relative path.
+func (g *ImportGraph) matchImported(patterns []string, importPath string) bool {
+	if glob.MatchAny(patterns, importPath) {
+		return true
+	}
+	rel, ok := g.relative(importPath)
+	return ok && glob.MatchAny(patterns, rel)
+}
+
+// matchImporter reports whether an importing package (relative to the module
+// root) matches any of the globs, by its relative or its full import path.
+func (g *ImportGraph) matchImporter(patterns []string, pkg string) bool {
+	if glob.MatchAny(patterns, pkg) {
+		return true
+	}
+	if g.Module == "" {
+		return false
+	}
+	return glob.MatchAny(patterns, path.Join(g.Module, pkg))
+}
+
+// displayImport returns the import path as shown in violations.
+func (g *ImportGraph) displayImport(importPath string) string {
+	if rel, ok := g.relative(importPath); ok && rel != "" {
+		return rel
+	}
+	return importPath
+}
+
+// CheckImports evaluates the imports rules of the given ADRs against the
+// graph. Every ADR given is enforced, whatever its status; callers pick the
+// ADRs that apply. A rule only considers edges from files matching its
+// paths, or the ADR's scope paths when it has none.
+func CheckImports(adrs []*adr.ADR, graph *ImportGraph) []Violation {
+	files := make([]string, 0, len(graph.Edges))
+	for _, e := range graph.Edges {
+		if len(files) == 0 || files[len(files)-1] != e.File {
+			files = append(files, e.File)
+		}
+	}
+
+	var violations []Violation
+	for _, a := range adrs {
+		for _, c := range a.Frontmatter.Constraints {
+			if c.Imports == nil {
+				continue
+			}
+			violations = append(violations, importViolations(a.Frontmatter.ADRID, c, graph, ruleFiles(Target{ADR: a}, c, files))...)
+		}
+	}
+	return violations
+}
+
+// importViolations returns the edges from the given files that break an
+// imports rule.
+func importViolations(adrID string, c adr.Constraint, graph *ImportGraph, files []string) []Violation {
+	inScope := make(map[string]bool, len(files))
+	for _, f := range files {
+		inScope[f] = true
+	}
+
+	rule := c.Imports
+	var violations []Violation
+	for _, e := range graph.Edges {
+		if !inScope[e.File] || !graph.matchImported(rule.To, e.To) {
+			continue
+		}
+
+		var detail string
+		switch {
+		case len(rule.ForbidFrom) > 0 && graph.matchImporter(rule.ForbidFrom, e.From):
+			detail = fmt.Sprintf("%s must not import %s", displayPackage(e.From), graph.displayImport(e.To))
+		case len(rule.AllowFrom) > 0 && !graph.matchImporter(rule.AllowFrom, e.From):
+			detail = fmt.Sprintf("%s imports %s, which only %s may import",
+				displayPackage(e.From), graph.displayImport(e.To), strings.Join(rule.AllowFrom, ", "))
+		default:
+			continue
+		}
+		if c.Message != "" {
+			detail = fmt.Sprintf("%s (%s)", detail, c.Message)
+		}
+		violations = append(violations, Violation{
+			ADRID:   adrID,
+			Rule:    adr.RuleImports,
+			File:    e.File,
+			Line:    e.Line,
+			Message: detail,
+		})
+	}
+	return violations
+}
+
+func displayPackage(pkg string) string {
+	if pkg == "" {
+		return "."
+	}
+	return pkg
+}
+
+// modulePath returns the module path declared in the go.mod at the root of
+// src, or an empty string if there is no go.mod.
+func modulePath(src Source) (string, error) {
+	data, ok, err := src.Read("go.mod")
+	if err != nil || !ok {
+		return "", err
+	}
+	for _, line := range splitLines(data) {
+		fields := strings.Fields(line)
+		if len(fields) >= 2 && fields[0] == "module" {
+			return strings.Trim(fields[1], `"`), nil
+		}
+	}
+	return "", nil
+}
+
+// skippedDirs are never searched for Go files.
+var skippedDirs = map[string]bool{
+	"vendor":       true,
+	"testdata":     true,
+	"node_modules": true,
+}
+
+// FindGoFiles returns all Go files below root as slash-separated paths
+// relative to root, in lexical order. Hidden, vendor and testdata
+// directories are skipped.
+func FindGoFiles(root string) ([]string, error) {
+	var files []string
+	err := filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			name := d.Name()
+			if p != root && (strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") || skippedDirs[name]) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if strings.HasSuffix(p, ".go") {
+			rel, err := filepath.Rel(root, p)
+			if err != nil {
+				return err
+			}
+			files = append(files, filepath.ToSlash(rel))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("finding Go files: %w", err)
+	}
+	sort.Strings(files)
+	return files, nil
+}
@@ -0,0 +1,93 @@
+package enforce
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/sventorben/decider/internal/adr"
+)
+
+func importsADR(scope []string, rule adr.ImportRule) *adr.ADR {
+	return testADR(adr.StatusAdopted, scope, adr.Constraint{Imports: &rule})
+}
+
+func TestCheckImports(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		"go.mod":                   "module example.com/app\n\ngo 1.22\n",
+		"main.go":                  "package main\n\nimport \"example.com/app/internal/api\"\n",
+		"internal/db/db.go":        "package db\n\nimport \"database/sql\"\n",
+		"internal/api/api.go":      "package api\n\nimport (\n\t\"database/sql\"\n\n\t\"example.com/app/internal/db\"\n)\n",
+		"internal/api/api_test.go": "package api\n\nimport \"testing\"\n",
+	})
+	files, err := FindGoFiles(root)
+	if err != nil {
+		t.Fatalf("FindGoFiles() error = %v", err)
+	}
+	wantFiles := []string{"internal/api/api.go", "internal/api/api_test.go", "internal/db/db.go", "main.go"}
+	if !reflect.DeepEqual(files, wantFiles) {
+		t.Fatalf("FindGoFiles() = %v, want %v", files, wantFiles)
+	}
+
+	graph, err := BuildImportGraph(Dir(root), files)
+	if err != nil {
+		t.Fatalf("BuildImportGraph() error = %v", err)
+	}
+	if graph.Module != "example.com/app" || len(graph.Edges) != 5 || graph.Packages() != 3 {
+		t.Fatalf("graph = %+v", graph)
+	}
+
+	tests := []struct {
+		name string
+		adr  *adr.ADR
+		want []Violation
+	}{
+		{
+			name: "allow_from",
+			adr:  importsADR(nil, adr.ImportRule{To: []string{"database/sql"}, AllowFrom: []string{"internal/db/**"}}),
+			want: []Violation{{ADRID: "ADR-0001", Rule: "imports", File: "internal/api/api.go", Line: 4,
+				Message: "internal/api imports database/sql, which only internal/db/** may import"}},
+		},
+		{
+			name: "forbid_from with module-relative target",
+			adr:  importsADR(nil, adr.ImportRule{To: []string{"internal/db"}, ForbidFrom: []string{"internal/api"}}),
+			want: []Violation{{ADRID: "ADR-0001", Rule: "imports", File: "internal/api/api.go", Line: 6,
+				Message: "internal/api must not import internal/db"}},
+		},
+		{
+			name: "full import paths",
+			adr:  importsADR(nil, adr.ImportRule{To: []string{"example.com/app/internal/api"}, ForbidFrom: []string{"example.com/app"}}),
+			want: []Violation{{ADRID: "ADR-0001", Rule: "imports", File: "main.go", Line: 3,
+				Message: ". must not import internal/api"}},
+		},
+		{
+			name: "scope limits checked files",
+			adr:  importsADR([]string{"internal/db/**"}, adr.ImportRule{To: []string{"database/sql"}, AllowFrom: []string{"internal/db/**"}}),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := CheckImports([]*adr.ADR{tt.adr}, graph)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CheckImports() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEvaluateImportsRule(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		"svc/handler.go": "package svc\n\nimport \"database/sql\"\n",
+	})
+
+	a := importsADR([]string{"svc/**"}, adr.ImportRule{To: []string{"database/sql"}, AllowFrom: []string{"db/**"}})
+	got, err := Evaluate([]Target{{ADR: a}}, []string{"svc/handler.go"}, Dir(root))
+	if err != nil {
+		t.Fatalf("Evaluate() error = %v", err)
+	}
+	if len(got) != 1 || got[0].Rule != adr.RuleImports || got[0].Line != 3 {
+		t.Errorf("Evaluate() = %+v", got)
+	}
+}
@@ -0,0 +1,397 @@
+// Package git lists changed files by running the git command line tool.
+package git
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/sventorben/decider/internal/validate"
+)
+
+// Range selects which changes to list.
+//
+//   - Base only: the working tree against Base (git diff BASE)
+//   - Base and Head: the commits between them (git diff BASE HEAD)
+//   - Staged: the index against HEAD, or against Base when set (git diff --cached)
+//   - WorkingTree: unstaged changes to tracked files (git diff)
+type Range struct {
+	Base        string
+	Head        string
+	Staged      bool
+	WorkingTree bool
+}
+
+// args returns the git diff arguments for the range.
+func (r Range) args() ([]string, error) {
+	for _, ref := range []string{r.Base, r.Head} {
+		if ref == "" {
+			continue
+		}
+		if err := validate.ValidateGitRef(ref); err != nil {
+			return nil, fmt.Errorf("invalid git ref: %w", err)
+		}
+	}
+
+	args := []string{"diff", "--name-status", "-z", "-M"}
+	switch {
+	case r.Staged && r.WorkingTree:
+		return nil, fmt.Errorf("staged and working tree changes cannot be combined")
+	case r.Head != "" && r.Base == "":
+		return nil, fmt.Errorf("a head ref requires a base ref")
+	case r.Head != "" && (r.Staged || r.WorkingTree):
+		return nil, fmt.Errorf("a head ref cannot be combined with staged or working tree changes")
+	case r.WorkingTree && r.Base != "":
+		return nil, fmt.Errorf("working tree changes are relative to the index, not a base ref")
+	case r.Staged:
+		args = append(args, "--cached")
+		if r.Base != "" {
+			args = append(args, r.Base)
+		}
+	case r.WorkingTree:
+	case r.Base == "":
+		return nil, fmt.Errorf("a base ref is required")
+	default:
+		args = append(args, r.Base)
+		if r.Head != "" {
+			args = append(args, r.Head)
+		}
+	}
+	// Keep refs from being read as paths
+	return append(args, "--"), nil
+}
+
+// Snapshot returns the files that the changes in r lead to: the index for
+// staged changes, or the head commit of a commit range. It is nil when the
+// changes lead to the working tree.
+func (r Range) Snapshot() *Snapshot {
+	switch {
+	case r.Staged:
+		return &Snapshot{}
+	case r.Head != "":
+		return &Snapshot{Rev: r.Head}
+	}
+	return nil
+}
+
+// Snapshot reads files as they are in a commit, or in the index when Rev is
+// empty. Paths are relative to the repository root.
+type Snapshot struct {
+	Rev string
+}
+
+// object names the blob of path, e.g. "HEAD:src/a.go", or ":src/a.go" in
+// the index.
+func (s *Snapshot) object(path string) string {
+	return s.Rev + ":" + filepath.ToSlash(path)
+}
+
+// stat returns the size of the file at path, or false if there is no such
+// file in the snapshot.
+func (s *Snapshot) stat(path string) (int64, bool, error) {
+	if s.Rev != "" {
+		if err := validate.ValidateGitRef(s.Rev); err != nil {
+			return 0, false, fmt.Errorf("invalid git ref: %w", err)
+		}
+	}
+	output, err := runInput(strings.NewReader(s.object(path)+"\n"), "cat-file", "--batch-check")
+	if err != nil {
+		return 0, false, err
+	}
+	// "<object> <type> <size>", or "<name> missing" when there is none
+	fields := strings.Fields(string(output))
+	if len(fields) != 3 || fields[1] != "blob" {
+		return 0, false, nil
+	}
+	size, err := strconv.ParseInt(fields[2], 10, 64)
+	if err != nil {
+		return 0, false, fmt.Errorf("unexpected git cat-file output %q", output)
+	}
+	return size, true, nil
+}
+
+// Exists reports whether the snapshot has a file at path.
+func (s *Snapshot) Exists(path string) (bool, error) {
+	_, ok, err := s.stat(path)
+	return ok, err
+}
+
+// Read returns the contents of the file at path. It returns false if there
+// is no such file in the snapshot or it exceeds the maximum file size.
+func (s *Snapshot) Read(path string) ([]byte, bool, error) {
+	size, ok, err := s.stat(path)
+	if err != nil || !ok || size > validate.MaxFileSizeBytes {
+		return nil, false, err
+	}
+	data, err := run("cat-file", "blob", s.object(path))
+	if err != nil {
+		return nil, false, err
+	}
+	return data, true, nil
+}
+
+// Files returns the files directly in dir, a directory relative to the
+// repository root, sorted.
+func (s *Snapshot) Files(dir string) ([]string, error) {
+	dir = path.Clean(filepath.ToSlash(dir))
+	spec := ":(top)" + dir + "/"
+	if dir == "." {
+		spec = ":/"
+	}
+	var output []byte
+	var err error
+	if s.Rev == "" {
+		output, err = run("ls-files", "-z", "--cached", "--full-name", "--", spec)
+	} else {
+		if err := validate.ValidateGitRef(s.Rev); err != nil {
+			return nil, fmt.Errorf("invalid git ref: %w", err)
+		}
+		output, err = run("ls-tree", "-z", "-r", "--name-only", "--full-tree", s.Rev, "--", spec)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	files := []string{}
+	for _, f := range splitNul(string(output)) {
+		// Files with merge conflicts are listed once per stage
+		if path.Dir(f) == dir && (len(files) == 0 || files[len(files)-1] != f) {
+			files = append(files, f)
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// ChangeType is how a file changed.
+type ChangeType string
+
+// Change types, from git's name-status letters. Copies are reported as
+// added files and type changes as modified ones.
+const (
+	Added    ChangeType = "added"
+	Modified ChangeType = "modified"
+	Deleted  ChangeType = "deleted"
+	Renamed  ChangeType = "renamed"
+)
+
+// Change is a changed file. Paths are relative to the repository root and
+// in slash form; OldPath is only set for renames.
+type Change struct {
+	Path    string
+	OldPath string
+	Type    ChangeType
+}
+
+// Changes returns the files changed in r, with renames detected.
+func Changes(r Range) ([]Change, error) {
+	args, err := r.args()
+	if err != nil {
+		return nil, err
+	}
+	output, err := run(args...)
+	if err != nil {
+		return nil, err
+	}
+	return parseNameStatus(string(output))
+}
+
+// parseNameStatus parses the output of git diff --name-status -z: a status
+// field followed by one path, or two for renames and copies.
+func parseNameStatus(output string) ([]Change, error) {
+	fields := strings.Split(strings.TrimSuffix(output, "\x00"), "\x00")
+	if len(fields) == 1 && fields[0] == "" {
+		return nil, nil
+	}
+
+	var changes []Change
+	for i := 0; i < len(fields); {
+		status := fields[i]
+		paths := 1
+		if status != "" && (status[0] == 'R' || status[0] == 'C') {
+			paths = 2
+		}
+		if status == "" || i+paths >= len(fields) {
+			return nil, fmt.Errorf("unexpected git diff output %q", output)
+		}
+
+		c := Change{Path: filepath.ToSlash(fields[i+paths])}
+		switch status[0] {
+		case 'A', 'C':
+			c.Type = Added
+		case 'D':
+			c.Type = Deleted
+		case 'R':
+			c.Type = Renamed
+			c.OldPath = filepath.ToSlash(fields[i+1])
+		default:
+			c.Type = Modified
+		}
+		changes = append(changes, c)
+		i += paths + 1
+	}
+	return changes, nil
+}
+
+// Root returns the top-level directory of the current repository.
+func Root() (string, error) {
+	output, err := run("rev-parse", "--show-toplevel")
+	if err != nil {
+		return "", fmt.Errorf("finding git repository root: %w", err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// Files returns the files in the working tree that git tracks or, honouring
+// .gitignore, would track, relative to the repository root and sorted.
+// Tracked files deleted from the working tree are left out.
+func Files() ([]string, error) {
+	listed, err := run("ls-files", "-z", "--cached", "--others", "--exclude-standard", "--full-name", "--", ":/")
+	if err != nil {
+		return nil, err
+	}
+	deleted, err := run("ls-files", "-z", "--deleted", "--full-name", "--", ":/")
+	if err != nil {
+		return nil, err
+	}
+
+	gone := make(map[string]bool)
+	for _, f := range splitNul(string(deleted)) {
+		gone[f] = true
+	}
+	files := []string{}
+	for _, f := range splitNul(string(listed)) {
+		// Files with merge conflicts are listed once per stage
+		if !gone[f] && (len(files) == 0 || files[len(files)-1] != f) {
+			files = append(files, f)
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+func splitNul(output string) []string {
+	if output == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(output, "\x00"), "\x00")
+}
+
+// ReadFileList reads paths, one per line, as written by git diff --name-only
+// or find. Blank lines are skipped and paths are cleaned. Paths must be
+// relative and stay inside the directory they are relative to.
+func ReadFileList(r io.Reader) ([]string, error) {
+	files := []string{}
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		f := path.Clean(filepath.ToSlash(line))
+		if filepath.IsAbs(line) || path.IsAbs(f) || f == ".." || strings.HasPrefix(f, "../") {
+			return nil, fmt.Errorf("reading file list: path %q must be relative and inside the repository", line)
+		}
+		files = append(files, f)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading file list: %w", err)
+	}
+	return files, nil
+}
+
+// HooksDir returns the directory git runs hooks from, which honours
+// core.hooksPath.
+func HooksDir() (string, error) {
+	output, err := run("rev-parse", "--git-path", "hooks")
+	if err != nil {
+		return "", fmt.Errorf("finding git hooks directory: %w", err)
+	}
+	return filepath.Abs(strings.TrimSpace(string(output)))
+}
+
+// Trailers returns the values of the trailers named key in a commit
+// message, as parsed by git interpret-trailers. Keys match case-insensitively.
+func Trailers(message, key string) ([]string, error) {
+	output, err := runInput(strings.NewReader(message), "interpret-trailers", "--parse")
+	if err != nil {
+		return nil, err
+	}
+	return trailerValues(string(output), key), nil
+}
+
+// CommitTrailers are the values of one commit's trailers.
+type CommitTrailers struct {
+	Commit string
+	Values []string
+}
+
+// RangeTrailers returns, for each commit reachable from head but not from
+// base, the values of its trailers named key. Keys match case-insensitively.
+func RangeTrailers(base, head, key string) ([]CommitTrailers, error) {
+	for _, ref := range []string{base, head} {
+		if err := validate.ValidateGitRef(ref); err != nil {
+			return nil, fmt.Errorf("invalid git ref: %w", err)
+		}
+	}
+	output, err := run("log", "-z", "--format=%H%n%(trailers:key="+key+",valueonly)", base+".."+head, "--")
+	if err != nil {
+		return nil, err
+	}
+
+	var commits []CommitTrailers
+	for _, record := range strings.Split(string(output), "\x00") {
+		lines := strings.Split(strings.TrimSpace(record), "\n")
+		if lines[0] == "" {
+			continue
+		}
+		c := CommitTrailers{Commit: lines[0]}
+		for _, v := range lines[1:] {
+			if v = strings.TrimSpace(v); v != "" {
+				c.Values = append(c.Values, v)
+			}
+		}
+		commits = append(commits, c)
+	}
+	return commits, nil
+}
+
+// trailerValues picks the values of key from "Key: value" lines.
+func trailerValues(output, key string) []string {
+	var values []string
+	for _, line := range strings.Split(output, "\n") {
+		k, v, ok := strings.Cut(line, ":")
+		if ok && strings.EqualFold(strings.TrimSpace(k), key) {
+			if v = strings.TrimSpace(v); v != "" {
+				values = append(values, v)
+			}
+		}
+	}
+	return values
+}
+
+func run(args ...string) ([]byte, error) {
+	return runInput(nil, args...)
+}
+
+func runInput(stdin io.Reader, args ...string) ([]byte, error) {
+	var stderr bytes.Buffer
+	cmd := exec.Command("git", args...)
+	cmd.Stdin = stdin
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("git %s failed: %s", args[0], msg)
+		}
+		return nil, fmt.Errorf("git %s failed: %w", args[0], err)
+	}
+	return output, nil
+}
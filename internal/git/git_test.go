@@ -0,0 +1,321 @@
+package git
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// initRepo creates a repository with two commits, staged changes and an
+// unstaged change, and makes it the working directory.
+//
+//	HEAD~1: a.go, gone.go, src/db/x.go
+//	HEAD:   src/b.go added
+//	index:  c.go added, gone.go deleted, src/db/x.go renamed to legacy/x.go
+//	tree:   a.go modified
+func initRepo(t *testing.T) {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	dir := t.TempDir()
+	t.Chdir(dir)
+	for _, kv := range [][2]string{
+		{"GIT_AUTHOR_NAME", "test"}, {"GIT_AUTHOR_EMAIL", "test@example.com"},
+		{"GIT_COMMITTER_NAME", "test"}, {"GIT_COMMITTER_EMAIL", "test@example.com"},
+		{"GIT_CONFIG_GLOBAL", os.DevNull}, {"GIT_CONFIG_NOSYSTEM", "1"},
+	} {
+		t.Setenv(kv[0], kv[1])
+	}
+
+	git := func(args ...string) {
+		t.Helper()
+		if out, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+			t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, out)
+		}
+	}
+	write := func(name, content string) {
+		t.Helper()
+		if err := os.MkdirAll(filepath.Dir(name), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(name, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	git("init", "-q")
+	write("a.go", "package a\n")
+	write("gone.go", "package gone\n")
+	write("src/db/x.go", "package db\n\nfunc X() {}\n")
+	git("add", ".")
+	git("commit", "-q", "-m", "first")
+	write("src/b.go", "package b\n")
+	git("add", ".")
+	git("commit", "-q", "-m", "second")
+	write("c.go", "package c\n")
+	git("add", "c.go")
+	git("rm", "-q", "gone.go")
+	if err := os.Mkdir("legacy", 0755); err != nil {
+		t.Fatal(err)
+	}
+	git("mv", "src/db/x.go", "legacy/x.go")
+	write("a.go", "package a // changed\n")
+}
+
+func TestChanges(t *testing.T) {
+	initRepo(t)
+
+	var (
+		a       = Change{Path: "a.go", Type: Modified}
+		b       = Change{Path: "src/b.go", Type: Added}
+		c       = Change{Path: "c.go", Type: Added}
+		gone    = Change{Path: "gone.go", Type: Deleted}
+		renamed = Change{Path: "legacy/x.go", OldPath: "src/db/x.go", Type: Renamed}
+	)
+	tests := []struct {
+		name    string
+		r       Range
+		changes []Change
+	}{
+		{"base against working tree", Range{Base: "HEAD~1"}, []Change{a, c, gone, renamed, b}},
+		{"base..head", Range{Base: "HEAD~1", Head: "HEAD"}, []Change{b}},
+		{"staged", Range{Staged: true}, []Change{c, gone, renamed}},
+		{"staged against base", Range{Base: "HEAD~1", Staged: true}, []Change{c, gone, renamed, b}},
+		{"working tree", Range{WorkingTree: true}, []Change{a}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changes, err := Changes(tt.r)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(changes, tt.changes) {
+				t.Errorf("Changes() = %+v, want %+v", changes, tt.changes)
+			}
+		})
+	}
+
+	if _, err := Changes(Range{Base: "no-such-ref"}); err == nil || !strings.Contains(err.Error(), "git diff failed") {
+		t.Errorf("unknown ref: err = %v", err)
+	}
+	root, err := Root()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if wd, _ := os.Getwd(); !sameDir(t, root, wd) {
+		t.Errorf("Root() = %q, want %q", root, wd)
+	}
+}
+
+func TestFiles(t *testing.T) {
+	initRepo(t)
+	for name, content := range map[string]string{".gitignore": "*.log\n", "build.log": "", "notes.txt": ""} {
+		if err := os.WriteFile(name, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Remove("a.go"); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir("src")
+
+	files, err := Files()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{".gitignore", "c.go", "legacy/x.go", "notes.txt", "src/b.go"}
+	if !reflect.DeepEqual(files, want) {
+		t.Errorf("Files() = %q, want %q", files, want)
+	}
+}
+
+func TestSnapshot(t *testing.T) {
+	initRepo(t)
+	t.Chdir("src")
+
+	tests := []struct {
+		name    string
+		r       Range
+		path    string
+		content string // Empty if the file does not exist
+	}{
+		{"index", Range{Staged: true}, "a.go", "package a\n"},
+		{"index added", Range{Staged: true}, "c.go", "package c\n"},
+		{"index deleted", Range{Staged: true}, "gone.go", ""},
+		{"head", Range{Base: "HEAD~1", Head: "HEAD"}, "src/b.go", "package b\n"},
+		{"head lacks staged file", Range{Base: "HEAD~1", Head: "HEAD"}, "c.go", ""},
+		{"directory", Range{Staged: true}, "src", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := tt.r.Snapshot()
+			data, ok, err := s.Read(tt.path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(data) != tt.content || ok != (tt.content != "") {
+				t.Errorf("Read(%q) = %q, %v, want %q", tt.path, data, ok, tt.content)
+			}
+			if exists, err := s.Exists(tt.path); err != nil || exists != ok {
+				t.Errorf("Exists(%q) = %v, %v, want %v", tt.path, exists, err, ok)
+			}
+		})
+	}
+
+	files := []struct {
+		s    *Snapshot
+		dir  string
+		want []string
+	}{
+		{&Snapshot{}, ".", []string{"a.go", "c.go"}},
+		{&Snapshot{}, "legacy", []string{"legacy/x.go"}},
+		{&Snapshot{Rev: "HEAD"}, ".", []string{"a.go", "gone.go"}},
+		{&Snapshot{Rev: "HEAD"}, "src/", []string{"src/b.go"}},
+		{&Snapshot{Rev: "HEAD~1"}, "src", []string{}},
+	}
+	for _, tt := range files {
+		got, err := tt.s.Files(tt.dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%+v.Files(%q) = %q, want %q", tt.s, tt.dir, got, tt.want)
+		}
+	}
+
+	for _, r := range []Range{{Base: "HEAD"}, {WorkingTree: true}} {
+		if s := r.Snapshot(); s != nil {
+			t.Errorf("%+v.Snapshot() = %+v, want nil for the working tree", r, s)
+		}
+	}
+}
+
+func sameDir(t *testing.T, a, b string) bool {
+	t.Helper()
+	ia, err1 := os.Stat(a)
+	ib, err2 := os.Stat(b)
+	return err1 == nil && err2 == nil && os.SameFile(ia, ib)
+}
+
+func TestParseNameStatus(t *testing.T) {
+	changes, err := parseNameStatus("M\x00a.go\x00R087\x00old.go\x00new.go\x00C100\x00a.go\x00copy.go\x00T\x00link\x00")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Change{
+		{Path: "a.go", Type: Modified},
+		{Path: "new.go", OldPath: "old.go", Type: Renamed},
+		{Path: "copy.go", Type: Added},
+		{Path: "link", Type: Modified},
+	}
+	if !reflect.DeepEqual(changes, want) {
+		t.Errorf("parseNameStatus() = %+v, want %+v", changes, want)
+	}
+
+	if changes, err := parseNameStatus(""); err != nil || changes != nil {
+		t.Errorf("empty output = %v, %v", changes, err)
+	}
+	if _, err := parseNameStatus("R100\x00old.go\x00"); err == nil {
+		t.Error("truncated rename: expected an error")
+	}
+}
+
+func TestRangeErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		r    Range
+		err  string
+	}{
+		{"nothing", Range{}, "base ref is required"},
+		{"head without base", Range{Head: "HEAD"}, "requires a base ref"},
+		{"staged and working tree", Range{Staged: true, WorkingTree: true}, "cannot be combined"},
+		{"head and staged", Range{Base: "main", Head: "HEAD", Staged: true}, "cannot be combined"},
+		{"working tree and base", Range{Base: "main", WorkingTree: true}, "relative to the index"},
+		{"flag as ref", Range{Base: "--output=x"}, "invalid git ref"},
+		{"flag as head", Range{Base: "main", Head: "-p"}, "invalid git ref"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.r.args()
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("args() error = %v, want %q", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestReadFileList(t *testing.T) {
+	files, err := ReadFileList(strings.NewReader("./src/a.go\n\n  docs/b.md  \r\nc.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"src/a.go", "docs/b.md", "c.go"}
+	if !reflect.DeepEqual(files, want) {
+		t.Errorf("ReadFileList() = %v, want %v", files, want)
+	}
+
+	files, err = ReadFileList(strings.NewReader(""))
+	if err != nil || files == nil || len(files) != 0 {
+		t.Errorf("empty list = %#v, %v, want an empty non-nil slice", files, err)
+	}
+
+	files, err = ReadFileList(strings.NewReader("src//db/../a.go\nsrc/./b.go"))
+	if want := []string{"src/a.go", "src/b.go"}; err != nil || !reflect.DeepEqual(files, want) {
+		t.Errorf("ReadFileList() = %v, %v, want %v", files, err, want)
+	}
+	for _, outside := range []string{"../x.go", "src/../../x.go", "..", "/etc/passwd"} {
+		if files, err := ReadFileList(strings.NewReader("a.go\n" + outside)); err == nil {
+			t.Errorf("ReadFileList(%q) = %v, want an error", outside, files)
+		}
+	}
+}
+
+func TestTrailers(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	message := "Move queries\n\nADR-Ack: ADR-0001 mentioned in the body does not count.\n\n" +
+		"Signed-off-by: Someone <someone@example.com>\nadr-ack: ADR-0001, ADR-0004\nADR-Ack: ADR-0007\n"
+	values, err := Trailers(message, "ADR-Ack")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"ADR-0001, ADR-0004", "ADR-0007"}
+	if !reflect.DeepEqual(values, want) {
+		t.Errorf("Trailers() = %q, want %q", values, want)
+	}
+}
+
+func TestRangeTrailers(t *testing.T) {
+	initRepo(t)
+	commit := func(message string) {
+		t.Helper()
+		if out, err := exec.Command("git", "commit", "-q", "--allow-empty", "-m", message).CombinedOutput(); err != nil {
+			t.Fatalf("git commit: %v\n%s", err, out)
+		}
+	}
+	commit("Move queries\n\nADR-Ack: ADR-0001, ADR-0004\nadr-ack: ADR-0007")
+	commit("Fix typo")
+
+	commits, err := RangeTrailers("HEAD~2", "HEAD", "ADR-Ack")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(commits) != 2 {
+		t.Fatalf("RangeTrailers() = %+v, want 2 commits", commits)
+	}
+	if commits[0].Values != nil || len(commits[0].Commit) != 40 {
+		t.Errorf("latest commit = %+v, want no values", commits[0])
+	}
+	if want := []string{"ADR-0001, ADR-0004", "ADR-0007"}; !reflect.DeepEqual(commits[1].Values, want) {
+		t.Errorf("values = %q, want %q", commits[1].Values, want)
+	}
+
+	if _, err := RangeTrailers("-p", "HEAD", "ADR-Ack"); err == nil {
+		t.Error("RangeTrailers(-p) error = nil, want invalid ref")
+	}
+}
@@ -2,6 +2,7 @@
 package glob
 
 import (
+	"fmt"
 	pathpkg "path"
 	"path/filepath"
 	"strings"
@@ -10,9 +11,16 @@ import (
 // MaxGlobDepth limits the number of ** segments to prevent pathological patterns.
 const MaxGlobDepth = 10
 
+// maxAlternatives limits the number of patterns a brace expression may
+// expand to.
+const maxAlternatives = 256
+
 // Match checks if a path matches a glob pattern.
-// Supports standard glob patterns including ** for recursive matching.
-// Returns false for patterns with too many ** segments (DoS protection).
+// Supports *, ?, character classes ([abc], [a-z], [!abc] or [^abc]), brace
+// alternatives ({api,web}, which may nest) and ** for recursive matching.
+// Patterns are matched segment by segment, so only ** crosses a '/'.
+// Returns false for patterns with too many ** segments (DoS protection) and
+// for malformed patterns.
 func Match(pattern, path string) bool {
 	// Normalize path separators (handle both OS-native and explicit backslashes)
 	pattern = filepath.ToSlash(pattern)
@@ -26,17 +34,92 @@ func Match(pattern, path string) bool {
 		return false
 	}
 
-	// Handle ** patterns specially
-	if strings.Contains(pattern, "**") {
-		return matchDoublestar(pattern, path)
+	alternatives, err := expandBraces(pattern)
+	if err != nil {
+		return false
+	}
+	for _, alt := range alternatives {
+		if matchDoublestar(alt, path) {
+			return true
+		}
 	}
+	return false
+}
 
-	// Use path.Match for simple patterns (uses / as separator consistently)
-	matched, err := pathpkg.Match(pattern, path)
+// ValidatePattern reports whether pattern, optionally prefixed with "!" for
+// an exclusion, is a well-formed scope pattern.
+func ValidatePattern(pattern string) error {
+	pattern = strings.TrimPrefix(pattern, "!")
+	if pattern == "" {
+		return fmt.Errorf("empty pattern")
+	}
+	if strings.Count(pattern, "**") > MaxGlobDepth {
+		return fmt.Errorf("more than %d '**' segments", MaxGlobDepth)
+	}
+	alternatives, err := expandBraces(filepath.ToSlash(pattern))
 	if err != nil {
-		return false
+		return err
+	}
+	for _, alt := range alternatives {
+		for _, part := range strings.Split(alt, "/") {
+			if _, err := pathpkg.Match(classes(part), ""); err != nil {
+				return fmt.Errorf("malformed pattern %q", pattern)
+			}
+		}
 	}
-	return matched
+	return nil
+}
+
+// expandBraces expands brace alternatives, so that "src/{api,web}/**"
+// becomes "src/api/**" and "src/web/**". Braces may nest.
+func expandBraces(pattern string) ([]string, error) {
+	start := strings.IndexByte(pattern, '{')
+	if start < 0 {
+		return []string{pattern}, nil
+	}
+
+	// Find the matching '}' and the commas at this level
+	depth, end := 0, -1
+	var commas []int
+	for i := start; i < len(pattern) && end < 0; i++ {
+		switch pattern[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				end = i
+			}
+		case ',':
+			if depth == 1 {
+				commas = append(commas, i)
+			}
+		}
+	}
+	if end < 0 {
+		return nil, fmt.Errorf("unclosed '{' in pattern %q", pattern)
+	}
+
+	var result []string
+	prev := start + 1
+	for _, sep := range append(commas, end) {
+		expanded, err := expandBraces(pattern[:start] + pattern[prev:sep] + pattern[end+1:])
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, expanded...)
+		if len(result) > maxAlternatives {
+			return nil, fmt.Errorf("pattern %q expands to more than %d alternatives", pattern, maxAlternatives)
+		}
+		prev = sep + 1
+	}
+	return result, nil
+}
+
+// classes rewrites negated character classes from the gitignore form [!a]
+// to the [^a] form path.Match understands.
+func classes(segment string) string {
+	return strings.ReplaceAll(segment, "[!", "[^")
 }
 
 // matchDoublestar handles patterns with ** (recursive directory matching).
@@ -69,7 +152,7 @@ func matchParts(pattern, path []string) bool {
 		}
 
 		// Match single part with potential wildcards
-		matched, err := pathpkg.Match(pattern[pi], path[pathi])
+		matched, err := pathpkg.Match(classes(pattern[pi]), path[pathi])
 		if err != nil || !matched {
 			return false
 		}
@@ -89,17 +172,40 @@ func matchParts(pattern, path []string) bool {
 	return pathi == len(path)
 }
 
-// MatchAny checks if a path matches any of the given patterns.
-func MatchAny(patterns []string, path string) bool {
+// ScopeMatch describes how a path relates to a list of scope patterns.
+type ScopeMatch struct {
+	Matched    bool   // The path is in scope
+	Pattern    string // The last inclusion pattern that matched the path
+	ExcludedBy string // The exclusion, with its '!', that took the path out of scope
+}
+
+// MatchScope matches a path against scope patterns with gitignore-style
+// semantics: patterns prefixed with '!' exclude paths that earlier patterns
+// included, and the last pattern that matches decides.
+func MatchScope(patterns []string, path string) ScopeMatch {
+	var m ScopeMatch
 	for _, pattern := range patterns {
+		if exclusion, ok := strings.CutPrefix(pattern, "!"); ok {
+			if m.Matched && Match(exclusion, path) {
+				m.Matched = false
+				m.ExcludedBy = pattern
+			}
+			continue
+		}
 		if Match(pattern, path) {
-			return true
+			m = ScopeMatch{Matched: true, Pattern: pattern}
 		}
 	}
-	return false
+	return m
 }
 
-// FilterPaths returns only the paths that match at least one pattern.
+// MatchAny checks if a path is in scope of the given patterns, honoring
+// '!' exclusions as MatchScope does.
+func MatchAny(patterns []string, path string) bool {
+	return MatchScope(patterns, path).Matched
+}
+
+// FilterPaths returns only the paths that are in scope of the patterns.
 func FilterPaths(patterns []string, paths []string) []string {
 	var result []string
 	for _, path := range paths {
@@ -110,11 +216,15 @@ func FilterPaths(patterns []string, paths []string) []string {
 	return result
 }
 
-// FindMatchingPatterns returns all patterns that match the given path.
+// FindMatchingPatterns returns all inclusion patterns that match the given
+// path, or nil if an exclusion takes the path out of scope.
 func FindMatchingPatterns(patterns []string, path string) []string {
+	if !MatchAny(patterns, path) {
+		return nil
+	}
 	var result []string
 	for _, pattern := range patterns {
-		if Match(pattern, path) {
+		if !strings.HasPrefix(pattern, "!") && Match(pattern, path) {
 			result = append(result, pattern)
 		}
 	}
@@ -42,6 +42,24 @@ func TestMatch(t *testing.T) {
 
 		// Windows-style paths (should work after normalization)
 		{"src/**", "src\\file.go", true},
+
+		// Brace alternatives
+		{"src/{api,web}/**", "src/api/handler.go", true},
+		{"src/{api,web}/**", "src/web/index.ts", true},
+		{"src/{api,web}/**", "src/db/query.go", false},
+		{"*.{go,mod}", "go.mod", true},
+		{"{cmd,internal/{adr,cli}}/*.go", "internal/cli/list.go", true},
+		{"{cmd,internal/{adr,cli}}/*.go", "internal/lsp/server.go", false},
+		{"src/{api,web", "src/api", false},
+
+		// Character classes, in simple and ** patterns alike
+		{"v[0-9].go", "v1.go", true},
+		{"v[!0-9].go", "vx.go", true},
+		{"v[^0-9].go", "v1.go", false},
+		{"src/**/v[!0-9].go", "src/a/vx.go", true},
+		{"src/**/v[!0-9].go", "src/a/v1.go", false},
+		{"a[!x]b", "a/b", false},
+		{"a?b", "a/b", false},
 	}
 
 	for _, tt := range tests {
@@ -124,3 +142,57 @@ func TestFindMatchingPatterns(t *testing.T) {
 		})
 	}
 }
+
+func TestMatchScope(t *testing.T) {
+	patterns := []string{"src/**", "!src/gen/**", "src/gen/keep.go", "!**/*_test.go"}
+
+	tests := []struct {
+		path string
+		want ScopeMatch
+	}{
+		{"src/main.go", ScopeMatch{Matched: true, Pattern: "src/**"}},
+		{"src/gen/types.go", ScopeMatch{Pattern: "src/**", ExcludedBy: "!src/gen/**"}},
+		{"src/gen/keep.go", ScopeMatch{Matched: true, Pattern: "src/gen/keep.go"}},
+		{"src/main_test.go", ScopeMatch{Pattern: "src/**", ExcludedBy: "!**/*_test.go"}},
+		{"other/main.go", ScopeMatch{}},
+		{"other/main_test.go", ScopeMatch{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			if got := MatchScope(patterns, tt.path); got != tt.want {
+				t.Errorf("MatchScope(%q) = %+v, want %+v", tt.path, got, tt.want)
+			}
+		})
+	}
+
+	if got := FilterPaths(patterns, []string{"src/a.go", "src/gen/b.go", "src/gen/keep.go"}); len(got) != 2 {
+		t.Errorf("FilterPaths() = %v, want the excluded path dropped", got)
+	}
+	if got := FindMatchingPatterns(patterns, "src/gen/types.go"); got != nil {
+		t.Errorf("FindMatchingPatterns() = %v, want nil for an excluded path", got)
+	}
+}
+
+func TestValidatePattern(t *testing.T) {
+	tests := []struct {
+		pattern string
+		valid   bool
+	}{
+		{"src/**", true},
+		{"!src/gen/**", true},
+		{"src/{api,web}/[a-z]*.go", true},
+		{"", false},
+		{"!", false},
+		{"src/{api,web/**", false},
+		{"src/[a-/x.go", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			if err := ValidatePattern(tt.pattern); (err == nil) != tt.valid {
+				t.Errorf("ValidatePattern(%q) error = %v, want valid %v", tt.pattern, err, tt.valid)
+			}
+		})
+	}
+}
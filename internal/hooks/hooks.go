@@ -0,0 +1,163 @@
+// Package hooks installs and removes the git hooks that run decider's checks.
+//
+// A hook decider installs is a short shell script that runs the hook it
+// replaced, if any, and then 'decider hooks run NAME'. Replaced hooks are
+// kept next to it with the suffix ".pre-decider" and restored on uninstall.
+package hooks
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Names lists the hooks decider provides, in installation order.
+var Names = []string{"pre-commit", "commit-msg"}
+
+// ChainSuffix is appended to the name of a hook that decider replaced.
+const ChainSuffix = ".pre-decider"
+
+// marker identifies hooks written by decider.
+const marker = "# Installed by decider."
+
+// Status describes one hook in a hooks directory.
+type Status struct {
+	Hook      string `json:"hook"`
+	Installed bool   `json:"installed"` // The hook is decider's
+	Chained   bool   `json:"chained"`   // A replaced hook is kept and run first
+	Foreign   bool   `json:"foreign"`   // The hook exists but is not decider's
+	Path      string `json:"path"`
+}
+
+// Script returns the hook script for name.
+func Script(name string) string {
+	return fmt.Sprintf(`#!/bin/sh
+%s Remove with 'decider hooks uninstall'.
+#
+# Runs the %[2]s hook that was here before decider, if any, then decider's
+# checks. Skip them once with 'git commit --no-verify'.
+
+previous="$(dirname "$0")/%[2]s%[3]s"
+if [ -x "$previous" ]; then
+	"$previous" "$@" || exit $?
+fi
+
+if ! command -v decider >/dev/null 2>&1; then
+	echo "decider: not found in PATH, skipping ADR checks" >&2
+	exit 0
+fi
+exec decider hooks run %[2]s "$@"
+`, marker, name, ChainSuffix)
+}
+
+// Validate checks that every name is a hook decider provides.
+func Validate(names []string) error {
+	for _, name := range names {
+		if !isKnown(name) {
+			return fmt.Errorf("unknown hook %q (available: %s)", name, strings.Join(Names, ", "))
+		}
+	}
+	return nil
+}
+
+func isKnown(name string) bool {
+	for _, n := range Names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Inspect reports the state of the named hooks in dir.
+func Inspect(dir string, names []string) ([]Status, error) {
+	statuses := make([]Status, 0, len(names))
+	for _, name := range names {
+		s, err := inspect(dir, name)
+		if err != nil {
+			return nil, err
+		}
+		statuses = append(statuses, s)
+	}
+	return statuses, nil
+}
+
+func inspect(dir, name string) (Status, error) {
+	path := filepath.Join(dir, name)
+	s := Status{Hook: name, Path: path}
+
+	data, err := os.ReadFile(path)
+	switch {
+	case os.IsNotExist(err):
+	case err != nil:
+		return s, fmt.Errorf("reading %s: %w", path, err)
+	case isDecider(data):
+		s.Installed = true
+	default:
+		s.Foreign = true
+	}
+
+	if _, err := os.Stat(path + ChainSuffix); err == nil {
+		s.Chained = true
+	}
+	return s, nil
+}
+
+func isDecider(data []byte) bool {
+	return strings.Contains(string(data), marker)
+}
+
+// Install writes decider's hooks into dir. An existing hook that is not
+// decider's is renamed with ChainSuffix so the new hook runs it first;
+// decider's own hooks are rewritten in place.
+func Install(dir string, names []string) ([]Status, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("creating hooks directory: %w", err)
+	}
+	for _, name := range names {
+		s, err := inspect(dir, name)
+		if err != nil {
+			return nil, err
+		}
+		if s.Foreign {
+			if s.Chained {
+				return nil, fmt.Errorf("%s: cannot keep the existing hook, %s already exists", name, s.Path+ChainSuffix)
+			}
+			if err := os.Rename(s.Path, s.Path+ChainSuffix); err != nil {
+				return nil, fmt.Errorf("%s: keeping the existing hook: %w", name, err)
+			}
+		}
+		if err := os.WriteFile(s.Path, []byte(Script(name)), 0755); err != nil {
+			return nil, fmt.Errorf("writing %s: %w", s.Path, err)
+		}
+		// WriteFile keeps the mode of an existing file
+		if err := os.Chmod(s.Path, 0755); err != nil {
+			return nil, fmt.Errorf("writing %s: %w", s.Path, err)
+		}
+	}
+	return Inspect(dir, names)
+}
+
+// Uninstall removes decider's hooks from dir and restores the hooks they
+// replaced. Hooks that are not decider's are left alone.
+func Uninstall(dir string, names []string) ([]Status, error) {
+	for _, name := range names {
+		s, err := inspect(dir, name)
+		if err != nil {
+			return nil, err
+		}
+		if !s.Installed {
+			continue
+		}
+		if err := os.Remove(s.Path); err != nil {
+			return nil, fmt.Errorf("removing %s: %w", s.Path, err)
+		}
+		if s.Chained {
+			if err := os.Rename(s.Path+ChainSuffix, s.Path); err != nil {
+				return nil, fmt.Errorf("%s: restoring the previous hook: %w", name, err)
+			}
+		}
+	}
+	return Inspect(dir, names)
+}
@@ -0,0 +1,103 @@
+package hooks
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestInstallChainsExistingHook(t *testing.T) {
+	dir := t.TempDir()
+	existing := "#!/bin/sh\necho lint\n"
+	if err := os.WriteFile(filepath.Join(dir, "pre-commit"), []byte(existing), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	statuses, err := Install(dir, Names)
+	if err != nil {
+		t.Fatalf("Install() error = %v", err)
+	}
+	want := []Status{
+		{Hook: "pre-commit", Installed: true, Chained: true, Path: filepath.Join(dir, "pre-commit")},
+		{Hook: "commit-msg", Installed: true, Path: filepath.Join(dir, "commit-msg")},
+	}
+	for i, s := range statuses {
+		if s != want[i] {
+			t.Errorf("status[%d] = %+v, want %+v", i, s, want[i])
+		}
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "pre-commit"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{marker, `previous="$(dirname "$0")/pre-commit.pre-decider"`, "exec decider hooks run pre-commit"} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("hook lacks %q:\n%s", want, data)
+		}
+	}
+	if info, err := os.Stat(filepath.Join(dir, "commit-msg")); err != nil || info.Mode().Perm()&0100 == 0 {
+		t.Errorf("commit-msg is not executable: %v, %v", info, err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, "pre-commit"+ChainSuffix)); string(data) != existing {
+		t.Errorf("kept hook = %q, want %q", data, existing)
+	}
+
+	// Installing again rewrites decider's hooks and keeps the chain
+	if _, err := Install(dir, Names); err != nil {
+		t.Fatalf("second Install() error = %v", err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, "pre-commit"+ChainSuffix)); string(data) != existing {
+		t.Errorf("kept hook after reinstall = %q", data)
+	}
+
+	statuses, err = Uninstall(dir, Names)
+	if err != nil {
+		t.Fatalf("Uninstall() error = %v", err)
+	}
+	if !statuses[0].Foreign || statuses[0].Installed || statuses[0].Chained {
+		t.Errorf("pre-commit after uninstall = %+v, want the previous hook", statuses[0])
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, "pre-commit")); string(data) != existing {
+		t.Errorf("restored hook = %q, want %q", data, existing)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "commit-msg")); !os.IsNotExist(err) {
+		t.Errorf("commit-msg still present: %v", err)
+	}
+}
+
+func TestUninstallLeavesForeignHooks(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "commit-msg"), []byte("#!/bin/sh\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	statuses, err := Uninstall(dir, []string{"commit-msg"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !statuses[0].Foreign {
+		t.Errorf("status = %+v, want the foreign hook untouched", statuses[0])
+	}
+}
+
+func TestInstallRefusesToOverwriteKeptHook(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"pre-commit", "pre-commit" + ChainSuffix} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("#!/bin/sh\n"), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := Install(dir, []string{"pre-commit"}); err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Errorf("Install() error = %v, want a refusal", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	if err := Validate([]string{"pre-commit", "commit-msg"}); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+	if err := Validate([]string{"pre-push"}); err == nil || !strings.Contains(err.Error(), "unknown hook") {
+		t.Errorf("Validate(pre-push) error = %v", err)
+	}
+}
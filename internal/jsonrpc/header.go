@@ -0,0 +1,80 @@
+package jsonrpc
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// MaxMessageSize limits the size of a Content-Length framed message.
+const MaxMessageSize = 64 * 1024 * 1024
+
+// HeaderCodec frames each message with a Content-Length header, as in the
+// Language Server Protocol. Writes are safe for concurrent use.
+type HeaderCodec struct {
+	r  *bufio.Reader
+	w  io.Writer
+	mu sync.Mutex
+}
+
+// NewHeaderCodec creates a Content-Length framed codec.
+func NewHeaderCodec(r io.Reader, w io.Writer) *HeaderCodec {
+	return &HeaderCodec{r: bufio.NewReader(r), w: w}
+}
+
+// ReadMessage reads the headers and the body of the next message. It returns
+// io.EOF when the stream ends between messages.
+func (c *HeaderCodec) ReadMessage() (json.RawMessage, error) {
+	length := -1
+	for first := true; ; first = false {
+		line, err := c.r.ReadString('\n')
+		if err != nil {
+			if first && err == io.EOF && line == "" {
+				return nil, io.EOF
+			}
+			return nil, fmt.Errorf("reading header: %w", err)
+		}
+		line = strings.TrimRight(line, "\r\n")
+		if line == "" {
+			break
+		}
+		name, value, ok := strings.Cut(line, ":")
+		if !ok {
+			return nil, fmt.Errorf("malformed header %q", line)
+		}
+		if strings.EqualFold(strings.TrimSpace(name), "Content-Length") {
+			length, err = strconv.Atoi(strings.TrimSpace(value))
+			if err != nil || length < 0 || length > MaxMessageSize {
+				return nil, fmt.Errorf("invalid Content-Length %q", value)
+			}
+		}
+	}
+	if length < 0 {
+		return nil, fmt.Errorf("missing Content-Length header")
+	}
+
+	body := make([]byte, length)
+	if _, err := io.ReadFull(c.r, body); err != nil {
+		return nil, fmt.Errorf("reading body: %w", err)
+	}
+	return json.RawMessage(body), nil
+}
+
+// WriteMessage encodes v as JSON with a Content-Length header.
+func (c *HeaderCodec) WriteMessage(v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("encoding message: %w", err)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, err := fmt.Fprintf(c.w, "Content-Length: %d\r\n\r\n", len(data)); err != nil {
+		return err
+	}
+	_, err = c.w.Write(data)
+	return err
+}
@@ -0,0 +1,199 @@
+// Package jsonrpc implements the JSON-RPC 2.0 message types and the stream
+// codecs used by stdio protocol servers: newline-delimited (MCP) and
+// Content-Length framed (LSP).
+package jsonrpc
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// Version is the JSON-RPC protocol version.
+const Version = "2.0"
+
+// Standard JSON-RPC error codes.
+const (
+	CodeParseError     = -32700
+	CodeInvalidRequest = -32600
+	CodeMethodNotFound = -32601
+	CodeInvalidParams  = -32602
+	CodeInternalError  = -32603
+)
+
+// Request is a JSON-RPC request, or a notification when ID is empty.
+type Request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+// IsNotification reports whether the request expects no response.
+func (r *Request) IsNotification() bool {
+	return len(r.ID) == 0
+}
+
+// Response is a JSON-RPC response. Exactly one of Result and Error is set.
+type Response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *Error          `json:"error,omitempty"`
+}
+
+// Error is a JSON-RPC error object.
+type Error struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("jsonrpc error %d: %s", e.Code, e.Message)
+}
+
+// Errorf creates an Error with a formatted message.
+func Errorf(code int, format string, args ...interface{}) *Error {
+	return &Error{Code: code, Message: fmt.Sprintf(format, args...)}
+}
+
+// Notification is a message sent without expecting a response.
+type Notification struct {
+	JSONRPC string      `json:"jsonrpc"`
+	Method  string      `json:"method"`
+	Params  interface{} `json:"params,omitempty"`
+}
+
+// NewNotification creates a notification.
+func NewNotification(method string, params interface{}) *Notification {
+	return &Notification{JSONRPC: Version, Method: method, Params: params}
+}
+
+// NewResponse creates a response carrying result, encoded as JSON. A nil
+// result is sent as null.
+func NewResponse(id json.RawMessage, result interface{}) (*Response, error) {
+	data, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("encoding result: %w", err)
+	}
+	return &Response{JSONRPC: Version, ID: nullID(id), Result: data}, nil
+}
+
+// NewErrorResponse creates a response carrying an error.
+func NewErrorResponse(id json.RawMessage, err *Error) *Response {
+	return &Response{JSONRPC: Version, ID: nullID(id), Error: err}
+}
+
+func nullID(id json.RawMessage) json.RawMessage {
+	if len(id) == 0 {
+		return json.RawMessage("null")
+	}
+	return id
+}
+
+// Codec reads and writes JSON-RPC messages on a stream.
+type Codec interface {
+	ReadMessage() (json.RawMessage, error)
+	WriteMessage(v interface{}) error
+}
+
+// LineCodec frames each message as a single line of JSON. Writes are safe
+// for concurrent use.
+type LineCodec struct {
+	r  *bufio.Reader
+	w  io.Writer
+	mu sync.Mutex
+}
+
+// NewLineCodec creates a newline-delimited codec.
+func NewLineCodec(r io.Reader, w io.Writer) *LineCodec {
+	return &LineCodec{r: bufio.NewReader(r), w: w}
+}
+
+// ReadMessage returns the next non-empty line. It returns io.EOF when the
+// stream ends.
+func (c *LineCodec) ReadMessage() (json.RawMessage, error) {
+	for {
+		line, err := c.r.ReadBytes('\n')
+		line = bytes.TrimSpace(line)
+		if len(line) > 0 {
+			return json.RawMessage(line), nil
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// WriteMessage encodes v as one line of JSON.
+func (c *LineCodec) WriteMessage(v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("encoding message: %w", err)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	_, err = c.w.Write(append(data, '\n'))
+	return err
+}
+
+// Handler handles a request and returns its result. For notifications the
+// result is discarded.
+type Handler func(req *Request) (interface{}, *Error)
+
+// StopServing can be returned by a Handler to make Serve return after the
+// current message without sending a response.
+var StopServing = &Error{Code: CodeInternalError, Message: "server stopped"}
+
+// Serve reads requests from the codec and answers them in order until the
+// stream ends.
+func Serve(codec Codec, handle Handler) error {
+	for {
+		msg, err := codec.ReadMessage()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("reading message: %w", err)
+		}
+
+		var req Request
+		if err := json.Unmarshal(msg, &req); err != nil {
+			if err := codec.WriteMessage(NewErrorResponse(nil, Errorf(CodeParseError, "parse error: %v", err))); err != nil {
+				return err
+			}
+			continue
+		}
+		if req.JSONRPC != Version || req.Method == "" {
+			if req.IsNotification() {
+				continue
+			}
+			if err := codec.WriteMessage(NewErrorResponse(req.ID, Errorf(CodeInvalidRequest, "invalid request"))); err != nil {
+				return err
+			}
+			continue
+		}
+
+		result, rpcErr := handle(&req)
+		if rpcErr == StopServing {
+			return nil
+		}
+		if req.IsNotification() {
+			continue
+		}
+
+		resp := NewErrorResponse(req.ID, rpcErr)
+		if rpcErr == nil {
+			if resp, err = NewResponse(req.ID, result); err != nil {
+				resp = NewErrorResponse(req.ID, Errorf(CodeInternalError, "%v", err))
+			}
+		}
+		if err := codec.WriteMessage(resp); err != nil {
+			return fmt.Errorf("writing response: %w", err)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package jsonrpc
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestServe(t *testing.T) {
+	input := strings.Join([]string{
+		`{"jsonrpc":"2.0","id":1,"method":"echo","params":"hi"}`,
+		``,
+		`{"jsonrpc":"2.0","method":"notify"}`,
+		`not json`,
+		`{"jsonrpc":"1.0","id":"a","method":"echo"}`,
+		`{"jsonrpc":"2.0","id":2,"method":"fail"}`,
+		`{"jsonrpc":"2.0","id":3,"method":"nothing"}`,
+	}, "\n")
+
+	var notified bool
+	var out bytes.Buffer
+	err := Serve(NewLineCodec(strings.NewReader(input), &out), func(req *Request) (interface{}, *Error) {
+		switch req.Method {
+		case "echo":
+			return req.Params, nil
+		case "notify":
+			notified = true
+			return "ignored", nil
+		case "fail":
+			return nil, Errorf(CodeMethodNotFound, "no %s", req.Method)
+		}
+		return nil, nil
+	})
+	if err != nil {
+		t.Fatalf("Serve() error = %v", err)
+	}
+	if !notified {
+		t.Error("notification not handled")
+	}
+
+	want := []string{
+		`{"jsonrpc":"2.0","id":1,"result":"hi"}`,
+		`{"jsonrpc":"2.0","id":null,"error":{"code":-32700,"message":"parse error: invalid character 'o' in literal null (expecting 'u')"}}`,
+		`{"jsonrpc":"2.0","id":"a","error":{"code":-32600,"message":"invalid request"}}`,
+		`{"jsonrpc":"2.0","id":2,"error":{"code":-32601,"message":"no fail"}}`,
+		`{"jsonrpc":"2.0","id":3,"result":null}`,
+	}
+	got := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(got) != len(want) {
+		t.Fatalf("responses:\n%s", out.String())
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("response %d = %s, want %s", i, got[i], want[i])
+		}
+	}
+}
+
+func TestLineCodecRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	codec := NewLineCodec(&buf, &buf)
+	if err := codec.WriteMessage(map[string]string{"text": "line1\nline2"}); err != nil {
+		t.Fatal(err)
+	}
+	msg, err := codec.ReadMessage()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got map[string]string
+	if err := json.Unmarshal(msg, &got); err != nil || got["text"] != "line1\nline2" {
+		t.Errorf("ReadMessage() = %s, %v", msg, err)
+	}
+}
+
+func TestHeaderCodec(t *testing.T) {
+	var buf bytes.Buffer
+	codec := NewHeaderCodec(&buf, &buf)
+	for _, msg := range []string{"first", "second"} {
+		if err := codec.WriteMessage(NewNotification("log", msg)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if !strings.HasPrefix(buf.String(), "Content-Length: 49\r\n\r\n{") {
+		t.Errorf("unexpected framing: %q", buf.String())
+	}
+
+	for _, want := range []string{"first", "second"} {
+		msg, err := codec.ReadMessage()
+		if err != nil {
+			t.Fatalf("ReadMessage() error = %v", err)
+		}
+		var n struct{ Params string }
+		if err := json.Unmarshal(msg, &n); err != nil || n.Params != want {
+			t.Errorf("ReadMessage() = %s, want params %q", msg, want)
+		}
+	}
+	if _, err := codec.ReadMessage(); err != io.EOF {
+		t.Errorf("ReadMessage() at end = %v, want io.EOF", err)
+	}
+}
+
+func TestHeaderCodecErrors(t *testing.T) {
+	tests := []string{
+		"Content-Type: application/json\r\n\r\n{}",
+		"Content-Length: abc\r\n\r\n{}",
+		"Content-Length: 10\r\n\r\n{}",
+		"garbage\r\n\r\n",
+	}
+	for _, input := range tests {
+		if _, err := NewHeaderCodec(strings.NewReader(input), io.Discard).ReadMessage(); err == nil || err == io.EOF {
+			t.Errorf("ReadMessage(%q) error = %v, want error", input, err)
+		}
+	}
+}
+
+func TestServeStop(t *testing.T) {
+	input := `{"jsonrpc":"2.0","method":"exit"}` + "\n" + `{"jsonrpc":"2.0","id":1,"method":"late"}` + "\n"
+	var out bytes.Buffer
+	err := Serve(NewLineCodec(strings.NewReader(input), &out), func(req *Request) (interface{}, *Error) {
+		if req.Method == "exit" {
+			return nil, StopServing
+		}
+		t.Errorf("unexpected request %s after stop", req.Method)
+		return nil, nil
+	})
+	if err != nil || out.Len() != 0 {
+		t.Errorf("Serve() = %v, output %q", err, out.String())
+	}
+}
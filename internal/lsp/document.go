@@ -0,0 +1,175 @@
+package lsp
+
+import (
+	"net/url"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// uriToPath converts a file:// URI to a file path.
+func uriToPath(uri string) (string, bool) {
+	u, err := url.Parse(uri)
+	if err != nil || u.Scheme != "file" {
+		return "", false
+	}
+	p := u.Path
+	if runtime.GOOS == "windows" {
+		p = strings.TrimPrefix(p, "/")
+	}
+	return filepath.FromSlash(p), true
+}
+
+// pathToURI converts an absolute file path to a file:// URI.
+func pathToURI(path string) string {
+	p := filepath.ToSlash(path)
+	if !strings.HasPrefix(p, "/") {
+		p = "/" + p
+	}
+	return (&url.URL{Scheme: "file", Path: p}).String()
+}
+
+// splitLines splits text into lines without line terminators.
+func splitLines(text string) []string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSuffix(line, "\r")
+	}
+	return lines
+}
+
+// utf16Len returns the length of s in UTF-16 code units, the unit of LSP
+// character offsets.
+func utf16Len(s string) int {
+	n := 0
+	for _, r := range s {
+		n += utf16.RuneLen(r)
+	}
+	return n
+}
+
+// byteOffset converts a UTF-16 character offset within line to a byte offset.
+func byteOffset(line string, character int) int {
+	units := 0
+	for i, r := range line {
+		if units >= character {
+			return i
+		}
+		units += utf16.RuneLen(r)
+	}
+	return len(line)
+}
+
+// lineRange returns the range covering a whole line.
+func lineRange(lines []string, line int) Range {
+	if line < 0 || line >= len(lines) {
+		line = 0
+	}
+	end := 0
+	if line < len(lines) {
+		end = utf16Len(lines[line])
+	}
+	return Range{Start: Position{Line: line}, End: Position{Line: line, Character: end}}
+}
+
+// adrIDRegex matches ADR IDs with any configured prefix.
+var adrIDRegex = regexp.MustCompile(`[A-Za-z][A-Za-z0-9]*-\d{4}`)
+
+// idAt returns the ADR ID under the cursor and its range, if any.
+func idAt(lines []string, pos Position) (string, Range, bool) {
+	if pos.Line < 0 || pos.Line >= len(lines) {
+		return "", Range{}, false
+	}
+	line := lines[pos.Line]
+	offset := byteOffset(line, pos.Character)
+	for _, loc := range adrIDRegex.FindAllStringIndex(line, -1) {
+		if offset >= loc[0] && offset <= loc[1] {
+			r := Range{
+				Start: Position{Line: pos.Line, Character: utf16Len(line[:loc[0]])},
+				End:   Position{Line: pos.Line, Character: utf16Len(line[:loc[1]])},
+			}
+			return line[loc[0]:loc[1]], r, true
+		}
+	}
+	return "", Range{}, false
+}
+
+// frontmatterEnd returns the index of the closing "---" line, or -1 if the
+// document has no frontmatter.
+func frontmatterEnd(lines []string) int {
+	if len(lines) == 0 || strings.TrimSpace(lines[0]) != "---" {
+		return -1
+	}
+	for i := 1; i < len(lines); i++ {
+		if strings.TrimSpace(lines[i]) == "---" {
+			return i
+		}
+	}
+	return -1
+}
+
+// referenceFields are the frontmatter keys whose values are ADR IDs.
+var referenceFields = map[string]bool{
+	"supersedes":    true,
+	"superseded_by": true,
+	"related_adrs":  true,
+}
+
+var topLevelKeyRegex = regexp.MustCompile(`^([A-Za-z_][A-Za-z0-9_]*)\s*:`)
+
+// enclosingKey returns the top-level frontmatter key whose value contains the
+// given line, or an empty string outside the frontmatter.
+func enclosingKey(lines []string, line int) string {
+	end := frontmatterEnd(lines)
+	if end < 0 || line <= 0 || line >= end {
+		return ""
+	}
+	for i := line; i > 0; i-- {
+		if m := topLevelKeyRegex.FindStringSubmatch(lines[i]); m != nil {
+			return m[1]
+		}
+		if i < line && strings.TrimSpace(lines[i]) != "" && !startsIndented(lines[i]) {
+			return ""
+		}
+	}
+	return ""
+}
+
+func startsIndented(line string) bool {
+	r, _ := utf8.DecodeRuneInString(line)
+	return r == ' ' || r == '\t' || r == '-'
+}
+
+// yamlLineRegex extracts the line number from yaml.v3 error messages.
+var yamlLineRegex = regexp.MustCompile(`line (\d+)`)
+
+// locateParseError returns the line of a frontmatter parse error.
+func locateParseError(lines []string, err error) int {
+	m := yamlLineRegex.FindStringSubmatch(err.Error())
+	if m == nil || frontmatterEnd(lines) < 0 {
+		return 0
+	}
+	n, _ := strconv.Atoi(m[1])
+	return n // yaml lines are 1-based and start after the opening "---"
+}
+
+// positionRange converts a 1-based line and rune column, as reported by
+// validation, to a range from that column to the end of the line.
+func positionRange(lines []string, line, column int) Range {
+	r := lineRange(lines, line-1)
+	text := ""
+	if r.Start.Line < len(lines) {
+		text = lines[r.Start.Line]
+	}
+	if column > 1 {
+		runes := []rune(text)
+		if column-1 < len(runes) {
+			r.Start.Character = utf16Len(string(runes[:column-1]))
+		}
+	}
+	return r
+}
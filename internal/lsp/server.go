@@ -0,0 +1,417 @@
+// Package lsp implements a Language Server Protocol server over stdio that
+// validates ADR files while they are edited and surfaces ADRs in source files.
+package lsp
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/sventorben/decider/internal/adr"
+	"github.com/sventorben/decider/internal/glob"
+	"github.com/sventorben/decider/internal/jsonrpc"
+	"github.com/sventorben/decider/internal/validate"
+)
+
+// Config holds configuration for the language server.
+type Config struct {
+	Dir     string // ADR directory
+	Version string // Server version reported to clients
+
+	// Project-specific validation rules for diagnostics.
+	RequiredSections []string
+	AllowedTags      []string
+	IDPrefix         string
+}
+
+// Server answers LSP requests for one workspace.
+type Server struct {
+	cfg      Config
+	dir      string            // Absolute ADR directory
+	root     string            // Absolute workspace root
+	docs     map[string]string // Text of open documents by URI
+	codec    jsonrpc.Codec
+	shutdown bool
+}
+
+// errExitWithoutShutdown is returned by Serve when the client sends exit
+// without a preceding shutdown request.
+var errExitWithoutShutdown = errors.New("exit received before shutdown")
+
+// NewServer creates a language server.
+func NewServer(cfg Config) *Server {
+	dir, err := filepath.Abs(cfg.Dir)
+	if err != nil {
+		dir = cfg.Dir
+	}
+	root, err := os.Getwd()
+	if err != nil {
+		root = "."
+	}
+	return &Server{cfg: cfg, dir: dir, root: root, docs: make(map[string]string)}
+}
+
+// Serve answers Content-Length framed JSON-RPC requests from r on w until
+// the client sends exit or r ends.
+func (s *Server) Serve(r io.Reader, w io.Writer) error {
+	s.codec = jsonrpc.NewHeaderCodec(r, w)
+	exited := false
+	err := jsonrpc.Serve(s.codec, func(req *jsonrpc.Request) (interface{}, *jsonrpc.Error) {
+		if req.Method == "exit" {
+			exited = true
+			return nil, jsonrpc.StopServing
+		}
+		return s.handle(req)
+	})
+	if err == nil && exited && !s.shutdown {
+		return errExitWithoutShutdown
+	}
+	return err
+}
+
+func (s *Server) handle(req *jsonrpc.Request) (interface{}, *jsonrpc.Error) {
+	if s.shutdown && !req.IsNotification() {
+		return nil, jsonrpc.Errorf(jsonrpc.CodeInvalidRequest, "server is shutting down")
+	}
+
+	switch req.Method {
+	case "initialize":
+		var params struct {
+			RootURI string `json:"rootUri"`
+		}
+		_ = json.Unmarshal(req.Params, &params)
+		if root, ok := uriToPath(params.RootURI); ok {
+			s.root = root
+		}
+		return map[string]interface{}{
+			"capabilities": map[string]interface{}{
+				"textDocumentSync":   1, // Full
+				"completionProvider": map[string]interface{}{"triggerCharacters": []string{"-", " ", "["}},
+				"definitionProvider": true,
+				"hoverProvider":      true,
+			},
+			"serverInfo": map[string]string{"name": "decider", "version": s.cfg.Version},
+		}, nil
+	case "initialized":
+		return nil, nil
+	case "shutdown":
+		s.shutdown = true
+		return nil, nil
+
+	case "textDocument/didOpen":
+		var params DidOpenParams
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return nil, invalidParams(err)
+		}
+		s.docs[params.TextDocument.URI] = params.TextDocument.Text
+		return nil, s.publishDiagnostics(params.TextDocument.URI)
+	case "textDocument/didChange":
+		var params DidChangeParams
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return nil, invalidParams(err)
+		}
+		if n := len(params.ContentChanges); n > 0 {
+			s.docs[params.TextDocument.URI] = params.ContentChanges[n-1].Text
+		}
+		return nil, s.publishDiagnostics(params.TextDocument.URI)
+	case "textDocument/didSave":
+		return nil, nil
+	case "textDocument/didClose":
+		var params DidCloseParams
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return nil, invalidParams(err)
+		}
+		delete(s.docs, params.TextDocument.URI)
+		if s.isADRDocument(params.TextDocument.URI) {
+			return nil, s.notify("textDocument/publishDiagnostics",
+				PublishDiagnosticsParams{URI: params.TextDocument.URI, Diagnostics: []Diagnostic{}})
+		}
+		return nil, nil
+
+	case "textDocument/completion":
+		var params TextDocumentPositionParams
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return nil, invalidParams(err)
+		}
+		return s.completion(params), nil
+	case "textDocument/definition":
+		var params TextDocumentPositionParams
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return nil, invalidParams(err)
+		}
+		return s.definition(params), nil
+	case "textDocument/hover":
+		var params TextDocumentPositionParams
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return nil, invalidParams(err)
+		}
+		return s.hover(params), nil
+	}
+
+	if req.IsNotification() || strings.HasPrefix(req.Method, "$/") {
+		return nil, nil
+	}
+	return nil, jsonrpc.Errorf(jsonrpc.CodeMethodNotFound, "method not found: %s", req.Method)
+}
+
+func invalidParams(err error) *jsonrpc.Error {
+	return jsonrpc.Errorf(jsonrpc.CodeInvalidParams, "invalid params: %v", err)
+}
+
+func (s *Server) notify(method string, params interface{}) *jsonrpc.Error {
+	if err := s.codec.WriteMessage(jsonrpc.NewNotification(method, params)); err != nil {
+		return jsonrpc.Errorf(jsonrpc.CodeInternalError, "%v", err)
+	}
+	return nil
+}
+
+// isADRDocument reports whether a document is an ADR file in the ADR directory.
+func (s *Server) isADRDocument(uri string) bool {
+	path, ok := uriToPath(uri)
+	if !ok || filepath.Dir(path) != s.dir {
+		return false
+	}
+	return adr.IsADRFilename(filepath.Base(path))
+}
+
+// text returns the contents of a document, preferring the open buffer.
+func (s *Server) text(uri string) (string, bool) {
+	if text, ok := s.docs[uri]; ok {
+		return text, true
+	}
+	path, ok := uriToPath(uri)
+	if !ok || validate.CheckFileSize(path) != nil {
+		return "", false
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", false
+	}
+	return string(data), true
+}
+
+// loadADRs returns the ADRs of the workspace, using the contents of open
+// documents over the files on disk. ADRs that do not parse are skipped.
+func (s *Server) loadADRs() []*adr.ADR {
+	files, _ := adr.ListADRFiles(s.dir)
+	seen := make(map[string]bool)
+	var adrs []*adr.ADR
+	load := func(uri, path string) {
+		text, ok := s.text(uri)
+		if !ok {
+			return
+		}
+		seen[uri] = true
+		if a, err := adr.ParseADR(text, filepath.Base(path), path); err == nil {
+			adrs = append(adrs, a)
+		}
+	}
+	for _, name := range files {
+		path := filepath.Join(s.dir, name)
+		load(pathToURI(path), path)
+	}
+
+	// Open ADRs that have not been saved yet
+	var unsaved []string
+	for uri := range s.docs {
+		if !seen[uri] && s.isADRDocument(uri) {
+			unsaved = append(unsaved, uri)
+		}
+	}
+	sort.Strings(unsaved)
+	for _, uri := range unsaved {
+		path, _ := uriToPath(uri)
+		load(uri, path)
+	}
+	return adrs
+}
+
+// publishDiagnostics validates an open ADR document and sends the result.
+func (s *Server) publishDiagnostics(uri string) *jsonrpc.Error {
+	if !s.isADRDocument(uri) {
+		return nil
+	}
+	return s.notify("textDocument/publishDiagnostics", PublishDiagnosticsParams{
+		URI:         uri,
+		Diagnostics: s.diagnostics(uri),
+	})
+}
+
+// diagnostics validates an ADR document like 'decider check adr', including
+// references to the other ADRs of the workspace.
+func (s *Server) diagnostics(uri string) []Diagnostic {
+	text := s.docs[uri]
+	lines := splitLines(text)
+	path, _ := uriToPath(uri)
+	name := filepath.Base(path)
+
+	diagnostics := []Diagnostic{}
+	a, err := adr.ParseADR(text, name, path)
+	if err != nil {
+		return append(diagnostics, Diagnostic{
+			Range:    lineRange(lines, locateParseError(lines, err)),
+			Severity: SeverityError,
+			Code:     "parse_error",
+			Source:   "decider",
+			Message:  err.Error(),
+		})
+	}
+
+	vr := adr.ValidateWithOptions(a, adr.ValidateOptions{
+		RequiredSections: s.cfg.RequiredSections,
+		AllowedTags:      s.cfg.AllowedTags,
+		IDPrefix:         s.cfg.IDPrefix,
+	})
+	errs := vr.Errors
+	for _, ve := range adr.ValidateRepository(s.loadADRs()) {
+		if ve.File == name {
+			errs = append(errs, ve)
+		}
+	}
+
+	add := func(ve adr.ValidationError, severity int) {
+		diagnostics = append(diagnostics, Diagnostic{
+			Range:    positionRange(lines, ve.Line, ve.Column),
+			Severity: severity,
+			Code:     ve.Code,
+			Source:   "decider",
+			Message:  fmt.Sprintf("%s: %s", ve.Field, ve.Message),
+		})
+	}
+	for _, ve := range errs {
+		add(ve, SeverityError)
+	}
+	for _, ve := range vr.Warnings {
+		add(ve, SeverityWarning)
+	}
+	return diagnostics
+}
+
+// completion offers ADR IDs inside the supersedes, superseded_by and
+// related_adrs lists of an ADR's frontmatter.
+func (s *Server) completion(params TextDocumentPositionParams) []CompletionItem {
+	items := []CompletionItem{}
+	text, ok := s.text(params.TextDocument.URI)
+	if !ok {
+		return items
+	}
+	lines := splitLines(text)
+	if !referenceFields[enclosingKey(lines, params.Position.Line)] {
+		return items
+	}
+
+	self := ""
+	path, _ := uriToPath(params.TextDocument.URI)
+	if a, err := adr.ParseADR(text, filepath.Base(path), path); err == nil {
+		self = a.Frontmatter.ADRID
+	}
+
+	for _, a := range s.loadADRs() {
+		if a.Frontmatter.ADRID == self {
+			continue
+		}
+		items = append(items, CompletionItem{
+			Label:         a.Frontmatter.ADRID,
+			Kind:          CompletionItemKindReference,
+			Detail:        a.Frontmatter.Title,
+			Documentation: fmt.Sprintf("Status: %s\nFile: %s", a.Frontmatter.Status, a.Filename),
+		})
+	}
+	return items
+}
+
+// definition resolves an ADR ID under the cursor, in any document, to the
+// ADR's file.
+func (s *Server) definition(params TextDocumentPositionParams) []Location {
+	locations := []Location{}
+	text, ok := s.text(params.TextDocument.URI)
+	if !ok {
+		return locations
+	}
+	id, _, ok := idAt(splitLines(text), params.Position)
+	if !ok {
+		return locations
+	}
+	for _, a := range s.loadADRs() {
+		if a.Frontmatter.ADRID == id {
+			locations = append(locations, Location{URI: pathToURI(a.FilePath)})
+		}
+	}
+	return locations
+}
+
+// hover describes the ADR under the cursor or, elsewhere in a source file,
+// the adopted ADRs whose scope covers the file.
+func (s *Server) hover(params TextDocumentPositionParams) *Hover {
+	text, ok := s.text(params.TextDocument.URI)
+	if !ok {
+		return nil
+	}
+	adrs := s.loadADRs()
+
+	if id, r, ok := idAt(splitLines(text), params.Position); ok {
+		for _, a := range adrs {
+			if a.Frontmatter.ADRID == id {
+				return &Hover{Contents: markdown(describeADR(a)), Range: &r}
+			}
+		}
+	}
+
+	if s.isADRDocument(params.TextDocument.URI) {
+		return nil
+	}
+	path, ok := uriToPath(params.TextDocument.URI)
+	if !ok {
+		return nil
+	}
+	rel, err := filepath.Rel(s.root, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return nil
+	}
+	rel = filepath.ToSlash(rel)
+
+	var sections []string
+	for _, a := range adrs {
+		if a.Frontmatter.Status != adr.StatusAdopted {
+			continue
+		}
+		patterns := glob.FindMatchingPatterns(a.Frontmatter.Scope.Paths, rel)
+		if len(patterns) == 0 {
+			continue
+		}
+		sections = append(sections, fmt.Sprintf("%s\n\n_Scope: `%s`_", describeADR(a), strings.Join(patterns, "`, `")))
+	}
+	if len(sections) == 0 {
+		return nil
+	}
+	sort.Strings(sections)
+	return &Hover{Contents: markdown(strings.Join(sections, "\n\n---\n\n"))}
+}
+
+func markdown(value string) MarkupContent {
+	return MarkupContent{Kind: "markdown", Value: value}
+}
+
+// describeADR renders an ADR's title, status and rules as markdown.
+func describeADR(a *adr.ADR) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "**%s: %s** (%s)", a.Frontmatter.ADRID, a.Frontmatter.Title, a.Frontmatter.Status)
+	if constraints := adr.ConstraintStrings(a.Frontmatter.Constraints); len(constraints) > 0 {
+		b.WriteString("\n\nConstraints:")
+		for _, c := range constraints {
+			fmt.Fprintf(&b, "\n- %s", c)
+		}
+	}
+	if len(a.Frontmatter.Invariants) > 0 {
+		b.WriteString("\n\nInvariants:")
+		for _, inv := range a.Frontmatter.Invariants {
+			fmt.Fprintf(&b, "\n- %s", inv)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,400 @@
+package lsp
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/sventorben/decider/internal/jsonrpc"
+)
+
+// fakeClient talks to a Server running in-process over pipes.
+type fakeClient struct {
+	t      *testing.T
+	codec  *jsonrpc.HeaderCodec
+	stdin  io.WriteCloser
+	done   chan error
+	nextID int
+}
+
+func startServer(t *testing.T, cfg Config) *fakeClient {
+	t.Helper()
+	serverIn, clientOut := io.Pipe()
+	clientIn, serverOut := io.Pipe()
+
+	c := &fakeClient{
+		t:     t,
+		codec: jsonrpc.NewHeaderCodec(clientIn, clientOut),
+		stdin: clientOut,
+		done:  make(chan error, 1),
+	}
+	go func() {
+		err := NewServer(cfg).Serve(serverIn, serverOut)
+		_ = serverOut.Close()
+		c.done <- err
+	}()
+	return c
+}
+
+// call sends a request and decodes the response's result into result.
+func (c *fakeClient) call(method string, params interface{}, result interface{}) *jsonrpc.Error {
+	c.t.Helper()
+	c.nextID++
+	id := json.RawMessage(strconv.Itoa(c.nextID))
+	c.send(id, method, params)
+
+	var resp jsonrpc.Response
+	c.read(&resp)
+	if string(resp.ID) != string(id) {
+		c.t.Fatalf("response id = %s, want %s", resp.ID, id)
+	}
+	if resp.Error != nil {
+		return resp.Error
+	}
+	if result != nil {
+		if err := json.Unmarshal(resp.Result, result); err != nil {
+			c.t.Fatalf("decoding result %s: %v", resp.Result, err)
+		}
+	}
+	return nil
+}
+
+// notify sends a notification.
+func (c *fakeClient) notify(method string, params interface{}) {
+	c.t.Helper()
+	c.send(nil, method, params)
+}
+
+func (c *fakeClient) send(id json.RawMessage, method string, params interface{}) {
+	c.t.Helper()
+	rawParams, err := json.Marshal(params)
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	if err := c.codec.WriteMessage(jsonrpc.Request{JSONRPC: jsonrpc.Version, ID: id, Method: method, Params: rawParams}); err != nil {
+		c.t.Fatalf("writing %s: %v", method, err)
+	}
+}
+
+func (c *fakeClient) read(v interface{}) {
+	c.t.Helper()
+	msg, err := c.codec.ReadMessage()
+	if err != nil {
+		c.t.Fatalf("reading message: %v", err)
+	}
+	if err := json.Unmarshal(msg, v); err != nil {
+		c.t.Fatalf("decoding message %s: %v", msg, err)
+	}
+}
+
+// diagnostics reads the next publishDiagnostics notification.
+func (c *fakeClient) diagnostics() PublishDiagnosticsParams {
+	c.t.Helper()
+	var msg struct {
+		Method string                   `json:"method"`
+		Params PublishDiagnosticsParams `json:"params"`
+	}
+	c.read(&msg)
+	if msg.Method != "textDocument/publishDiagnostics" {
+		c.t.Fatalf("notification method = %q", msg.Method)
+	}
+	return msg.Params
+}
+
+// stop performs the shutdown and exit sequence and returns Serve's result.
+func (c *fakeClient) stop(shutdown bool) error {
+	c.t.Helper()
+	if shutdown {
+		if err := c.call("shutdown", nil, nil); err != nil {
+			c.t.Fatalf("shutdown: %v", err)
+		}
+	}
+	c.notify("exit", nil)
+	err := <-c.done
+	_ = c.stdin.Close()
+	return err
+}
+
+const testADR = `---
+adr_id: ADR-0001
+title: Use PostgreSQL
+status: adopted
+date: 2026-01-16
+scope:
+  paths: ["src/db/**"]
+tags: [database]
+constraints:
+  - "Use prepared statements"
+---
+
+# ADR-0001: Use PostgreSQL
+
+## Context
+
+Context.
+
+## Decision
+
+We use PostgreSQL.
+
+## Alternatives Considered
+
+None.
+
+## Consequences
+
+Consequences.
+`
+
+const draftADR = `---
+adr_id: ADR-0002
+title: Add read replicas
+status: proposed
+date: 2026-02-01
+related_adrs:
+  - ADR-0001
+  - ADR-0042
+tags: [caching]
+---
+
+# ADR-0002: Add read replicas
+
+## Context
+
+Context.
+
+## Decision
+
+Decision.
+
+## Consequences
+
+Consequences.
+`
+
+// testServer starts an initialized server for a workspace with one adopted ADR.
+func testServer(t *testing.T) (*fakeClient, string) {
+	t.Helper()
+	root := t.TempDir()
+	dir := filepath.Join(root, "docs", "adr")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "0001-use-postgresql.md"), []byte(testADR), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	c := startServer(t, Config{Dir: dir, AllowedTags: []string{"database"}})
+	var init struct {
+		Capabilities struct {
+			HoverProvider bool `json:"hoverProvider"`
+		} `json:"capabilities"`
+		ServerInfo struct {
+			Name string `json:"name"`
+		} `json:"serverInfo"`
+	}
+	if err := c.call("initialize", map[string]interface{}{"rootUri": pathToURI(root)}, &init); err != nil {
+		t.Fatalf("initialize: %v", err)
+	}
+	if !init.Capabilities.HoverProvider || init.ServerInfo.Name != "decider" {
+		t.Fatalf("initialize result = %+v", init)
+	}
+	c.notify("initialized", struct{}{})
+	return c, root
+}
+
+func (c *fakeClient) open(path, text string) string {
+	c.t.Helper()
+	uri := pathToURI(path)
+	c.notify("textDocument/didOpen", DidOpenParams{TextDocument: TextDocumentItem{URI: uri, Version: 1, Text: text}})
+	return uri
+}
+
+func TestDiagnostics(t *testing.T) {
+	c, root := testServer(t)
+	path := filepath.Join(root, "docs", "adr", "0002-add-read-replicas.md")
+	uri := c.open(path, draftADR)
+
+	got := c.diagnostics()
+	if got.URI != uri {
+		t.Errorf("diagnostics uri = %q, want %q", got.URI, uri)
+	}
+	// Diagnostics are reported on the offending frontmatter key or item or, for
+	// missing sections, at the start of the body.
+	want := map[string]int{
+		"tags: ":         8,
+		"body: ":         11,
+		"related_adrs: ": 7, // the dangling ADR-0042 item
+	}
+	for prefix, line := range want {
+		found := false
+		for _, d := range got.Diagnostics {
+			if strings.HasPrefix(d.Message, prefix) {
+				found = true
+				if d.Range.Start.Line != line || d.Source != "decider" {
+					t.Errorf("diagnostic %+v, want line %d", d, line)
+				}
+			}
+		}
+		if !found {
+			t.Errorf("missing %q diagnostic in %+v", prefix, got.Diagnostics)
+		}
+	}
+
+	// Fixing the document leaves only the rationale warnings, which point
+	// at the Decision heading.
+	fixed := strings.Replace(draftADR, "  - ADR-0042\n", "", 1)
+	fixed = strings.Replace(fixed, "[caching]", "[database]", 1)
+	fixed = strings.Replace(fixed, "## Consequences", "## Alternatives Considered\n\nNone.\n\n## Consequences", 1)
+	c.notify("textDocument/didChange", map[string]interface{}{
+		"textDocument":   TextDocumentIdentifier{URI: uri},
+		"contentChanges": []map[string]string{{"text": fixed}},
+	})
+	got = c.diagnostics()
+	for _, d := range got.Diagnostics {
+		if d.Severity != SeverityWarning || d.Range.Start.Line != 16 {
+			t.Errorf("diagnostic after fix = %+v", d)
+		}
+	}
+
+	// A broken frontmatter is reported at the YAML error.
+	c.notify("textDocument/didChange", map[string]interface{}{
+		"textDocument":   TextDocumentIdentifier{URI: uri},
+		"contentChanges": []map[string]string{{"text": "---\nadr_id: ADR-0002\ntitle: [unclosed\n---\n"}},
+	})
+	got = c.diagnostics()
+	if len(got.Diagnostics) != 1 || got.Diagnostics[0].Code != "parse_error" {
+		t.Fatalf("parse diagnostics = %+v", got.Diagnostics)
+	}
+
+	c.notify("textDocument/didClose", DidCloseParams{TextDocument: TextDocumentIdentifier{URI: uri}})
+	if got := c.diagnostics(); len(got.Diagnostics) != 0 {
+		t.Errorf("diagnostics after close = %+v", got.Diagnostics)
+	}
+
+	if err := c.stop(true); err != nil {
+		t.Errorf("Serve() error = %v", err)
+	}
+}
+
+func TestCompletionAndDefinition(t *testing.T) {
+	c, root := testServer(t)
+	c.open(filepath.Join(root, "docs", "adr", "0002-add-read-replicas.md"), draftADR)
+	c.diagnostics()
+	uri := pathToURI(filepath.Join(root, "docs", "adr", "0002-add-read-replicas.md"))
+
+	var items []CompletionItem
+	if err := c.call("textDocument/completion", TextDocumentPositionParams{
+		TextDocument: TextDocumentIdentifier{URI: uri},
+		Position:     Position{Line: 6, Character: 4},
+	}, &items); err != nil {
+		t.Fatalf("completion: %v", err)
+	}
+	if len(items) != 1 || items[0].Label != "ADR-0001" || items[0].Detail != "Use PostgreSQL" {
+		t.Errorf("completion items = %+v", items)
+	}
+
+	// Outside the reference fields nothing is offered.
+	if err := c.call("textDocument/completion", TextDocumentPositionParams{
+		TextDocument: TextDocumentIdentifier{URI: uri},
+		Position:     Position{Line: 2, Character: 8},
+	}, &items); err != nil {
+		t.Fatalf("completion: %v", err)
+	}
+	if len(items) != 0 {
+		t.Errorf("completion items in title = %+v", items)
+	}
+
+	var locations []Location
+	if err := c.call("textDocument/definition", TextDocumentPositionParams{
+		TextDocument: TextDocumentIdentifier{URI: uri},
+		Position:     Position{Line: 6, Character: 6},
+	}, &locations); err != nil {
+		t.Fatalf("definition: %v", err)
+	}
+	want := pathToURI(filepath.Join(root, "docs", "adr", "0001-use-postgresql.md"))
+	if len(locations) != 1 || locations[0].URI != want {
+		t.Errorf("definition = %+v, want %s", locations, want)
+	}
+
+	// Unknown IDs have no definition.
+	if err := c.call("textDocument/definition", TextDocumentPositionParams{
+		TextDocument: TextDocumentIdentifier{URI: uri},
+		Position:     Position{Line: 7, Character: 6},
+	}, &locations); err != nil {
+		t.Fatalf("definition: %v", err)
+	}
+	if len(locations) != 0 {
+		t.Errorf("definition of unknown ADR = %+v", locations)
+	}
+
+	if err := c.stop(true); err != nil {
+		t.Errorf("Serve() error = %v", err)
+	}
+}
+
+func TestHover(t *testing.T) {
+	c, root := testServer(t)
+	source := filepath.Join(root, "src", "db", "conn.go")
+	if err := os.MkdirAll(filepath.Dir(source), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(source, []byte("package db\n\n// See ADR-0001.\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	other := filepath.Join(root, "main.go")
+	if err := os.WriteFile(other, []byte("package main\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		path string
+		pos  Position
+		want string // empty for no hover
+	}{
+		{"scoped file", source, Position{Line: 0, Character: 0}, "_Scope: `src/db/**`_"},
+		{"constraints", source, Position{Line: 0, Character: 0}, "- Use prepared statements"},
+		{"adr reference", source, Position{Line: 2, Character: 10}, "**ADR-0001: Use PostgreSQL** (adopted)"},
+		{"unscoped file", other, Position{Line: 0, Character: 0}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var hover *Hover
+			if err := c.call("textDocument/hover", TextDocumentPositionParams{
+				TextDocument: TextDocumentIdentifier{URI: pathToURI(tt.path)},
+				Position:     tt.pos,
+			}, &hover); err != nil {
+				t.Fatalf("hover: %v", err)
+			}
+			if tt.want == "" {
+				if hover != nil {
+					t.Errorf("hover = %+v, want none", hover)
+				}
+				return
+			}
+			if hover == nil || !strings.Contains(hover.Contents.Value, tt.want) {
+				t.Errorf("hover = %+v, want %q", hover, tt.want)
+			}
+		})
+	}
+
+	if err := c.stop(true); err != nil {
+		t.Errorf("Serve() error = %v", err)
+	}
+}
+
+func TestShutdown(t *testing.T) {
+	c, _ := testServer(t)
+	if err := c.call("workspace/symbol", nil, nil); err == nil || err.Code != jsonrpc.CodeMethodNotFound {
+		t.Errorf("unknown method error = %v", err)
+	}
+	if err := c.stop(false); err != errExitWithoutShutdown {
+		t.Errorf("exit without shutdown: Serve() error = %v", err)
+	}
+}
@@ -0,0 +1,205 @@
+// Package mcp implements a Model Context Protocol server over stdio that
+// exposes decider's commands as tools for AI agents.
+package mcp
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/sventorben/decider/internal/cli"
+	"github.com/sventorben/decider/internal/jsonrpc"
+)
+
+// ProtocolVersion is the latest MCP protocol version the server implements.
+const ProtocolVersion = "2025-06-18"
+
+// supportedVersions are the protocol versions the server can speak. A client
+// requesting one of them gets it; any other request gets ProtocolVersion.
+var supportedVersions = []string{ProtocolVersion, "2025-03-26", "2024-11-05"}
+
+// Config holds configuration for the MCP server.
+type Config struct {
+	Dir     string           // ADR directory
+	Format  cli.OutputFormat // Default payload format (toon or json)
+	Version string           // Server version reported to clients
+
+	// Project-specific validation rules for the validate tool.
+	RequiredSections []string
+	AllowedTags      []string
+	IDPrefix         string
+}
+
+// Server answers MCP requests by running decider commands.
+type Server struct {
+	cfg Config
+}
+
+// NewServer creates an MCP server.
+func NewServer(cfg Config) *Server {
+	if cfg.Format != cli.FormatJSON {
+		cfg.Format = cli.FormatTOON
+	}
+	return &Server{cfg: cfg}
+}
+
+// Serve answers newline-delimited JSON-RPC requests from r on w until r ends.
+func (s *Server) Serve(r io.Reader, w io.Writer) error {
+	return jsonrpc.Serve(jsonrpc.NewLineCodec(r, w), s.handle)
+}
+
+func (s *Server) handle(req *jsonrpc.Request) (interface{}, *jsonrpc.Error) {
+	switch req.Method {
+	case "initialize":
+		var params struct {
+			ProtocolVersion string `json:"protocolVersion"`
+		}
+		_ = json.Unmarshal(req.Params, &params)
+		version := ProtocolVersion
+		for _, v := range supportedVersions {
+			if v == params.ProtocolVersion {
+				version = v
+			}
+		}
+		return map[string]interface{}{
+			"protocolVersion": version,
+			"capabilities":    map[string]interface{}{"tools": map[string]interface{}{}},
+			"serverInfo":      map[string]string{"name": "decider", "version": s.cfg.Version},
+			"instructions": "Query the repository's Architecture Decision Records. " +
+				"Call adrs_for_paths before editing files to learn the constraints that apply.",
+		}, nil
+	case "notifications/initialized", "notifications/cancelled":
+		return nil, nil
+	case "ping":
+		return map[string]interface{}{}, nil
+	case "tools/list":
+		return map[string]interface{}{"tools": toolDefinitions}, nil
+	case "tools/call":
+		var params struct {
+			Name      string          `json:"name"`
+			Arguments json.RawMessage `json:"arguments"`
+		}
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return nil, jsonrpc.Errorf(jsonrpc.CodeInvalidParams, "invalid params: %v", err)
+		}
+		return s.callTool(params.Name, params.Arguments)
+	}
+	return nil, jsonrpc.Errorf(jsonrpc.CodeMethodNotFound, "method not found: %s", req.Method)
+}
+
+// ToolResult is the result of a tools/call request.
+type ToolResult struct {
+	Content []Content `json:"content"`
+	IsError bool      `json:"isError,omitempty"`
+}
+
+// Content is a block of tool output.
+type Content struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// toolArgs holds the arguments of all tools; each tool uses a subset.
+type toolArgs struct {
+	Format string   `json:"format"`
+	ID     string   `json:"id"`
+	Status string   `json:"status"`
+	Tags   []string `json:"tags"`
+	Path   string   `json:"path"`
+	Paths  []string `json:"paths"`
+	Base   string   `json:"base"`
+	Strict bool     `json:"strict"`
+}
+
+// callTool runs a tool. Unknown tools and malformed arguments are protocol
+// errors; failures of the command itself are reported as tool errors so the
+// agent can see and react to them.
+func (s *Server) callTool(name string, rawArgs json.RawMessage) (interface{}, *jsonrpc.Error) {
+	var args toolArgs
+	if len(rawArgs) > 0 && string(rawArgs) != "null" {
+		if err := json.Unmarshal(rawArgs, &args); err != nil {
+			return nil, jsonrpc.Errorf(jsonrpc.CodeInvalidParams, "invalid arguments: %v", err)
+		}
+	}
+
+	format := s.cfg.Format
+	switch args.Format {
+	case "":
+	case "toon", "json":
+		format = cli.OutputFormat(args.Format)
+	default:
+		return toolError(fmt.Errorf("invalid format %q (valid: toon, json)", args.Format)), nil
+	}
+
+	var buf bytes.Buffer
+	output := &cli.Output{Format: format, Writer: &buf}
+
+	var err error
+	switch name {
+	case "list_adrs":
+		_, err = cli.RunList(&cli.ListConfig{
+			Dir:    s.cfg.Dir,
+			Status: args.Status,
+			Tags:   args.Tags,
+			Path:   args.Path,
+			Format: format,
+			Output: output,
+		})
+	case "show_adr":
+		if args.ID == "" {
+			return toolError(fmt.Errorf("id is required")), nil
+		}
+		_, err = cli.RunShow(&cli.ShowConfig{ID: args.ID, Dir: s.cfg.Dir, Format: format, Output: output})
+	case "adrs_for_paths":
+		var files []string
+		if files, err = cleanPaths(args.Paths); err == nil {
+			_, err = cli.RunCheckDiff(&cli.CheckDiffConfig{Dir: s.cfg.Dir, Files: files, Format: format, Output: output})
+		}
+	case "check_diff":
+		if args.Base == "" {
+			return toolError(fmt.Errorf("base is required")), nil
+		}
+		_, err = cli.RunCheckDiff(&cli.CheckDiffConfig{Dir: s.cfg.Dir, Base: args.Base, Format: format, Output: output})
+	case "validate":
+		_, err = cli.RunCheckADR(&cli.CheckADRConfig{
+			Dir:              s.cfg.Dir,
+			Strict:           args.Strict,
+			Format:           format,
+			Output:           output,
+			RequiredSections: s.cfg.RequiredSections,
+			AllowedTags:      s.cfg.AllowedTags,
+			IDPrefix:         s.cfg.IDPrefix,
+		})
+	default:
+		return nil, jsonrpc.Errorf(jsonrpc.CodeInvalidParams, "unknown tool: %s", name)
+	}
+
+	if err != nil {
+		return toolError(err), nil
+	}
+	return &ToolResult{Content: []Content{{Type: "text", Text: buf.String()}}}, nil
+}
+
+func toolError(err error) *ToolResult {
+	return &ToolResult{Content: []Content{{Type: "text", Text: "error: " + err.Error()}}, IsError: true}
+}
+
+// cleanPaths normalizes repository-relative paths and rejects paths that
+// leave the repository.
+func cleanPaths(paths []string) ([]string, error) {
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("paths is required")
+	}
+	cleaned := make([]string, 0, len(paths))
+	for _, p := range paths {
+		c := path.Clean(strings.ReplaceAll(p, "\\", "/"))
+		if path.IsAbs(c) || c == ".." || strings.HasPrefix(c, "../") {
+			return nil, fmt.Errorf("path %q must be relative to the repository root", p)
+		}
+		cleaned = append(cleaned, c)
+	}
+	return cleaned, nil
+}
@@ -0,0 +1,222 @@
+package mcp
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/sventorben/decider/internal/cli"
+	"github.com/sventorben/decider/internal/jsonrpc"
+)
+
+// fakeClient talks to a Server running in-process over pipes.
+type fakeClient struct {
+	t      *testing.T
+	codec  *jsonrpc.LineCodec
+	stdin  io.WriteCloser
+	done   chan error
+	nextID int
+}
+
+func startServer(t *testing.T, cfg Config) *fakeClient {
+	t.Helper()
+	serverIn, clientOut := io.Pipe()
+	clientIn, serverOut := io.Pipe()
+
+	c := &fakeClient{
+		t:     t,
+		codec: jsonrpc.NewLineCodec(clientIn, clientOut),
+		stdin: clientOut,
+		done:  make(chan error, 1),
+	}
+	go func() {
+		err := NewServer(cfg).Serve(serverIn, serverOut)
+		_ = serverOut.Close()
+		c.done <- err
+	}()
+	t.Cleanup(func() {
+		_ = c.stdin.Close()
+		if err := <-c.done; err != nil {
+			t.Errorf("Serve() error = %v", err)
+		}
+	})
+	return c
+}
+
+// call sends a request and decodes the response's result into result.
+func (c *fakeClient) call(method string, params interface{}, result interface{}) *jsonrpc.Error {
+	c.t.Helper()
+	c.nextID++
+	rawParams, err := json.Marshal(params)
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	id := json.RawMessage(strconv.Itoa(c.nextID))
+	if err := c.codec.WriteMessage(jsonrpc.Request{JSONRPC: jsonrpc.Version, ID: id, Method: method, Params: rawParams}); err != nil {
+		c.t.Fatalf("writing request: %v", err)
+	}
+
+	msg, err := c.codec.ReadMessage()
+	if err != nil {
+		c.t.Fatalf("reading response: %v", err)
+	}
+	var resp jsonrpc.Response
+	if err := json.Unmarshal(msg, &resp); err != nil {
+		c.t.Fatalf("decoding response %s: %v", msg, err)
+	}
+	if string(resp.ID) != string(id) {
+		c.t.Fatalf("response id = %s, want %s", resp.ID, id)
+	}
+	if resp.Error != nil {
+		return resp.Error
+	}
+	if result != nil {
+		if err := json.Unmarshal(resp.Result, result); err != nil {
+			c.t.Fatalf("decoding result %s: %v", resp.Result, err)
+		}
+	}
+	return nil
+}
+
+// callTool calls a tool and returns its text output.
+func (c *fakeClient) callTool(name string, args map[string]interface{}) (string, bool) {
+	c.t.Helper()
+	var result ToolResult
+	if err := c.call("tools/call", map[string]interface{}{"name": name, "arguments": args}, &result); err != nil {
+		c.t.Fatalf("tools/call %s: %v", name, err)
+	}
+	if len(result.Content) != 1 || result.Content[0].Type != "text" {
+		c.t.Fatalf("tools/call %s content = %+v", name, result.Content)
+	}
+	return result.Content[0].Text, result.IsError
+}
+
+const testADR = `---
+adr_id: ADR-0001
+title: Use PostgreSQL
+status: adopted
+date: 2026-01-16
+scope:
+  paths: ["src/db/**"]
+tags: [database]
+constraints:
+  - "Use prepared statements"
+---
+
+# ADR-0001: Use PostgreSQL
+
+## Context
+
+Context.
+
+## Decision
+
+We use PostgreSQL.
+
+## Alternatives Considered
+
+None.
+
+## Consequences
+
+Consequences.
+`
+
+func testServer(t *testing.T) *fakeClient {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "0001-use-postgresql.md"), []byte(testADR), 0644); err != nil {
+		t.Fatal(err)
+	}
+	c := startServer(t, Config{Dir: dir, Format: cli.FormatJSON, Version: "test"})
+
+	var init struct {
+		ProtocolVersion string `json:"protocolVersion"`
+		ServerInfo      struct {
+			Name string `json:"name"`
+		} `json:"serverInfo"`
+	}
+	if err := c.call("initialize", map[string]interface{}{"protocolVersion": "2024-11-05"}, &init); err != nil {
+		t.Fatalf("initialize: %v", err)
+	}
+	if init.ProtocolVersion != "2024-11-05" || init.ServerInfo.Name != "decider" {
+		t.Fatalf("initialize result = %+v", init)
+	}
+	if err := c.codec.WriteMessage(jsonrpc.Request{JSONRPC: jsonrpc.Version, Method: "notifications/initialized"}); err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func TestToolsList(t *testing.T) {
+	c := testServer(t)
+
+	var result struct {
+		Tools []Tool `json:"tools"`
+	}
+	if err := c.call("tools/list", nil, &result); err != nil {
+		t.Fatalf("tools/list: %v", err)
+	}
+	var names []string
+	for _, tool := range result.Tools {
+		names = append(names, tool.Name)
+	}
+	want := "list_adrs show_adr adrs_for_paths check_diff validate"
+	if got := strings.Join(names, " "); got != want {
+		t.Errorf("tools = %s, want %s", got, want)
+	}
+}
+
+func TestToolCalls(t *testing.T) {
+	c := testServer(t)
+
+	tests := []struct {
+		name    string
+		tool    string
+		args    map[string]interface{}
+		want    string
+		wantErr bool
+	}{
+		{"list", "list_adrs", map[string]interface{}{"status": "adopted"}, `"adr_id": "ADR-0001"`, false},
+		{"list toon", "list_adrs", map[string]interface{}{"format": "toon"}, "adr_id:ADR-0001", false},
+		{"show", "show_adr", map[string]interface{}{"id": "1"}, `"decision": "We use PostgreSQL."`, false},
+		{"show missing", "show_adr", map[string]interface{}{"id": "ADR-0042"}, "error:", true},
+		{"show without id", "show_adr", nil, "id is required", true},
+		{"for paths", "adrs_for_paths", map[string]interface{}{"paths": []string{"src/db/conn.go"}}, `"Use prepared statements"`, false},
+		{"for paths outside repo", "adrs_for_paths", map[string]interface{}{"paths": []string{"../etc/passwd"}}, "must be relative", true},
+		{"check diff bad ref", "check_diff", map[string]interface{}{"base": "--output=x"}, "invalid git ref", true},
+		{"validate", "validate", nil, `"valid": true`, false},
+		{"validate strict", "validate", map[string]interface{}{"strict": true}, `"code": "missing_adopted_because"`, false},
+		{"invalid format", "validate", map[string]interface{}{"format": "xml"}, "invalid format", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			text, isError := c.callTool(tt.tool, tt.args)
+			if isError != tt.wantErr {
+				t.Errorf("isError = %v, want %v: %s", isError, tt.wantErr, text)
+			}
+			if !strings.Contains(text, tt.want) {
+				t.Errorf("output does not contain %q:\n%s", tt.want, text)
+			}
+		})
+	}
+}
+
+func TestProtocolErrors(t *testing.T) {
+	c := testServer(t)
+
+	if err := c.call("resources/list", nil, nil); err == nil || err.Code != jsonrpc.CodeMethodNotFound {
+		t.Errorf("unknown method error = %v", err)
+	}
+	if err := c.call("tools/call", map[string]interface{}{"name": "rm_rf"}, nil); err == nil || err.Code != jsonrpc.CodeInvalidParams {
+		t.Errorf("unknown tool error = %v", err)
+	}
+	if err := c.call("ping", nil, nil); err != nil {
+		t.Errorf("ping error = %v", err)
+	}
+}
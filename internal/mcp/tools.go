@@ -0,0 +1,75 @@
+package mcp
+
+// Tool describes a tool in the tools/list response.
+type Tool struct {
+	Name        string                 `json:"name"`
+	Description string                 `json:"description"`
+	InputSchema map[string]interface{} `json:"inputSchema"`
+}
+
+var formatProperty = map[string]interface{}{
+	"type":        "string",
+	"enum":        []string{"toon", "json"},
+	"description": "Payload format (default: the server's format)",
+}
+
+func stringList(description string) map[string]interface{} {
+	return map[string]interface{}{
+		"type":        "array",
+		"items":       map[string]string{"type": "string"},
+		"description": description,
+	}
+}
+
+func schema(required []string, properties map[string]interface{}) map[string]interface{} {
+	properties["format"] = formatProperty
+	s := map[string]interface{}{
+		"type":       "object",
+		"properties": properties,
+	}
+	if len(required) > 0 {
+		s["required"] = required
+	}
+	return s
+}
+
+// toolDefinitions are the tools offered by the server, in tools/list order.
+var toolDefinitions = []Tool{
+	{
+		Name:        "list_adrs",
+		Description: "List Architecture Decision Records, optionally filtered by status, tags or a file path they apply to.",
+		InputSchema: schema(nil, map[string]interface{}{
+			"status": map[string]string{"type": "string", "description": "Only ADRs with this status (proposed, adopted, rejected, deprecated, superseded)"},
+			"tags":   stringList("Only ADRs with any of these tags"),
+			"path":   map[string]string{"type": "string", "description": "Only ADRs whose scope matches this repository-relative path"},
+		}),
+	},
+	{
+		Name:        "show_adr",
+		Description: "Show an ADR's metadata, constraints, invariants and decision.",
+		InputSchema: schema([]string{"id"}, map[string]interface{}{
+			"id": map[string]string{"type": "string", "description": "ADR ID (e.g. ADR-0001), number or filename"},
+		}),
+	},
+	{
+		Name:        "adrs_for_paths",
+		Description: "Find the ADRs, constraints and invariants that apply to the given files, and any violations of structured constraints. Call this before editing files.",
+		InputSchema: schema([]string{"paths"}, map[string]interface{}{
+			"paths": stringList("Repository-relative file paths"),
+		}),
+	},
+	{
+		Name:        "check_diff",
+		Description: "Find the ADRs that apply to the files changed since a git ref, and any violations of structured constraints.",
+		InputSchema: schema([]string{"base"}, map[string]interface{}{
+			"base": map[string]string{"type": "string", "description": "Base git ref, e.g. main"},
+		}),
+	},
+	{
+		Name:        "validate",
+		Description: "Validate all ADRs: frontmatter, required sections, rationale pattern and references between ADRs.",
+		InputSchema: schema(nil, map[string]interface{}{
+			"strict": map[string]string{"type": "boolean", "description": "Treat warnings as errors"},
+		}),
+	},
+}
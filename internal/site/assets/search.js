@@ -0,0 +1,65 @@
+// Client-side search over window.DECIDER_SEARCH_INDEX, defined by
+// search-index.js. Every term must occur in an ADR for it to match.
+(function () {
+  var input = document.getElementById("search");
+  var results = document.getElementById("search-results");
+  var index = window.DECIDER_SEARCH_INDEX || [];
+  var root = document.currentScript.getAttribute("data-root") || "";
+  var limit = 20;
+
+  var haystacks = index.map(function (entry) {
+    return [entry.id, entry.title, entry.status, (entry.tags || []).join(" "), entry.text]
+      .join(" ")
+      .toLowerCase();
+  });
+
+  function item(entry) {
+    var li = document.createElement("li");
+    var badge = document.createElement("span");
+    badge.className = "badge status-" + entry.status;
+    badge.textContent = entry.status;
+    var link = document.createElement("a");
+    link.href = root + entry.url;
+    link.textContent = entry.id + ": " + entry.title;
+    li.appendChild(badge);
+    li.appendChild(document.createTextNode(" "));
+    li.appendChild(link);
+    return li;
+  }
+
+  function search() {
+    var terms = input.value.toLowerCase().split(/\s+/).filter(Boolean);
+    results.textContent = "";
+    if (terms.length === 0) {
+      results.hidden = true;
+      return;
+    }
+
+    var found = 0;
+    for (var i = 0; i < index.length && found < limit; i++) {
+      var haystack = haystacks[i];
+      var matches = terms.every(function (term) {
+        return haystack.indexOf(term) >= 0;
+      });
+      if (matches) {
+        results.appendChild(item(index[i]));
+        found++;
+      }
+    }
+    if (found === 0) {
+      var empty = document.createElement("li");
+      empty.className = "empty";
+      empty.textContent = "No matching decisions";
+      results.appendChild(empty);
+    }
+    results.hidden = false;
+  }
+
+  input.addEventListener("input", search);
+  input.addEventListener("keydown", function (event) {
+    if (event.key === "Escape") {
+      input.value = "";
+      search();
+    }
+  });
+})();
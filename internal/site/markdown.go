@@ -0,0 +1,405 @@
+package site
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// The Markdown renderer covers what ADRs use: ATX headings, paragraphs,
+// nested lists with task items, fenced code blocks, block quotes, tables,
+// horizontal rules, and inline code, emphasis and links. Raw HTML is
+// escaped, not passed through, so ADR content cannot inject markup.
+
+var (
+	headingRe   = regexp.MustCompile(`^(#{1,6})\s+(.*?)(?:\s+#+)?\s*$`)
+	ruleRe      = regexp.MustCompile(`^\s{0,3}(?:(?:\*\s*){3,}|(?:-\s*){3,}|(?:_\s*){3,})$`)
+	listItemRe  = regexp.MustCompile(`^(\s*)([-*+]|\d{1,9}[.)])\s+(.*)$`)
+	tableSepRe  = regexp.MustCompile(`^\s*\|?\s*:?-+:?\s*(?:\|\s*:?-+:?\s*)*\|?\s*$`)
+	taskRe      = regexp.MustCompile(`^\[([ xX])\]\s+`)
+	linkRe      = regexp.MustCompile(`^\[([^\]]*)\]\(\s*([^()\s]*(?:\([^()\s]*\)[^()\s]*)*)(?:\s+"[^"]*")?\s*\)`)
+	safeSchemes = []string{"http://", "https://", "mailto:"}
+)
+
+// markdown renders Markdown to HTML. Link targets are passed through link,
+// which may rewrite them; unsafe targets such as javascript: URLs are
+// dropped. Heading IDs are unique within one renderer.
+type markdown struct {
+	link func(string) string
+	ids  map[string]int
+}
+
+func newMarkdown(link func(string) string) *markdown {
+	if link == nil {
+		link = func(s string) string { return s }
+	}
+	return &markdown{link: link, ids: make(map[string]int)}
+}
+
+// render converts a Markdown document to HTML.
+func (m *markdown) render(src string) string {
+	lines := strings.Split(strings.ReplaceAll(src, "\r\n", "\n"), "\n")
+	var b strings.Builder
+	m.blocks(&b, lines)
+	return b.String()
+}
+
+func (m *markdown) blocks(b *strings.Builder, lines []string) {
+	var para []string
+	flush := func() {
+		if len(para) > 0 {
+			b.WriteString("<p>" + m.inline(strings.Join(para, "\n")) + "</p>\n")
+			para = nil
+		}
+	}
+
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+
+		switch {
+		case trimmed == "":
+			flush()
+
+		case strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~"):
+			flush()
+			fence := trimmed[:3]
+			lang := strings.TrimSpace(strings.TrimLeft(trimmed, fence[:1]))
+			var code []string
+			for i++; i < len(lines) && !strings.HasPrefix(strings.TrimSpace(lines[i]), fence); i++ {
+				code = append(code, lines[i])
+			}
+			class := ""
+			if lang != "" {
+				class = fmt.Sprintf(` class="language-%s"`, html.EscapeString(strings.Fields(lang)[0]))
+			}
+			fmt.Fprintf(b, "<pre><code%s>%s</code></pre>\n", class, html.EscapeString(strings.Join(code, "\n")))
+
+		case headingRe.MatchString(trimmed) && !strings.HasPrefix(line, "    "):
+			flush()
+			match := headingRe.FindStringSubmatch(trimmed)
+			level := len(match[1])
+			fmt.Fprintf(b, "<h%d id=\"%s\">%s</h%d>\n", level, m.headingID(match[2]), m.inline(match[2]), level)
+
+		case ruleRe.MatchString(line):
+			flush()
+			b.WriteString("<hr>\n")
+
+		case strings.HasPrefix(trimmed, ">"):
+			flush()
+			var quote []string
+			for ; i < len(lines) && strings.HasPrefix(strings.TrimSpace(lines[i]), ">"); i++ {
+				q := strings.TrimPrefix(strings.TrimSpace(lines[i]), ">")
+				quote = append(quote, strings.TrimPrefix(q, " "))
+			}
+			i--
+			b.WriteString("<blockquote>\n")
+			m.blocks(b, quote)
+			b.WriteString("</blockquote>\n")
+
+		case strings.Contains(line, "|") && i+1 < len(lines) && tableSepRe.MatchString(lines[i+1]) && strings.Contains(lines[i+1], "-"):
+			flush()
+			i = m.table(b, lines, i)
+
+		case listItemRe.MatchString(line) && (len(para) == 0 || interruptsParagraph(line)):
+			flush()
+			i = m.list(b, lines, i)
+
+		default:
+			para = append(para, trimmed)
+		}
+	}
+	flush()
+}
+
+// list renders the list starting at lines[start] and returns the index of
+// its last line. Items are rendered recursively, so indented lines nest.
+func (m *markdown) list(b *strings.Builder, lines []string, start int) int {
+	first := listItemRe.FindStringSubmatch(lines[start])
+	indent := len(first[1])
+	ordered := unicode.IsDigit(rune(first[2][0]))
+	tag := "ul"
+	if ordered {
+		tag = "ol"
+	}
+
+	var items [][]string
+	offset := 0 // Indentation of the current item's content
+	i := start
+	for ; i < len(lines); i++ {
+		line := lines[i]
+		if match := listItemRe.FindStringSubmatch(line); match != nil && len(match[1]) == indent {
+			if unicode.IsDigit(rune(match[2][0])) != ordered {
+				break
+			}
+			items = append(items, []string{match[3]})
+			offset = len(match[0]) - len(match[3])
+			continue
+		}
+		if strings.TrimSpace(line) == "" {
+			// A blank line continues the list only if it goes on below
+			next := i + 1
+			for next < len(lines) && strings.TrimSpace(lines[next]) == "" {
+				next++
+			}
+			if next < len(lines) && leadingSpaces(lines[next]) > indent {
+				items[len(items)-1] = append(items[len(items)-1], "")
+				continue
+			}
+			break
+		}
+		if leadingSpaces(line) <= indent && !isBlockStart(line) && len(items[len(items)-1]) == 1 {
+			// Lazy continuation of the item's first paragraph
+			items[len(items)-1] = append(items[len(items)-1], strings.TrimSpace(line))
+			continue
+		}
+		if leadingSpaces(line) <= indent {
+			break
+		}
+		items[len(items)-1] = append(items[len(items)-1], dedent(line, offset))
+	}
+
+	if n := strings.TrimLeft(strings.TrimRight(first[2], ".)"), "0"); ordered && n != "1" {
+		if n == "" {
+			n = "0"
+		}
+		fmt.Fprintf(b, "<ol start=\"%s\">\n", n)
+	} else {
+		fmt.Fprintf(b, "<%s>\n", tag)
+	}
+	for _, item := range items {
+		b.WriteString("<li>")
+		if match := taskRe.FindStringSubmatch(item[0]); match != nil {
+			checked := ""
+			if match[1] != " " {
+				checked = " checked"
+			}
+			fmt.Fprintf(b, `<input type="checkbox" disabled%s> `, checked)
+			item[0] = item[0][len(match[0]):]
+		}
+		var inner strings.Builder
+		m.blocks(&inner, item)
+		content := strings.TrimSuffix(inner.String(), "\n")
+		// A single paragraph is rendered tight, without <p>
+		if strings.HasPrefix(content, "<p>") && strings.Count(content, "<p>") == 1 {
+			content = strings.Replace(strings.Replace(content, "<p>", "", 1), "</p>", "", 1)
+		}
+		b.WriteString(content + "</li>\n")
+	}
+	fmt.Fprintf(b, "</%s>\n", tag)
+	return i - 1
+}
+
+// table renders the table starting at lines[start] and returns the index
+// of its last line.
+func (m *markdown) table(b *strings.Builder, lines []string, start int) int {
+	b.WriteString("<table>\n<thead>\n<tr>")
+	for _, cell := range tableCells(lines[start]) {
+		b.WriteString("<th>" + m.inline(cell) + "</th>")
+	}
+	b.WriteString("</tr>\n</thead>\n<tbody>\n")
+	i := start + 2
+	for ; i < len(lines) && strings.Contains(lines[i], "|") && strings.TrimSpace(lines[i]) != ""; i++ {
+		b.WriteString("<tr>")
+		for _, cell := range tableCells(lines[i]) {
+			b.WriteString("<td>" + m.inline(cell) + "</td>")
+		}
+		b.WriteString("</tr>\n")
+	}
+	b.WriteString("</tbody>\n</table>\n")
+	return i - 1
+}
+
+// tableCells splits a table row at pipes; as in GFM, an escaped pipe \|
+// stays in the cell, even in a code span.
+func tableCells(line string) []string {
+	line = strings.TrimSpace(line)
+	line = strings.TrimPrefix(line, "|")
+	if strings.HasSuffix(line, "|") && !strings.HasSuffix(line, "\\|") {
+		line = line[:len(line)-1]
+	}
+	var cells []string
+	cell := 0
+	for i := 0; i <= len(line); i++ {
+		if i == len(line) || line[i] == '|' && (i == 0 || line[i-1] != '\\') {
+			cells = append(cells, strings.TrimSpace(strings.ReplaceAll(line[cell:i], "\\|", "|")))
+			cell = i + 1
+		}
+	}
+	return cells
+}
+
+// inline renders code spans, emphasis, links and backslash escapes, and
+// escapes everything else.
+func (m *markdown) inline(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == '\\' && i+1 < len(s) && strings.IndexByte("\\`*_[]()#+-.!|<>", s[i+1]) >= 0:
+			b.WriteString(html.EscapeString(s[i+1 : i+2]))
+			i += 2
+			continue
+
+		case c == '`':
+			run := 1
+			for i+run < len(s) && s[i+run] == '`' {
+				run++
+			}
+			delim := s[i : i+run]
+			if end := strings.Index(s[i+run:], delim); end >= 0 {
+				code := strings.TrimSpace(s[i+run : i+run+end])
+				b.WriteString("<code>" + html.EscapeString(code) + "</code>")
+				i += run + end + run
+				continue
+			}
+
+		case c == '[':
+			if match := linkRe.FindStringSubmatch(s[i:]); match != nil {
+				text := m.inline(match[1])
+				if href := m.href(match[2]); href != "" {
+					fmt.Fprintf(&b, `<a href="%s">%s</a>`, html.EscapeString(href), text)
+				} else {
+					b.WriteString(text)
+				}
+				i += len(match[0])
+				continue
+			}
+
+		case c == '*' || c == '_':
+			if c == '_' && i > 0 && isWordByte(s[i-1]) {
+				break
+			}
+			delim := string(c)
+			tag := "em"
+			if i+1 < len(s) && s[i+1] == c {
+				delim, tag = delim+delim, "strong"
+			}
+			rest := s[i+len(delim):]
+			if end := closingDelimiter(rest, delim); end > 0 {
+				fmt.Fprintf(&b, "<%s>%s</%s>", tag, m.inline(rest[:end]), tag)
+				i += len(delim) + end + len(delim)
+				continue
+			}
+		}
+		b.WriteString(html.EscapeString(s[i : i+1]))
+		i++
+	}
+	return b.String()
+}
+
+// closingDelimiter returns the index in s of the delimiter closing an
+// emphasis, or -1. The emphasized text must not start or end with a space.
+func closingDelimiter(s, delim string) int {
+	if s == "" || s[0] == ' ' {
+		return -1
+	}
+	for i := 1; i+len(delim) <= len(s); i++ {
+		if s[i] == '`' {
+			// Skip code spans
+			if end := strings.IndexByte(s[i+1:], '`'); end >= 0 {
+				i += end + 1
+				continue
+			}
+		}
+		if strings.HasPrefix(s[i:], delim) && s[i-1] != ' ' {
+			after := i + len(delim)
+			if after < len(s) && s[after] == delim[0] {
+				continue
+			}
+			if delim[0] == '_' && after < len(s) && isWordByte(s[after]) {
+				continue
+			}
+			return i
+		}
+	}
+	return -1
+}
+
+// href returns the link target to use for dest, or "" for unsafe targets.
+func (m *markdown) href(dest string) string {
+	lower := strings.ToLower(dest)
+	if i := strings.IndexAny(lower, ":/?#"); i >= 0 && lower[i] == ':' {
+		safe := false
+		for _, scheme := range safeSchemes {
+			if strings.HasPrefix(lower, scheme) {
+				safe = true
+			}
+		}
+		if !safe {
+			return ""
+		}
+		return dest
+	}
+	return m.link(dest)
+}
+
+// headingID returns a unique anchor for a heading.
+func (m *markdown) headingID(text string) string {
+	id := slug(text)
+	if id == "" {
+		id = "section"
+	}
+	n := m.ids[id]
+	m.ids[id]++
+	if n > 0 {
+		id = fmt.Sprintf("%s-%d", id, n)
+	}
+	return id
+}
+
+// slug lowercases s and replaces every run of characters other than
+// letters and digits with a single hyphen.
+func slug(s string) string {
+	var b strings.Builder
+	hyphen := false
+	for _, r := range strings.ToLower(s) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if hyphen && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			b.WriteRune(r)
+			hyphen = false
+		} else {
+			hyphen = true
+		}
+	}
+	return b.String()
+}
+
+// isBlockStart reports whether line starts a block other than a paragraph.
+func isBlockStart(line string) bool {
+	trimmed := strings.TrimSpace(line)
+	return headingRe.MatchString(trimmed) || ruleRe.MatchString(line) || listItemRe.MatchString(line) ||
+		strings.HasPrefix(trimmed, ">") || strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~")
+}
+
+// interruptsParagraph reports whether the list item line may start a list
+// right after a paragraph line: as in GitHub's Markdown, a bullet or an
+// ordered item numbered 1 may.
+func interruptsParagraph(line string) bool {
+	match := listItemRe.FindStringSubmatch(line)
+	if strings.TrimSpace(match[3]) == "" {
+		return false
+	}
+	n := strings.TrimRight(match[2], ".)")
+	return !unicode.IsDigit(rune(n[0])) || strings.TrimLeft(n, "0") == "1"
+}
+
+func isWordByte(c byte) bool {
+	return c == '_' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= 0x80
+}
+
+func leadingSpaces(s string) int {
+	return len(s) - len(strings.TrimLeft(s, " \t"))
+}
+
+// dedent removes up to n leading spaces from line.
+func dedent(line string, n int) string {
+	for i := 0; i < n && strings.HasPrefix(line, " "); i++ {
+		line = line[1:]
+	}
+	return line
+}
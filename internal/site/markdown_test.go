@@ -0,0 +1,50 @@
+package site
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMarkdownRender(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{"heading", "## Decision Outcome", `<h2 id="decision-outcome">Decision Outcome</h2>`},
+		{"duplicate headings", "## Positive\n## Positive", `<h2 id="positive">Positive</h2>` + "\n" + `<h2 id="positive-1">Positive</h2>`},
+		{"paragraph", "One\ntwo\n\nThree", "<p>One\ntwo</p>\n<p>Three</p>"},
+		{"emphasis", "**Adopted because:** it is *fast*", "<p><strong>Adopted because:</strong> it is <em>fast</em></p>"},
+		{"intraword underscores", "snake_case_name", "<p>snake_case_name</p>"},
+		{"code span", "Use `<T>` here", "<p>Use <code>&lt;T&gt;</code> here</p>"},
+		{"escaped html", "<script>alert(1)</script>", "<p>&lt;script&gt;alert(1)&lt;/script&gt;</p>"},
+		{"link", "[docs](https://example.com/a?b=1&c=2)", `<p><a href="https://example.com/a?b=1&amp;c=2">docs</a></p>`},
+		{"link with parentheses", "[Foo](https://en.wikipedia.org/wiki/Foo_(bar))", `<p><a href="https://en.wikipedia.org/wiki/Foo_(bar)">Foo</a></p>`},
+		{"unsafe link", "[click](javascript:alert(1))", "<p>click</p>"},
+		{"fence", "```go\nif a < b {}\n```", `<pre><code class="language-go">if a &lt; b {}</code></pre>`},
+		{"rule", "---", "<hr>"},
+		{"blockquote", "> Quoted", "<blockquote>\n<p>Quoted</p>\n</blockquote>"},
+		{"list", "- a\n- b", "<ul>\n<li>a</li>\n<li>b</li>\n</ul>"},
+		{"nested list", "- a\n  - b\n- c", "<ul>\n<li>a\n<ul>\n<li>b</li>\n</ul></li>\n<li>c</li>\n</ul>"},
+		{"ordered list", "3. a\n4. b", "<ol start=\"3\">\n<li>a</li>\n<li>b</li>\n</ol>"},
+		{"list after paragraph", "Because:\n- a", "<p>Because:</p>\n<ul>\n<li>a</li>\n</ul>"},
+		{"number in paragraph", "Released in\n2. quarter", "<p>Released in\n2. quarter</p>"},
+		{"task list", "- [x] done\n- [ ] open", "<ul>\n<li><input type=\"checkbox\" disabled checked> done</li>\n<li><input type=\"checkbox\" disabled> open</li>\n</ul>"},
+		{"table", "| A | B |\n|---|---|\n| 1 | `x\\|y` |", "<table>\n<thead>\n<tr><th>A</th><th>B</th></tr>\n</thead>\n<tbody>\n<tr><td>1</td><td><code>x|y</code></td></tr>\n</tbody>\n</table>"},
+	}
+	for _, tt := range tests {
+		got := strings.TrimSuffix(newMarkdown(nil).render(tt.src), "\n")
+		if got != tt.want {
+			t.Errorf("%s: render(%q) =\n%s\nwant\n%s", tt.name, tt.src, got, tt.want)
+		}
+	}
+}
+
+func TestMarkdownLinkRewrite(t *testing.T) {
+	md := newMarkdown(func(dest string) string { return strings.TrimSuffix(dest, ".md") + ".html" })
+	got := md.render("See [ADR-0002](0002-api.md).")
+	want := "<p>See <a href=\"0002-api.html\">ADR-0002</a>.</p>\n"
+	if got != want {
+		t.Errorf("render() = %q, want %q", got, want)
+	}
+}
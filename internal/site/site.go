@@ -0,0 +1,380 @@
+// Package site renders ADRs into a static HTML decision log: a page per
+// ADR, an index, tag pages, a supersession timeline and a client-side
+// search. The output is self-contained, so it works offline from the file
+// system, and deterministic, so it can be committed and diffed.
+package site
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/sventorben/decider/internal/adr"
+	"github.com/sventorben/decider/internal/index"
+)
+
+//go:embed templates/*.html assets/*
+var content embed.FS
+
+// DefaultTitle is the site title unless configured otherwise.
+const DefaultTitle = "Architecture Decisions"
+
+// Options configure the generated site.
+type Options struct {
+	Title string // Site title; DefaultTitle when empty
+}
+
+// File is a generated file, with its path relative to the site root in
+// slash form.
+type File struct {
+	Path string
+	Data []byte
+}
+
+// adrView is an ADR as the templates show it.
+type adrView struct {
+	ID           string
+	Title        string
+	Status       string
+	Date         string
+	StatusDate   string
+	File         string
+	URL          string // Relative to the site root
+	Tags         []*tagView
+	Scope        []string
+	Constraints  []string
+	Invariants   []string
+	Supersedes   []ref
+	SupersededBy []ref
+	Related      []ref
+	Body         template.HTML
+}
+
+// ref links to an ADR by ID; URL is empty for ADRs that do not exist.
+type ref struct {
+	ID    string
+	Title string
+	URL   string
+}
+
+type tagView struct {
+	Name string
+	URL  string
+	ADRs []*adrView
+}
+
+type statusCount struct {
+	Status string
+	Count  int
+}
+
+// page is the data of every template. Root is the relative path from the
+// page to the site root, so links work without a web server.
+type page struct {
+	Site     string
+	Title    string
+	Root     string
+	Nav      string
+	ADRs     []*adrView
+	ADR      *adrView
+	Tags     []*tagView
+	Tag      *tagView
+	Statuses []statusCount
+	Chains   [][]ref
+}
+
+// searchEntry is an ADR in the search index.
+type searchEntry struct {
+	ID     string   `json:"id"`
+	Title  string   `json:"title"`
+	Status string   `json:"status"`
+	Tags   []string `json:"tags,omitempty"`
+	URL    string   `json:"url"`
+	Text   string   `json:"text"`
+}
+
+// Render generates the site for adrs, in the order given. Files are sorted
+// by path.
+func Render(adrs []*adr.ADR, opts Options) ([]File, error) {
+	if opts.Title == "" {
+		opts.Title = DefaultTitle
+	}
+
+	views, tags := buildViews(adrs)
+	r, err := newRenderer(opts.Title)
+	if err != nil {
+		return nil, err
+	}
+
+	r.page("index.html", "index.html", page{Title: opts.Title, Nav: "index", ADRs: views, Statuses: statusCounts(views)})
+	for _, v := range views {
+		r.page(v.URL, "adr.html", page{Title: v.ID + ": " + v.Title, Root: "../", ADR: v})
+	}
+	r.page("tags/index.html", "tags.html", page{Title: "Tags", Root: "../", Nav: "tags", Tags: tags})
+	for _, t := range tags {
+		r.page(t.URL, "tag.html", page{Title: "Tag: " + t.Name, Root: "../", Nav: "tags", Tag: t, ADRs: t.ADRs})
+	}
+	r.page("timeline.html", "timeline.html", page{Title: "Timeline", Nav: "timeline", ADRs: timeline(views), Chains: supersessionChains(adrs, views)})
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	search, err := searchIndex(adrs, views)
+	if err != nil {
+		return nil, err
+	}
+	r.files = append(r.files, File{Path: "search-index.js", Data: search})
+
+	for _, name := range []string{"style.css", "search.js"} {
+		data, err := content.ReadFile("assets/" + name)
+		if err != nil {
+			return nil, err
+		}
+		r.files = append(r.files, File{Path: "assets/" + name, Data: data})
+	}
+
+	sort.Slice(r.files, func(i, j int) bool { return r.files[i].Path < r.files[j].Path })
+	return r.files, nil
+}
+
+// renderer executes the page templates, keeping the first error.
+type renderer struct {
+	site  string
+	base  *template.Template
+	files []File
+	err   error
+}
+
+func newRenderer(site string) (*renderer, error) {
+	base, err := template.ParseFS(content, "templates/layout.html")
+	if err != nil {
+		return nil, fmt.Errorf("parsing layout: %w", err)
+	}
+	return &renderer{site: site, base: base}, nil
+}
+
+func (r *renderer) page(filePath, name string, data page) {
+	if r.err != nil {
+		return
+	}
+	t, err := r.base.Clone()
+	if err == nil {
+		t, err = t.ParseFS(content, "templates/"+name)
+	}
+	if err != nil {
+		r.err = fmt.Errorf("parsing %s: %w", name, err)
+		return
+	}
+
+	data.Site = r.site
+	var buf bytes.Buffer
+	if err := t.ExecuteTemplate(&buf, "layout", data); err != nil {
+		r.err = fmt.Errorf("rendering %s: %w", filePath, err)
+		return
+	}
+	r.files = append(r.files, File{Path: filePath, Data: buf.Bytes()})
+}
+
+// buildViews prepares the ADRs and tags for the templates. The listing
+// data comes from the index entries; tags are sorted by name.
+func buildViews(adrs []*adr.ADR) ([]*adrView, []*tagView) {
+	entries := index.Generate(adrs).ADRs
+	byID := make(map[string]*adrView)
+	byFile := make(map[string]string)
+	views := make([]*adrView, len(adrs))
+	for i, e := range entries {
+		views[i] = &adrView{
+			ID:     e.ADRID,
+			Title:  e.Title,
+			Status: e.Status,
+			Date:   e.Date,
+			File:   e.File,
+			URL:    "adr/" + strings.TrimSuffix(e.File, ".md") + ".html",
+			Scope:  e.ScopePaths,
+		}
+		if _, ok := byID[e.ADRID]; !ok {
+			byID[e.ADRID] = views[i]
+		}
+		byFile[e.File] = views[i].URL
+	}
+
+	refs := func(ids []string) []ref {
+		var result []ref
+		for _, id := range ids {
+			r := ref{ID: id}
+			if v, ok := byID[id]; ok {
+				r.Title, r.URL = v.Title, v.URL
+			}
+			result = append(result, r)
+		}
+		return result
+	}
+
+	tagsByName := make(map[string]*tagView)
+	for i, a := range adrs {
+		v := views[i]
+		v.StatusDate = a.Frontmatter.StatusDate
+		v.Constraints = adr.ConstraintStrings(a.Frontmatter.Constraints)
+		v.Invariants = a.Frontmatter.Invariants
+		v.Supersedes = refs(a.Frontmatter.Supersedes)
+		v.SupersededBy = refs(a.Frontmatter.SupersededBy)
+		v.Related = refs(a.Frontmatter.RelatedADRs)
+
+		// Links to other ADR files point to their pages; both live in adr/
+		md := newMarkdown(func(dest string) string {
+			target, anchor, _ := strings.Cut(dest, "#")
+			if url, ok := byFile[path.Base(target)]; ok && path.Dir(target) == "." {
+				dest = path.Base(url)
+				if anchor != "" {
+					dest += "#" + anchor
+				}
+			}
+			return dest
+		})
+		v.Body = template.HTML(md.render(stripTitle(a.Body)))
+
+		for _, name := range a.Frontmatter.Tags {
+			t, ok := tagsByName[name]
+			if !ok {
+				t = &tagView{Name: name}
+				tagsByName[name] = t
+			}
+			t.ADRs = append(t.ADRs, v)
+			v.Tags = append(v.Tags, t)
+		}
+	}
+
+	tags := make([]*tagView, 0, len(tagsByName))
+	for _, t := range tagsByName {
+		tags = append(tags, t)
+	}
+	sort.Slice(tags, func(i, j int) bool { return tags[i].Name < tags[j].Name })
+
+	// Tags that differ only in case or punctuation get numbered pages
+	used := make(map[string]int)
+	for _, t := range tags {
+		name := slug(t.Name)
+		if name == "" {
+			name = "tag"
+		}
+		if used[name]++; used[name] > 1 {
+			name = fmt.Sprintf("%s-%d", name, used[name])
+		}
+		t.URL = "tags/" + name + ".html"
+	}
+	return views, tags
+}
+
+// stripTitle removes the leading "# Title" heading of an ADR body, which
+// the page shows in its header.
+func stripTitle(body string) string {
+	trimmed := strings.TrimLeft(body, " \t\r\n")
+	if !strings.HasPrefix(trimmed, "# ") {
+		return body
+	}
+	if _, rest, ok := strings.Cut(trimmed, "\n"); ok {
+		return rest
+	}
+	return ""
+}
+
+// statusCounts counts the ADRs by status, in lifecycle order.
+func statusCounts(views []*adrView) []statusCount {
+	var counts []statusCount
+	for _, s := range adr.ValidStatuses() {
+		n := 0
+		for _, v := range views {
+			if v.Status == string(s) {
+				n++
+			}
+		}
+		if n > 0 {
+			counts = append(counts, statusCount{Status: string(s), Count: n})
+		}
+	}
+	return counts
+}
+
+// timeline orders the ADRs by date, oldest first; ADRs of the same date
+// keep their order.
+func timeline(views []*adrView) []*adrView {
+	sorted := append([]*adrView(nil), views...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Date < sorted[j].Date })
+	return sorted
+}
+
+// supersessionChains lists, for each ADR that was superseded but supersedes
+// nothing itself, the ADRs reached by following superseded_by links,
+// breadth-first. Cycles and missing ADRs end a chain.
+func supersessionChains(adrs []*adr.ADR, views []*adrView) [][]ref {
+	byID := make(map[string]int)
+	for i, a := range adrs {
+		if _, ok := byID[a.Frontmatter.ADRID]; !ok {
+			byID[a.Frontmatter.ADRID] = i
+		}
+	}
+	refOf := func(i int) ref { return ref{ID: views[i].ID, Title: views[i].Title, URL: views[i].URL} }
+
+	var chains [][]ref
+	for i, a := range adrs {
+		if len(a.Frontmatter.SupersededBy) == 0 {
+			continue
+		}
+		root := true
+		for _, id := range a.Frontmatter.Supersedes {
+			if _, ok := byID[id]; ok {
+				root = false
+			}
+		}
+		if !root {
+			continue
+		}
+
+		chain := []ref{refOf(i)}
+		seen := map[int]bool{i: true}
+		queue := []int{i}
+		for len(queue) > 0 {
+			current := queue[0]
+			queue = queue[1:]
+			for _, id := range adrs[current].Frontmatter.SupersededBy {
+				next, ok := byID[id]
+				if !ok || seen[next] {
+					continue
+				}
+				seen[next] = true
+				chain = append(chain, refOf(next))
+				queue = append(queue, next)
+			}
+		}
+		chains = append(chains, chain)
+	}
+	return chains
+}
+
+// searchIndex returns a script defining the search index, so that search
+// works from the file system, where pages cannot fetch JSON.
+func searchIndex(adrs []*adr.ADR, views []*adrView) ([]byte, error) {
+	entries := make([]searchEntry, len(views))
+	for i, v := range views {
+		text := append(append([]string{}, v.Constraints...), v.Invariants...)
+		text = append(text, adrs[i].Body)
+		entries[i] = searchEntry{
+			ID:     v.ID,
+			Title:  v.Title,
+			Status: v.Status,
+			Tags:   adrs[i].Frontmatter.Tags,
+			URL:    v.URL,
+			Text:   strings.Join(strings.Fields(strings.Join(text, " ")), " "),
+		}
+	}
+	data, err := json.Marshal(entries)
+	if err != nil {
+		return nil, fmt.Errorf("encoding search index: %w", err)
+	}
+	return []byte("window.DECIDER_SEARCH_INDEX = " + string(data) + ";\n"), nil
+}
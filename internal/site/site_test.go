@@ -0,0 +1,186 @@
+package site
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/sventorben/decider/internal/adr"
+)
+
+func testADRs() []*adr.ADR {
+	return []*adr.ADR{
+		{
+			Filename: "0001-use-mysql.md",
+			Body:     "# ADR-0001: Use MySQL\n\n## Decision\n\nUse MySQL.\n",
+			Frontmatter: adr.Frontmatter{
+				ADRID:        "ADR-0001",
+				Title:        "Use MySQL",
+				Status:       adr.StatusSuperseded,
+				Date:         "2026-01-10",
+				Tags:         []string{"database"},
+				SupersededBy: []string{"ADR-0002"},
+			},
+		},
+		{
+			Filename: "0002-use-postgresql.md",
+			Body:     "# ADR-0002: Use PostgreSQL\n\n## Decision\n\nUse PostgreSQL instead of [MySQL](0001-use-mysql.md#decision).\n",
+			Frontmatter: adr.Frontmatter{
+				ADRID:       "ADR-0002",
+				Title:       "Use PostgreSQL",
+				Status:      adr.StatusAdopted,
+				Date:        "2026-01-12",
+				Tags:        []string{"database", "Storage & Backup"},
+				Scope:       adr.Scope{Paths: []string{"db/**"}},
+				Constraints: []adr.Constraint{{Text: "Queries are parameterized"}},
+				Supersedes:  []string{"ADR-0001"},
+			},
+		},
+		{
+			Filename: "0003-rest-api.md",
+			Body:     "# ADR-0003: REST API\n\n## Decision\n\nUse <REST>.\n",
+			Frontmatter: adr.Frontmatter{
+				ADRID:       "ADR-0003",
+				Title:       "REST API",
+				Status:      adr.StatusProposed,
+				Date:        "2026-01-08",
+				RelatedADRs: []string{"ADR-0002", "ADR-0099"},
+			},
+		},
+	}
+}
+
+func render(t *testing.T, adrs []*adr.ADR) map[string]string {
+	t.Helper()
+	files, err := Render(adrs, Options{Title: "Decisions"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	pages := make(map[string]string)
+	for _, f := range files {
+		pages[f.Path] = string(f.Data)
+	}
+	return pages
+}
+
+func TestRender(t *testing.T) {
+	pages := render(t, testADRs())
+
+	var paths []string
+	for path := range pages {
+		paths = append(paths, path)
+	}
+	wantPaths := []string{
+		"adr/0001-use-mysql.html",
+		"adr/0002-use-postgresql.html",
+		"adr/0003-rest-api.html",
+		"assets/search.js",
+		"assets/style.css",
+		"index.html",
+		"search-index.js",
+		"tags/database.html",
+		"tags/index.html",
+		"tags/storage-backup.html",
+		"timeline.html",
+	}
+	if len(paths) != len(wantPaths) {
+		t.Fatalf("paths = %v, want %v", paths, wantPaths)
+	}
+	for _, path := range wantPaths {
+		if _, ok := pages[path]; !ok {
+			t.Errorf("missing %s", path)
+		}
+	}
+
+	tests := []struct {
+		path string
+		want []string
+	}{
+		{"index.html", []string{
+			"<title>Decisions</title>",
+			`<a href="adr/0002-use-postgresql.html">Use PostgreSQL</a>`,
+			`<span class="badge status-superseded">superseded</span>`,
+			`<a class="tag" href="tags/storage-backup.html">Storage &amp; Backup</a>`,
+		}},
+		{"adr/0002-use-postgresql.html", []string{
+			`<link rel="stylesheet" href="../assets/style.css">`,
+			"<li>Queries are parameterized</li>",
+			`<a href="0001-use-mysql.html#decision">MySQL</a>`,
+			`<h2 id="decision">Decision</h2>`,
+		}},
+		{"adr/0001-use-mysql.html", []string{`class="notice"`, `href="../adr/0002-use-postgresql.html"`}},
+		{"adr/0003-rest-api.html", []string{"Use &lt;REST&gt;.", "ADR-0099"}},
+		{"tags/database.html", []string{"0001-use-mysql.html", "0002-use-postgresql.html"}},
+		{"timeline.html", []string{`ADR-0001</a> <span class="arrow">→</span> <a href="adr/0002-use-postgresql.html" title="Use PostgreSQL">ADR-0002</a>`}},
+		{"search-index.js", []string{"window.DECIDER_SEARCH_INDEX = [", `"url":"adr/0002-use-postgresql.html"`, "Queries are parameterized"}},
+	}
+	for _, tt := range tests {
+		for _, want := range tt.want {
+			if !strings.Contains(pages[tt.path], want) {
+				t.Errorf("%s lacks %q", tt.path, want)
+			}
+		}
+	}
+
+	// The title is the page heading, not repeated in the body
+	if strings.Contains(pages["adr/0002-use-postgresql.html"], "<h1 id=") {
+		t.Error("ADR title heading rendered in the body")
+	}
+	if strings.Contains(pages["tags/storage-backup.html"], "0003-rest-api.html") {
+		t.Error("tag page lists an untagged ADR")
+	}
+}
+
+func TestRenderDeterministic(t *testing.T) {
+	first, err := Render(testADRs(), Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := Render(testADRs(), Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(first) != len(second) {
+		t.Fatalf("rendered %d files, then %d", len(first), len(second))
+	}
+	for i := range first {
+		if first[i].Path != second[i].Path || !bytes.Equal(first[i].Data, second[i].Data) {
+			t.Errorf("%s differs between renders", first[i].Path)
+		}
+	}
+}
+
+func TestSupersessionChains(t *testing.T) {
+	adrs := testADRs()
+	adrs[1].Frontmatter.SupersededBy = []string{"ADR-0003"}
+	adrs[2].Frontmatter.Supersedes = []string{"ADR-0002"}
+	views, _ := buildViews(adrs)
+
+	var got [][]string
+	for _, chain := range supersessionChains(adrs, views) {
+		var ids []string
+		for _, r := range chain {
+			ids = append(ids, r.ID)
+		}
+		got = append(got, ids)
+	}
+	want := [][]string{{"ADR-0001", "ADR-0002", "ADR-0003"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("supersessionChains() = %v, want %v", got, want)
+	}
+}
+
+func TestTagURLsUnique(t *testing.T) {
+	adrs := testADRs()
+	adrs[2].Frontmatter.Tags = []string{"Database"}
+	_, tags := buildViews(adrs)
+
+	urls := make(map[string]string)
+	for _, tag := range tags {
+		if other, ok := urls[tag.URL]; ok {
+			t.Errorf("tags %q and %q share %s", other, tag.Name, tag.URL)
+		}
+		urls[tag.URL] = tag.Name
+	}
+}
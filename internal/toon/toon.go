@@ -431,3 +431,25 @@ func FromJSON(jsonData []byte) ([]byte, error) {
 	}
 	return MarshalIndent(v, "", "  ")
 }
+
+// EstimateTokens approximates the number of tokens a language model's
+// tokenizer splits data into. Runs of letters and digits count one token per
+// four characters, rounded up; any other character except whitespace counts
+// as a token of its own. It is deterministic and errs on the high side for
+// prose, which is what a budget needs.
+func EstimateTokens(data []byte) int {
+	tokens, word := 0, 0
+	for _, r := range string(data) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			word++
+			continue
+		case unicode.IsSpace(r):
+		default:
+			tokens++
+		}
+		tokens += (word + 3) / 4
+		word = 0
+	}
+	return tokens + (word+3)/4
+}
@@ -237,6 +237,27 @@ func TestStructEncoding(t *testing.T) {
 	}
 }
 
+func TestEstimateTokens(t *testing.T) {
+	tests := []struct {
+		input string
+		want  int
+	}{
+		{"", 0},
+		{"   \n\t", 0},
+		{"go", 1},
+		{"tokens", 2},
+		{"use the repository pattern", 7},
+		{"{adr_id:ADR-0001}", 9},
+		{"[a b c]", 5},
+		{"größe", 2},
+	}
+	for _, tt := range tests {
+		if got := EstimateTokens([]byte(tt.input)); got != tt.want {
+			t.Errorf("EstimateTokens(%q) = %d, want %d", tt.input, got, tt.want)
+		}
+	}
+}
+
 func contains(s, substr string) bool {
 	return len(s) >= len(substr) && (s == substr || len(s) > 0 && containsAt(s, substr))
 }